@@ -0,0 +1,51 @@
+package graphql
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// WithAbortRetries enables retrying mutations Dgraph aborts because of a
+// conflict with a concurrent transaction. The delay between attempts
+// starts at the backoff duration, doubles on every attempt, and is
+// randomized by up to half its value in either direction so conflicting
+// clients don't retry in lockstep. DQL mutations are only retried when
+// they are committed immediately, since an aborted transaction that spans
+// several calls has to be retried by the caller.
+func WithAbortRetries(maxRetries int, backoff time.Duration) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.abortRetries = maxRetries
+		gql.abortBackoff = backoff
+	}
+}
+
+// retryAborted executes the function, executing it again while it fails
+// with an aborted transaction error and attempts remain.
+func (g *GraphQL) retryAborted(ctx context.Context, f func() error) error {
+	backoff := g.abortBackoff
+	for attempt := 0; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= g.abortRetries || ErrorCode(err) != CodeAborted {
+			return err
+		}
+
+		if log := g.logger(ctx); log != nil {
+			log("transaction aborted, retrying: " + err.Error())
+		}
+
+		if sleep(ctx, jitter(backoff)) != nil {
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+// jitter returns a random duration within half of the specified duration
+// in either direction.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d)))
+}
@@ -0,0 +1,103 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestAbortRetries(t *testing.T) {
+	t.Log("Given the need to retry mutations aborted by concurrent transactions.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a graphql mutation is aborted twice.", testID)
+		{
+			var calls int
+			f := func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				if calls <= 2 {
+					io.WriteString(w, `{"errors": [{"message": "Transaction has been aborted. Please retry"}]}`)
+					return
+				}
+				io.WriteString(w, `{"data": {"addCity": {"numUids": 1}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithAbortRetries(3, time.Millisecond))
+
+			var resp struct {
+				AddCity struct {
+					NumUids int `json:"numUids"`
+				} `json:"addCity"`
+			}
+			if err := gql.Execute(context.Background(), `mutation { addCity(input: [{name: "Miami"}]) { numUids } }`, &resp); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the mutation: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the mutation.", success, testID)
+
+			if calls != 3 || resp.AddCity.NumUids != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould retry until the mutation succeeds: calls %d, %+v", failed, testID, calls, resp)
+			}
+			t.Logf("\t%s\tTest %d:\tShould retry until the mutation succeeds.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a DQL mutation keeps being aborted.", testID)
+		{
+			var calls int
+			f := func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				io.WriteString(w, `{"errors": [{"message": "Transaction has been aborted. Please retry", "extensions": {"code": "Aborted"}}]}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithAbortRetries(2, time.Millisecond))
+
+			_, err := gql.MutateDQL(context.Background(), graphql.DQLMutation{
+				SetNQuads: `_:city <name> "Miami" .`,
+				CommitNow: true,
+			})
+			if graphql.ErrorCode(err) != graphql.CodeAborted {
+				t.Fatalf("\t%s\tTest %d:\tShould get the aborted error: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the aborted error.", success, testID)
+
+			if calls != 3 {
+				t.Fatalf("\t%s\tTest %d:\tShould stop after the maximum retries: %d", failed, testID, calls)
+			}
+			t.Logf("\t%s\tTest %d:\tShould stop after the maximum retries.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a mutation fails for another reason.", testID)
+		{
+			var calls int
+			f := func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				io.WriteString(w, `{"errors": [{"message": "invalid input"}]}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithAbortRetries(3, time.Millisecond))
+
+			if err := gql.Execute(context.Background(), `mutation { addCity(input: []) { numUids } }`, nil); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get the error.", failed, testID)
+			}
+			if calls != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould not retry the mutation: %d", failed, testID, calls)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not retry the mutation.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+)
+
+// Permission represents the access a group has to a predicate. Permissions
+// are combined with bitwise or, like PermissionRead | PermissionWrite.
+type Permission int
+
+// Set of permissions a rule can grant.
+const (
+	PermissionModify Permission = 1 << iota
+	PermissionWrite
+	PermissionRead
+)
+
+// ACLRule grants a group the permission to a predicate.
+type ACLRule struct {
+	Predicate  string     `json:"predicate"`
+	Permission Permission `json:"permission"`
+}
+
+// ACLGroup represents a group and the rules for its members.
+type ACLGroup struct {
+	Name  string    `json:"name"`
+	Rules []ACLRule `json:"rules"`
+}
+
+// ACLUser represents a user and the groups they belong to.
+type ACLUser struct {
+	Name   string     `json:"name"`
+	Groups []ACLGroup `json:"groups"`
+}
+
+// groupRef references a group by name in admin mutations.
+type groupRef struct {
+	Name string `json:"name"`
+}
+
+// groupRefs converts group names into references.
+func groupRefs(names []string) []groupRef {
+	refs := make([]groupRef, len(names))
+	for i, name := range names {
+		refs[i] = groupRef{Name: name}
+	}
+	return refs
+}
+
+// nameFilter matches a user or group by name in admin mutations.
+func nameFilter(name string) map[string]interface{} {
+	return map[string]interface{}{"name": map[string]string{"eq": name}}
+}
+
+// AddUser creates a user with the password that is a member of the groups.
+func (a *Admin) AddUser(ctx context.Context, name string, password string, groups ...string) error {
+	input := struct {
+		Name     string     `json:"name"`
+		Password string     `json:"password"`
+		Groups   []groupRef `json:"groups"`
+	}{
+		Name:     name,
+		Password: password,
+		Groups:   groupRefs(groups),
+	}
+
+	const mutation = `mutation($input: [AddUserInput!]!) { addUser(input: $input) { user { name } } }`
+	return a.execute(ctx, mutation, nil, WithVariable("input", []interface{}{input}))
+}
+
+// DeleteUser deletes the user.
+func (a *Admin) DeleteUser(ctx context.Context, name string) error {
+	const mutation = `mutation($filter: UserFilter!) { deleteUser(filter: $filter) { msg numUids } }`
+	return a.deleteACL(ctx, "user", name, mutation)
+}
+
+// User returns the user and the groups they belong to.
+func (a *Admin) User(ctx context.Context, name string) (*ACLUser, error) {
+	var response struct {
+		GetUser *ACLUser `json:"getUser"`
+	}
+
+	const query = `query($name: String!) { getUser(name: $name) { name groups { name rules { predicate permission } } } }`
+	if err := a.execute(ctx, query, &response, WithVariable("name", name)); err != nil {
+		return nil, err
+	}
+
+	if response.GetUser == nil {
+		return nil, fmt.Errorf("graphql admin error: user %q doesn't exist", name)
+	}
+
+	return response.GetUser, nil
+}
+
+// AddUserToGroups makes the user a member of the groups.
+func (a *Admin) AddUserToGroups(ctx context.Context, name string, groups ...string) error {
+	return a.updateUserGroups(ctx, name, "set", groups)
+}
+
+// RemoveUserFromGroups removes the user from the groups.
+func (a *Admin) RemoveUserFromGroups(ctx context.Context, name string, groups ...string) error {
+	return a.updateUserGroups(ctx, name, "remove", groups)
+}
+
+// updateUserGroups sets or removes the group membership of the user.
+func (a *Admin) updateUserGroups(ctx context.Context, name string, action string, groups []string) error {
+	input := map[string]interface{}{
+		"filter": nameFilter(name),
+		action:   map[string]interface{}{"groups": groupRefs(groups)},
+	}
+
+	const mutation = `mutation($input: UpdateUserInput!) { updateUser(input: $input) { user { name } } }`
+	return a.execute(ctx, mutation, nil, WithVariable("input", input))
+}
+
+// AddGroup creates a group with the rules.
+func (a *Admin) AddGroup(ctx context.Context, name string, rules ...ACLRule) error {
+	input := ACLGroup{
+		Name:  name,
+		Rules: rules,
+	}
+	if input.Rules == nil {
+		input.Rules = []ACLRule{}
+	}
+
+	const mutation = `mutation($input: [AddGroupInput!]!) { addGroup(input: $input) { group { name } } }`
+	return a.execute(ctx, mutation, nil, WithVariable("input", []interface{}{input}))
+}
+
+// DeleteGroup deletes the group.
+func (a *Admin) DeleteGroup(ctx context.Context, name string) error {
+	const mutation = `mutation($filter: GroupFilter!) { deleteGroup(filter: $filter) { msg numUids } }`
+	return a.deleteACL(ctx, "group", name, mutation)
+}
+
+// Group returns the group and its rules.
+func (a *Admin) Group(ctx context.Context, name string) (*ACLGroup, error) {
+	var response struct {
+		GetGroup *ACLGroup `json:"getGroup"`
+	}
+
+	const query = `query($name: String!) { getGroup(name: $name) { name rules { predicate permission } } }`
+	if err := a.execute(ctx, query, &response, WithVariable("name", name)); err != nil {
+		return nil, err
+	}
+
+	if response.GetGroup == nil {
+		return nil, fmt.Errorf("graphql admin error: group %q doesn't exist", name)
+	}
+
+	return response.GetGroup, nil
+}
+
+// SetRules adds the rules to the group, replacing the existing rules for
+// the same predicates.
+func (a *Admin) SetRules(ctx context.Context, group string, rules ...ACLRule) error {
+	input := map[string]interface{}{
+		"filter": nameFilter(group),
+		"set":    map[string]interface{}{"rules": rules},
+	}
+
+	const mutation = `mutation($input: UpdateGroupInput!) { updateGroup(input: $input) { group { name } } }`
+	return a.execute(ctx, mutation, nil, WithVariable("input", input))
+}
+
+// RemoveRules removes the rules for the predicates from the group.
+func (a *Admin) RemoveRules(ctx context.Context, group string, predicates ...string) error {
+	input := map[string]interface{}{
+		"filter": nameFilter(group),
+		"remove": map[string]interface{}{"rules": predicates},
+	}
+
+	const mutation = `mutation($input: UpdateGroupInput!) { updateGroup(input: $input) { group { name } } }`
+	return a.execute(ctx, mutation, nil, WithVariable("input", input))
+}
+
+// deleteACL deletes the user or group and reports an error if it doesn't
+// exist.
+func (a *Admin) deleteACL(ctx context.Context, kind string, name string, mutation string) error {
+	var response map[string]struct {
+		NumUids int `json:"numUids"`
+	}
+
+	if err := a.execute(ctx, mutation, &response, WithVariable("filter", nameFilter(name))); err != nil {
+		return err
+	}
+
+	for _, result := range response {
+		if result.NumUids == 0 {
+			return fmt.Errorf("graphql admin error: %s %q doesn't exist", kind, name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestACL(t *testing.T) {
+	t.Log("Given the need to provision users, groups, and rules.")
+	{
+		var requests []adminRequest
+		f := func(w http.ResponseWriter, r *http.Request) {
+			var req adminRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			requests = append(requests, req)
+
+			switch {
+			case strings.Contains(req.Query, "deleteUser("):
+				io.WriteString(w, `{"data": {"deleteUser": {"msg": "Deleted", "numUids": 0}}}`)
+			case strings.Contains(req.Query, "getGroup("):
+				io.WriteString(w, `{"data": {"getGroup": {"name": "dev", "rules": [{"predicate": "name", "permission": 6}]}}}`)
+			default:
+				io.WriteString(w, `{"data": {}}`)
+			}
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(f))
+		defer server.Close()
+
+		admin := graphql.NewAdmin(graphql.New(server.URL))
+		ctx := context.Background()
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen creating a group with rules and a user in it.", testID)
+		{
+			if err := admin.AddGroup(ctx, "dev", graphql.ACLRule{Predicate: "name", Permission: graphql.PermissionRead | graphql.PermissionWrite}); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to add the group: %v", failed, testID, err)
+			}
+			if err := admin.AddUser(ctx, "alice", "password", "dev"); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to add the user: %v", failed, testID, err)
+			}
+			if err := admin.RemoveRules(ctx, "dev", "age"); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to remove the rules: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to provision the group and user.", success, testID)
+
+			vars, _ := json.Marshal([]map[string]interface{}{requests[0].Variables, requests[1].Variables, requests[2].Variables})
+			exp := `[{"input":[{"name":"dev","rules":[{"permission":6,"predicate":"name"}]}]},` +
+				`{"input":[{"groups":[{"name":"dev"}],"name":"alice","password":"password"}]},` +
+				`{"input":{"filter":{"name":{"eq":"dev"}},"remove":{"rules":["age"]}}}]`
+			if string(vars) != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould send the admin inputs:\n%s\n%s", failed, testID, vars, exp)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the admin inputs.", success, testID)
+
+			group, err := admin.Group(ctx, "dev")
+			if err != nil || group.Rules[0].Permission != graphql.PermissionRead|graphql.PermissionWrite {
+				t.Fatalf("\t%s\tTest %d:\tShould get the group rules: %v %+v", failed, testID, err, group)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the group rules.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen deleting a user that doesn't exist.", testID)
+		{
+			if err := admin.DeleteUser(ctx, "bob"); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get an error.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an error.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Admin provides the Dgraph administrative operations available on the
+// url/admin endpoint, several of which require an Enterprise license.
+type Admin struct {
+	gql          *GraphQL
+	pollInterval time.Duration
+}
+
+// NewAdmin constructs an Admin that uses the specified client.
+func NewAdmin(gql *GraphQL, options ...func(a *Admin)) *Admin {
+	a := Admin{
+		gql:          gql,
+		pollInterval: 5 * time.Second,
+	}
+
+	for _, option := range options {
+		option(&a)
+	}
+
+	return &a
+}
+
+// WithPollInterval sets how often the status of a background task is
+// checked while waiting for it to finish. The default is 5 seconds.
+func WithPollInterval(interval time.Duration) func(a *Admin) {
+	return func(a *Admin) {
+		a.pollInterval = interval
+	}
+}
+
+// execute performs the graphql request on the url/admin endpoint.
+func (a *Admin) execute(ctx context.Context, graphql string, response interface{}, variables ...func(m map[string]interface{})) error {
+	return a.gql.ExecuteOnEndpoint(ctx, "admin", graphql, response, variables...)
+}
+
+// =============================================================================
+
+// Set of statuses a background task can be in.
+const (
+	TaskQueued  = "Queued"
+	TaskRunning = "Running"
+	TaskFailed  = "Failed"
+	TaskSuccess = "Success"
+	TaskUnknown = "Unknown"
+)
+
+// Task represents the status of a background task like a backup or an
+// export.
+type Task struct {
+	ID          string    `json:"-"`
+	Status      string    `json:"status"`
+	Kind        string    `json:"kind"`
+	LastUpdated time.Time `json:"lastUpdated"`
+}
+
+// Done reports whether the task has finished, successfully or not.
+func (t *Task) Done() bool {
+	return t.Status == TaskSuccess || t.Status == TaskFailed
+}
+
+// Task returns the status of the background task with the specified id.
+func (a *Admin) Task(ctx context.Context, id string) (*Task, error) {
+	var response struct {
+		Task Task `json:"task"`
+	}
+
+	const query = `query($id: String!) { task(input: {id: $id}) { status kind lastUpdated } }`
+	if err := a.execute(ctx, query, &response, WithVariable("id", id)); err != nil {
+		return nil, err
+	}
+
+	response.Task.ID = id
+	return &response.Task, nil
+}
+
+// WaitTask polls the status of the background task until it finishes or
+// the context is canceled. An error is returned if the task failed.
+func (a *Admin) WaitTask(ctx context.Context, id string) (*Task, error) {
+	for {
+		task, err := a.Task(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		switch task.Status {
+		case TaskSuccess:
+			return task, nil
+		case TaskFailed:
+			return task, fmt.Errorf("graphql admin error: %s task %s failed", task.Kind, id)
+		}
+
+		if err := sleep(ctx, a.pollInterval); err != nil {
+			return task, fmt.Errorf("graphql admin error: waiting for task %s: %w", id, err)
+		}
+	}
+}
+
+// =============================================================================
+
+// Backup represents a backup in a backup location.
+type Backup struct {
+	BackupID  string        `json:"backupId"`
+	BackupNum uint64        `json:"backupNum"`
+	Encrypted bool          `json:"encrypted"`
+	Path      string        `json:"path"`
+	Since     uint64        `json:"since"`
+	ReadTs    uint64        `json:"readTs"`
+	Type      string        `json:"type"`
+	Groups    []BackupGroup `json:"groups"`
+}
+
+// BackupGroup represents the predicates of a group included in a backup.
+type BackupGroup struct {
+	GroupID    uint32   `json:"groupId"`
+	Predicates []string `json:"predicates"`
+}
+
+// RestoreRequest represents the options for restoring a backup. BackupID
+// selects the backup series in the location, the latest if empty, and
+// BackupNum restores the series up to that backup, all if zero.
+type RestoreRequest struct {
+	Location          string `json:"location"`
+	BackupID          string `json:"backupId,omitempty"`
+	BackupNum         int    `json:"backupNum,omitempty"`
+	EncryptionKeyFile string `json:"encryptionKeyFile,omitempty"`
+	AccessKey         string `json:"accessKey,omitempty"`
+	SecretKey         string `json:"secretKey,omitempty"`
+	SessionToken      string `json:"sessionToken,omitempty"`
+	Anonymous         bool   `json:"anonymous,omitempty"`
+}
+
+// Backup starts a backup of the cluster to the destination, which can be a
+// path on the Alphas or a cloud storage url like s3://bucket/folder, and
+// waits for it to finish. A full backup is taken if forceFull is true,
+// else the backup is incremental to the last backup at the destination.
+func (a *Admin) Backup(ctx context.Context, destination string, forceFull bool) (*Task, error) {
+	var response struct {
+		Backup struct {
+			TaskID string `json:"taskId"`
+		} `json:"backup"`
+	}
+
+	const mutation = `mutation($destination: String!, $forceFull: Boolean) {
+	backup(input: {destination: $destination, forceFull: $forceFull}) { response { code message } exportedFiles taskId }
+}`
+	err := a.execute(ctx, mutation, &response,
+		WithVariable("destination", destination),
+		WithVariable("forceFull", forceFull),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.WaitTask(ctx, response.Backup.TaskID)
+}
+
+// ListBackups returns the backups in the location.
+func (a *Admin) ListBackups(ctx context.Context, location string) ([]Backup, error) {
+	var response struct {
+		ListBackups []Backup `json:"listBackups"`
+	}
+
+	const query = `query($location: String!) {
+	listBackups(input: {location: $location}) {
+		backupId backupNum encrypted path since readTs type
+		groups { groupId predicates }
+	}
+}`
+	if err := a.execute(ctx, query, &response, WithVariable("location", location)); err != nil {
+		return nil, err
+	}
+
+	return response.ListBackups, nil
+}
+
+// Restore starts restoring the backup into the cluster. The restore runs in
+// the background and the cluster rejects requests until it finishes.
+func (a *Admin) Restore(ctx context.Context, req RestoreRequest) error {
+	var response struct {
+		Restore struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"restore"`
+	}
+
+	const mutation = `mutation($input: RestoreInput!) { restore(input: $input) { code message } }`
+	if err := a.execute(ctx, mutation, &response, WithVariable("input", req)); err != nil {
+		return err
+	}
+
+	if response.Restore.Code != "Success" {
+		return fmt.Errorf("graphql admin error: restore: %s", response.Restore.Message)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// Set of formats an export can be written in.
+const (
+	ExportRDF  = "rdf"
+	ExportJSON = "json"
+)
+
+// Export represents a finished export. Dgraph writes the files of the
+// export into a dgraph.r<readTs>.u<date> folder in the destination, one
+// data and one schema file per group. Files holds the paths of the files
+// as reported by the host.
+type Export struct {
+	Task        *Task
+	Format      string
+	Destination string
+	Files       []string
+}
+
+// Export starts an export of the cluster in the format, rdf or json, to the
+// destination, which can be a path on the Alphas or a cloud storage url
+// like s3://bucket/folder, and waits for it to finish.
+func (a *Admin) Export(ctx context.Context, format string, destination string) (*Export, error) {
+	var response struct {
+		Export struct {
+			TaskID        string   `json:"taskId"`
+			ExportedFiles []string `json:"exportedFiles"`
+		} `json:"export"`
+	}
+
+	const mutation = `mutation($format: String, $destination: String) {
+	export(input: {format: $format, destination: $destination}) { response { code message } exportedFiles taskId }
+}`
+	err := a.execute(ctx, mutation, &response,
+		WithVariable("format", format),
+		WithVariable("destination", destination),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := a.WaitTask(ctx, response.Export.TaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := Export{
+		Task:        task,
+		Format:      format,
+		Destination: destination,
+		Files:       response.Export.ExportedFiles,
+	}
+
+	return &export, nil
+}
@@ -0,0 +1,174 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+// adminRequest represents a request to the fake admin endpoint.
+type adminRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func TestBackup(t *testing.T) {
+	t.Log("Given the need to back up and restore a cluster.")
+	{
+		var polls int
+		var restore map[string]interface{}
+		f := func(w http.ResponseWriter, r *http.Request) {
+			var req adminRequest
+			json.NewDecoder(r.Body).Decode(&req)
+
+			switch {
+			case strings.Contains(req.Query, "backup("):
+				if req.Variables["destination"] != "s3://backups/dgraph" || req.Variables["forceFull"] != true {
+					t.Fatalf("\t%s\tShould send the backup input: %v", failed, req.Variables)
+				}
+				io.WriteString(w, `{"data": {"backup": {"response": {"code": "Success"}, "taskId": "0x1234"}}}`)
+
+			case strings.Contains(req.Query, "task("):
+				polls++
+				status := "Running"
+				if polls == 3 {
+					status = "Success"
+				}
+				io.WriteString(w, `{"data": {"task": {"status": "`+status+`", "kind": "Backup", "lastUpdated": "2023-04-01T10:00:00Z"}}}`)
+
+			case strings.Contains(req.Query, "listBackups("):
+				io.WriteString(w, `{"data": {"listBackups": [{"backupId": "quirky_kapitsa", "backupNum": 1, "path": "dgraph.20230401", "type": "full", "readTs": 42, "groups": [{"groupId": 1, "predicates": ["name"]}]}]}}`)
+
+			case strings.Contains(req.Query, "restore("):
+				restore, _ = req.Variables["input"].(map[string]interface{})
+				io.WriteString(w, `{"data": {"restore": {"code": "Success", "message": "Restore operation started."}}}`)
+			}
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(f))
+		defer server.Close()
+
+		admin := graphql.NewAdmin(graphql.New(server.URL), graphql.WithPollInterval(time.Millisecond))
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen taking a full backup.", testID)
+		{
+			task, err := admin.Backup(context.Background(), "s3://backups/dgraph", true)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to take the backup: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to take the backup.", success, testID)
+
+			if task.ID != "0x1234" || task.Status != graphql.TaskSuccess || polls != 3 {
+				t.Fatalf("\t%s\tTest %d:\tShould wait for the task to finish: %+v after %d polls", failed, testID, task, polls)
+			}
+			t.Logf("\t%s\tTest %d:\tShould wait for the task to finish.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen listing and restoring backups.", testID)
+		{
+			backups, err := admin.ListBackups(context.Background(), "s3://backups/dgraph")
+			if err != nil || len(backups) != 1 || backups[0].BackupID != "quirky_kapitsa" || backups[0].Groups[0].Predicates[0] != "name" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the backups: %v %+v", failed, testID, err, backups)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the backups.", success, testID)
+
+			err = admin.Restore(context.Background(), graphql.RestoreRequest{
+				Location: "s3://backups/dgraph",
+				BackupID: backups[0].BackupID,
+			})
+			if err != nil || restore["backupId"] != "quirky_kapitsa" {
+				t.Fatalf("\t%s\tTest %d:\tShould restore the backup: %v %v", failed, testID, err, restore)
+			}
+			t.Logf("\t%s\tTest %d:\tShould restore the backup.", success, testID)
+		}
+	}
+}
+
+func TestExport(t *testing.T) {
+	t.Log("Given the need to export a cluster.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the export fails.", testID)
+		{
+			var polls int
+			f := func(w http.ResponseWriter, r *http.Request) {
+				var req adminRequest
+				json.NewDecoder(r.Body).Decode(&req)
+
+				switch {
+				case strings.Contains(req.Query, "export("):
+					if req.Variables["format"] != graphql.ExportJSON {
+						t.Fatalf("\t%s\tTest %d:\tShould send the export format: %v", failed, testID, req.Variables)
+					}
+					io.WriteString(w, `{"data": {"export": {"response": {"code": "Success"}, "taskId": "0x99"}}}`)
+
+				case strings.Contains(req.Query, "task("):
+					polls++
+					status := "Queued"
+					if polls == 2 {
+						status = "Failed"
+					}
+					io.WriteString(w, `{"data": {"task": {"status": "`+status+`", "kind": "Export"}}}`)
+				}
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			admin := graphql.NewAdmin(graphql.New(server.URL), graphql.WithPollInterval(time.Millisecond))
+
+			_, err := admin.Export(context.Background(), graphql.ExportJSON, "/exports")
+			if err == nil || !strings.Contains(err.Error(), "Export task 0x99 failed") {
+				t.Fatalf("\t%s\tTest %d:\tShould get the task failure: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the task failure.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the export succeeds.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				var req adminRequest
+				json.NewDecoder(r.Body).Decode(&req)
+
+				switch {
+				case strings.Contains(req.Query, "export("):
+					if !strings.Contains(req.Query, "exportedFiles") {
+						t.Fatalf("\t%s\tTest %d:\tShould select the exported files: %s", failed, testID, req.Query)
+					}
+					io.WriteString(w, `{"data": {"export": {"response": {"code": "Success"}, "taskId": "0x99", "exportedFiles": ["dgraph.r10.u0101/g01.json.gz", "dgraph.r10.u0101/g01.schema.gz"]}}}`)
+
+				case strings.Contains(req.Query, "task("):
+					io.WriteString(w, `{"data": {"task": {"status": "Success", "kind": "Export"}}}`)
+				}
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			admin := graphql.NewAdmin(graphql.New(server.URL), graphql.WithPollInterval(time.Millisecond))
+
+			export, err := admin.Export(context.Background(), graphql.ExportJSON, "/exports")
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to export the cluster: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to export the cluster.", success, testID)
+
+			exp := []string{"dgraph.r10.u0101/g01.json.gz", "dgraph.r10.u0101/g01.schema.gz"}
+			if fmt.Sprint(export.Files) != fmt.Sprint(exp) {
+				t.Fatalf("\t%s\tTest %d:\tShould get the exported files: %v", failed, testID, export.Files)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the exported files.", success, testID)
+		}
+	}
+}
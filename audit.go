@@ -0,0 +1,200 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Set of audit outcomes.
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// redacted replaces the value of redacted variables in audit records.
+const redacted = "[REDACTED]"
+
+// AuditRecord represents the record written for a mutation. Fingerprint
+// is set for graphql documents, as produced by the Fingerprint function.
+type AuditRecord struct {
+	Time        time.Time              `json:"time"`
+	Endpoint    string                 `json:"endpoint"`
+	Operation   string                 `json:"operation"`
+	Fingerprint string                 `json:"fingerprint,omitempty"`
+	Variables   map[string]interface{} `json:"variables,omitempty"`
+	Caller      string                 `json:"caller,omitempty"`
+	Duration    time.Duration          `json:"duration"`
+	Outcome     string                 `json:"outcome"`
+	Error       string                 `json:"error,omitempty"`
+}
+
+// AuditSink receives the audit records. Implementations must be safe for
+// concurrent use.
+type AuditSink interface {
+	WriteAudit(record AuditRecord) error
+}
+
+// AuditConfig represents the settings for audit records.
+type AuditConfig struct {
+	sink   AuditSink
+	redact map[string]bool
+	caller func(ctx context.Context) string
+}
+
+// WithAuditRedaction replaces the value of the named variables, and of
+// fields with those names in input objects, in audit records. Names are
+// matched without regard to case.
+func WithAuditRedaction(names ...string) func(ac *AuditConfig) {
+	return func(ac *AuditConfig) {
+		for _, name := range names {
+			ac.redact[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// WithAuditCaller sets the function that identifies the caller of a
+// mutation from the context of the call, like the user of the request
+// being served.
+func WithAuditCaller(caller func(ctx context.Context) string) func(ac *AuditConfig) {
+	return func(ac *AuditConfig) {
+		ac.caller = caller
+	}
+}
+
+// WithAuditSink writes an audit record to the sink for every graphql and
+// DQL mutation, with the operation, redacted variables, caller, duration,
+// and outcome. A failure to write the record is logged when logging is
+// enabled and doesn't fail the mutation.
+func WithAuditSink(sink AuditSink, options ...func(ac *AuditConfig)) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		ac := AuditConfig{
+			sink:   sink,
+			redact: make(map[string]bool),
+		}
+		for _, option := range options {
+			option(&ac)
+		}
+		gql.audit = &ac
+	}
+}
+
+// audited executes the mutation and writes the audit record for it.
+func (g *GraphQL) audited(ctx context.Context, endpoint string, operation Operation, queryVars map[string]interface{}, send func() error) error {
+	start := time.Now()
+	err := send()
+
+	if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+
+	record := AuditRecord{
+		Time:        start.UTC(),
+		Endpoint:    endpoint,
+		Operation:   operation.String(),
+		Fingerprint: callOpts(ctx).fingerprint,
+		Duration:    time.Since(start),
+		Outcome:     AuditSuccess,
+	}
+	if err != nil {
+		record.Outcome = AuditFailure
+		record.Error = err.Error()
+	}
+	if g.audit.caller != nil {
+		record.Caller = g.audit.caller(ctx)
+	}
+
+	vars, verr := g.auditVariables(queryVars)
+	if verr != nil {
+		record.Variables = map[string]interface{}{"error": verr.Error()}
+	} else {
+		record.Variables = vars
+	}
+
+	if werr := g.audit.sink.WriteAudit(record); werr != nil {
+		if log := g.logger(ctx); log != nil {
+			log(fmt.Sprintf("%saudit: %v", labels("", operation.String()), werr))
+		}
+	}
+
+	return err
+}
+
+// auditVariables returns a copy of the variables with the redacted values
+// replaced.
+func (g *GraphQL) auditVariables(queryVars map[string]interface{}) (map[string]interface{}, error) {
+	if len(queryVars) == 0 {
+		return nil, nil
+	}
+
+	var convert func(rv reflect.Value) (interface{}, bool)
+	if g.timeFormat != "" {
+		convert = g.timeFormat.encode
+	}
+
+	tree, err := literalTree(reflect.ValueOf(queryVars), convert)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := tree.(map[string]interface{})
+	if len(g.audit.redact) > 0 {
+		redact(vars, g.audit.redact)
+	}
+	return vars, nil
+}
+
+// redact replaces the values of the named keys in the tree.
+func redact(v interface{}, names map[string]bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if names[strings.ToLower(key)] {
+				v[key] = redacted
+				continue
+			}
+			redact(value, names)
+		}
+
+	case []interface{}:
+		for _, item := range v {
+			redact(item, names)
+		}
+	}
+}
+
+// =============================================================================
+
+// JSONAuditSink writes audit records to a writer as JSON, one record per
+// line.
+type JSONAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONAuditSink constructs a sink that writes to the specified writer.
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{w: w}
+}
+
+// WriteAudit implements the AuditSink interface.
+func (s *JSONAuditSink) WriteAudit(record AuditRecord) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("graphql audit error: %w", err)
+	}
+	b = append(b, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write(b); err != nil {
+		return fmt.Errorf("graphql audit error: %w", err)
+	}
+	return nil
+}
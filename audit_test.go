@@ -0,0 +1,104 @@
+package graphql_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAuditSink(t *testing.T) {
+	t.Log("Given the need to keep an audit log of mutations.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen mutations and queries are executed.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				if strings.Contains(string(b), "Tampa") {
+					io.WriteString(w, `{"errors": [{"message": "city exists"}]}`)
+					return
+				}
+				io.WriteString(w, `{"data": {"addUser": {"numUids": 1}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			type userKey struct{}
+			var log bytes.Buffer
+			gql := graphql.New(server.URL,
+				graphql.WithAuditSink(graphql.NewJSONAuditSink(&log),
+					graphql.WithAuditRedaction("password"),
+					graphql.WithAuditCaller(func(ctx context.Context) string {
+						s, _ := ctx.Value(userKey{}).(string)
+						return s
+					}),
+				),
+			)
+
+			type input struct {
+				Name     string `json:"name"`
+				Password string `json:"password"`
+			}
+
+			ctx := context.WithValue(context.Background(), userKey{}, "admin")
+			err := gql.Execute(ctx, `mutation AddUser($input: AddUserInput!) { addUser(input: [$input]) { numUids } }`, nil,
+				graphql.WithVariable("input", input{Name: "bill", Password: "secret"}),
+			)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the mutation: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the mutation.", success, testID)
+
+			if err := gql.Execute(ctx, `query { getUser(name: "bill") { name } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+
+			err = gql.Execute(ctx, `mutation { addCity(input: [{name: "Tampa"}]) { numUids } }`, nil)
+			if err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get the error for the failed mutation.", failed, testID)
+			}
+
+			lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould write a record for each mutation only: %s", failed, testID, log.String())
+			}
+			t.Logf("\t%s\tTest %d:\tShould write a record for each mutation only.", success, testID)
+
+			var records [2]graphql.AuditRecord
+			for i, line := range lines {
+				if err := json.Unmarshal([]byte(line), &records[i]); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould write the records as JSON: %v", failed, testID, err)
+				}
+			}
+
+			got := records[0]
+			exp := map[string]interface{}{
+				"input": map[string]interface{}{"name": "bill", "password": "[REDACTED]"},
+			}
+			if diff := cmp.Diff(got.Variables, exp); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould redact the variables. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould redact the variables.", success, testID)
+
+			fingerprint := graphql.Fingerprint(`mutation AddUser($input: AddUserInput!) { addUser(input: [$input]) { numUids } }`)
+			if got.Operation != "mutation AddUser" || got.Fingerprint != fingerprint || got.Caller != "admin" || got.Outcome != graphql.AuditSuccess || got.Endpoint != "graphql" || got.Time.IsZero() {
+				t.Fatalf("\t%s\tTest %d:\tShould record the mutation: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould record the mutation.", success, testID)
+
+			if got := records[1]; got.Outcome != graphql.AuditFailure || !strings.Contains(got.Error, "city exists") {
+				t.Fatalf("\t%s\tTest %d:\tShould record the failure: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould record the failure.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package graphql
+
+import (
+	"encoding/base64"
+	"strings"
+)
+
+// WithBasicAuth sends the user and password with every request using HTTP
+// basic authentication. It sets the Authorization header like WithHeader
+// does, so the last of them applied wins.
+func WithBasicAuth(user string, password string) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		credentials := base64.StdEncoding.EncodeToString([]byte(user + ":" + password))
+		gql.headers["Authorization"] = "Basic " + credentials
+	}
+}
+
+// WithBearerToken sends the token with every request in the Authorization
+// header as a bearer token, the way WithBasicAuth sets the header. The
+// token can be provided with or without the Bearer prefix.
+func WithBearerToken(token string) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		token = strings.TrimSpace(token)
+		if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+			token = strings.TrimSpace(token[7:])
+		}
+		gql.headers["Authorization"] = "Bearer " + token
+	}
+}
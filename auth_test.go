@@ -0,0 +1,56 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestAuth(t *testing.T) {
+	var got string
+	var user, password string
+	var basic bool
+	f := func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Authorization")
+		user, password, basic = r.BasicAuth()
+		io.WriteString(w, `{"data": {}}`)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(f))
+	defer server.Close()
+
+	t.Log("Given the need to authenticate requests.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen using basic authentication.", testID)
+		{
+			gql := graphql.New(server.URL, graphql.WithBasicAuth("bill", "p@ss:word"))
+			if err := gql.Execute(context.Background(), `query { name }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if !basic || user != "bill" || password != "p@ss:word" {
+				t.Fatalf("\t%s\tTest %d:\tShould send the credentials: %q", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the credentials.", success, testID)
+		}
+
+		for _, token := range []string{"abc123", "Bearer abc123", "bearer  abc123 "} {
+			testID++
+			t.Logf("\tTest %d:\tWhen using the bearer token %q.", testID, token)
+			{
+				gql := graphql.New(server.URL, graphql.WithBearerToken(token))
+				if err := gql.Execute(context.Background(), `query { name }`, nil); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+				if got != "Bearer abc123" {
+					t.Fatalf("\t%s\tTest %d:\tShould send the token: %q", failed, testID, got)
+				}
+				t.Logf("\t%s\tTest %d:\tShould send the token.", success, testID)
+			}
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package graphql
+
+import "strings"
+
+// WithDgraphAuthToken sets the token for a Dgraph host started with the
+// --security token flag. The token is sent in the X-Dgraph-AuthToken
+// header on admin operations, which are the requests made to the
+// url/admin and url/alter endpoints.
+func WithDgraphAuthToken(token string) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.authToken = token
+	}
+}
+
+// isAdminEndpoint reports whether the endpoint is one of the Dgraph admin
+// endpoints that require the auth token.
+func isAdminEndpoint(endpoint string) bool {
+	if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+	return endpoint == "admin" || strings.HasPrefix(endpoint, "admin/") || endpoint == "alter"
+}
@@ -0,0 +1,49 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestDgraphAuthToken(t *testing.T) {
+	t.Log("Given the need to access a Dgraph host started with a security token.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen executing admin and graphql operations.", testID)
+		{
+			headers := make(map[string]string)
+			f := func(w http.ResponseWriter, r *http.Request) {
+				headers[r.URL.Path] = r.Header.Get("X-Dgraph-AuthToken")
+				io.WriteString(w, `{"data": {}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithDgraphAuthToken("secret"))
+
+			if err := gql.ExecuteOnEndpoint(context.Background(), "admin", `query { health { status } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the admin query: %v", failed, testID, err)
+			}
+			if err := gql.Execute(context.Background(), `query { __typename }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the operations.", success, testID)
+
+			if headers["/admin"] != "secret" {
+				t.Fatalf("\t%s\tTest %d:\tShould send the token on admin operations.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the token on admin operations.", success, testID)
+
+			if headers["/graphql"] != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould not send the token on graphql operations.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not send the token on graphql operations.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package graphql
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// BodyDigest computes the digest of a serialized request body in the form
+// the gateway expects in the header.
+type BodyDigest func(body []byte) string
+
+// SHA256Digest returns the hex encoded SHA-256 digest of the body.
+func SHA256Digest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// HMACSHA256Digest returns a digest that produces the hex encoded HMAC
+// SHA-256 of the body using the specified key.
+func HMACSHA256Digest(key []byte) BodyDigest {
+	return func(body []byte) string {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(body)
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+}
+
+// ContentDigest returns the digest in the RFC 9530 Content-Digest format,
+// sha-256=:base64:, for use with the Content-Digest header.
+func ContentDigest(body []byte) string {
+	sum := sha256.Sum256(body)
+	return "sha-256=:" + base64.StdEncoding.EncodeToString(sum[:]) + ":"
+}
+
+// bodyHash represents the header that carries the digest of the body.
+type bodyHash struct {
+	header string
+	digest BodyDigest
+}
+
+// WithBodyHash computes the digest of the serialized body of every request
+// and sets it in the specified header, for gateways that verify payload
+// integrity. The digest covers the body as sent, after compression, and is
+// set before a signer set with WithRequestSigner runs so the signature can
+// cover the header.
+func WithBodyHash(header string, digest BodyDigest) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.bodyHash = &bodyHash{
+			header: header,
+			digest: digest,
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package graphql_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestBodyHash(t *testing.T) {
+	t.Log("Given the need to prove the integrity of request bodies.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the gateway verifies an HMAC of the body.", testID)
+		{
+			key := []byte("secret")
+
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+
+				mac := hmac.New(sha256.New, key)
+				mac.Write(b)
+				if exp := hex.EncodeToString(mac.Sum(nil)); r.Header.Get("X-Body-Hmac") != exp {
+					t.Fatalf("\t%s\tTest %d:\tShould get the digest of the body: %q", failed, testID, r.Header.Get("X-Body-Hmac"))
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the digest of the body.", success, testID)
+
+				if r.Header.Get("X-Signed") != r.Header.Get("X-Body-Hmac") {
+					t.Fatalf("\t%s\tTest %d:\tShould set the digest before signing.", failed, testID)
+				}
+				t.Logf("\t%s\tTest %d:\tShould set the digest before signing.", success, testID)
+
+				io.WriteString(w, `{"data": {"city": {"name": "Miami"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			signer := func(ctx context.Context, req *graphql.Request, body []byte) error {
+				req.Header.Set("X-Signed", req.Header.Get("X-Body-Hmac"))
+				return nil
+			}
+
+			gql := graphql.New(server.URL,
+				graphql.WithBodyHash("X-Body-Hmac", graphql.HMACSHA256Digest(key)),
+				graphql.WithRequestSigner(signer),
+			)
+
+			if err := gql.Execute(context.Background(), `query { city { name } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen producing the standard digests.", testID)
+		{
+			body := []byte("hello")
+
+			if got := graphql.SHA256Digest(body); got != "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824" {
+				t.Fatalf("\t%s\tTest %d:\tShould produce the SHA-256 digest: %s", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould produce the SHA-256 digest.", success, testID)
+
+			if got := graphql.ContentDigest(body); got != "sha-256=:LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=:" {
+				t.Fatalf("\t%s\tTest %d:\tShould produce the Content-Digest value: %s", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould produce the Content-Digest value.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// BodyTransformer transforms the bodies of graphql requests and responses,
+// like encrypting fields or wrapping the payload in the envelope a gateway
+// requires. EncodeBody receives the encoded request before it's compressed
+// and sent, and DecodeBody receives the body of a successful response
+// before it's decoded. Implementations must be safe for concurrent use.
+type BodyTransformer interface {
+	EncodeBody(ctx context.Context, body []byte) ([]byte, error)
+	DecodeBody(ctx context.Context, body []byte) ([]byte, error)
+}
+
+// WithBodyTransformer applies the transformer to the bodies of the graphql
+// requests and responses. Logs and errors show the request and response
+// as they are before encoding and after decoding.
+func WithBodyTransformer(transformer BodyTransformer) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.bodyTransformer = transformer
+	}
+}
+
+// encodeBody applies the transformer to the request body.
+func (g *GraphQL) encodeBody(ctx context.Context, r io.Reader) (io.Reader, error) {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("graphql read request error: %w", err)
+	}
+
+	body, err = g.bodyTransformer.EncodeBody(ctx, body)
+	if err != nil {
+		return nil, fmt.Errorf("graphql body transform error: %w", err)
+	}
+
+	return bytes.NewReader(body), nil
+}
+
+// decodeBody applies the transformer to the response body.
+func (g *GraphQL) decodeBody(ctx context.Context, data []byte) ([]byte, error) {
+	data, err := g.bodyTransformer.DecodeBody(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("graphql body transform error: %w", err)
+	}
+	return data, nil
+}
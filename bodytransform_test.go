@@ -0,0 +1,84 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+// envelope wraps bodies in a base64 encoded payload field.
+type envelope struct{}
+
+func (envelope) EncodeBody(ctx context.Context, body []byte) ([]byte, error) {
+	return json.Marshal(map[string]string{"payload": base64.StdEncoding.EncodeToString(body)})
+}
+
+func (envelope) DecodeBody(ctx context.Context, body []byte) ([]byte, error) {
+	var env struct {
+		Payload string `json:"payload"`
+	}
+	if err := json.Unmarshal(body, &env); err != nil {
+		return nil, err
+	}
+	if env.Payload == "" {
+		return nil, errors.New("missing payload")
+	}
+	return base64.StdEncoding.DecodeString(env.Payload)
+}
+
+func TestBodyTransformer(t *testing.T) {
+	t.Log("Given the need to wrap bodies in an envelope.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the host requires an envelope.", testID)
+		{
+			var sent string
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				body, err := envelope{}.DecodeBody(r.Context(), b)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				sent = string(body)
+
+				if strings.Contains(sent, "plain") {
+					io.WriteString(w, `{"data": {"name": "Miami"}}`)
+					return
+				}
+				resp, _ := envelope{}.EncodeBody(r.Context(), []byte(`{"data": {"name": "Miami"}}`))
+				w.Write(resp)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithBodyTransformer(envelope{}))
+
+			var got struct {
+				Name string `json:"name"`
+			}
+			if err := gql.Execute(context.Background(), `query { name }`, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if !strings.Contains(sent, `"query":"query { name }"`) || got.Name != "Miami" {
+				t.Fatalf("\t%s\tTest %d:\tShould transform the request and response: %s %q", failed, testID, sent, got.Name)
+			}
+			t.Logf("\t%s\tTest %d:\tShould transform the request and response.", success, testID)
+
+			err := gql.Execute(context.Background(), `query { plain }`, &got)
+			if err == nil || !strings.Contains(err.Error(), "graphql body transform error") {
+				t.Fatalf("\t%s\tTest %d:\tShould fail for a response without an envelope: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould fail for a response without an envelope.", success, testID)
+		}
+	}
+}
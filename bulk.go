@@ -0,0 +1,124 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+)
+
+// BulkConfig holds the settings for a bulk mutation.
+type BulkConfig struct {
+	chunkSize int
+	workers   int
+	variable  string
+	progress  func(done int, total int)
+}
+
+// WithBulkChunkSize sets the number of inputs sent in each mutation. The
+// default is 1000.
+func WithBulkChunkSize(size int) func(bc *BulkConfig) {
+	return func(bc *BulkConfig) {
+		if size > 0 {
+			bc.chunkSize = size
+		}
+	}
+}
+
+// WithBulkWorkers sets the number of chunks that can be executed
+// concurrently. The default is 4.
+func WithBulkWorkers(workers int) func(bc *BulkConfig) {
+	return func(bc *BulkConfig) {
+		if workers > 0 {
+			bc.workers = workers
+		}
+	}
+}
+
+// WithBulkVariable sets the name of the variable the mutation uses for the
+// slice of inputs. The default is input.
+func WithBulkVariable(name string) func(bc *BulkConfig) {
+	return func(bc *BulkConfig) {
+		if name != "" {
+			bc.variable = name
+		}
+	}
+}
+
+// WithBulkProgress accepts a function that is called each time a chunk
+// completes with the number of inputs processed so far and the total
+// number of inputs. The function is never called concurrently.
+func WithBulkProgress(fn func(done int, total int)) func(bc *BulkConfig) {
+	return func(bc *BulkConfig) {
+		bc.progress = fn
+	}
+}
+
+// BulkMutate splits the inputs into chunks and executes the mutation
+// against the url/graphql endpoint for each chunk, using a bounded number
+// of workers. The mutation receives each chunk through the configured
+// variable. If any chunks fail, a *MultiError is returned where each
+// failure is indexed by the position of the chunk. Chunks that haven't
+// started when the context is canceled are reported with the context
+// error.
+func BulkMutate[T any](ctx context.Context, gql *GraphQL, graphql string, inputs []T, options ...func(bc *BulkConfig)) error {
+	bc := BulkConfig{
+		chunkSize: 1000,
+		workers:   4,
+		variable:  "input",
+	}
+	for _, option := range options {
+		option(&bc)
+	}
+
+	var chunks [][]T
+	for start := 0; start < len(inputs); start += bc.chunkSize {
+		end := start + bc.chunkSize
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+		chunks = append(chunks, inputs[start:end])
+	}
+
+	merr := newMultiError(len(chunks))
+
+	var mu sync.Mutex
+	var done int
+	complete := func(index int, size int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			merr.add(index, err)
+		}
+
+		done += size
+		if bc.progress != nil {
+			bc.progress(done, len(inputs))
+		}
+	}
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(bc.workers)
+	for i := 0; i < bc.workers; i++ {
+		go func() {
+			defer wg.Done()
+			for index := range work {
+				queryVars := map[string]interface{}{bc.variable: chunks[index]}
+				err := gql.query(ctx, gql.graphqlPath, graphql, queryVars, nil)
+				complete(index, len(chunks[index]), err)
+			}
+		}()
+	}
+
+	for index := range chunks {
+		select {
+		case work <- index:
+		case <-ctx.Done():
+			complete(index, len(chunks[index]), ctx.Err())
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return merr.errOrNil()
+}
@@ -0,0 +1,86 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestBulkMutate(t *testing.T) {
+	t.Log("Given the need to execute a mutation over a large set of inputs.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen one of the chunks fails.", testID)
+		{
+			var calls int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+
+				var req struct {
+					Variables struct {
+						Users []struct {
+							Name string
+						}
+					}
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+
+				if req.Variables.Users[0].Name == "user-4" {
+					io.WriteString(w, `{"errors": [{"message": "bad chunk"}]}`)
+					return
+				}
+				io.WriteString(w, `{"data": {"addUser": {"numUids": 2}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			type user struct {
+				Name string `json:"name"`
+			}
+			var users []user
+			for i := 0; i < 7; i++ {
+				users = append(users, user{Name: "user-" + string(rune('0'+i))})
+			}
+
+			var progress []int
+			gql := graphql.New(server.URL)
+			err := graphql.BulkMutate(context.Background(), gql, `mutation($users: [AddUserInput!]!) { addUser(input: $users) { numUids } }`, users,
+				graphql.WithBulkChunkSize(2),
+				graphql.WithBulkWorkers(2),
+				graphql.WithBulkVariable("users"),
+				graphql.WithBulkProgress(func(done int, total int) {
+					progress = append(progress, done)
+				}),
+			)
+
+			var me *graphql.MultiError
+			if !errors.As(err, &me) {
+				t.Fatalf("\t%s\tTest %d:\tShould get a multi error: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get a multi error.", success, testID)
+
+			if me.Total != 4 || me.Failed() != 1 || me.ErrorAt(2) == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get the failed chunk: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the failed chunk.", success, testID)
+
+			if atomic.LoadInt32(&calls) != 4 {
+				t.Fatalf("\t%s\tTest %d:\tShould execute every chunk: %d", failed, testID, calls)
+			}
+			t.Logf("\t%s\tTest %d:\tShould execute every chunk.", success, testID)
+
+			if len(progress) != 4 || progress[3] != 7 {
+				t.Fatalf("\t%s\tTest %d:\tShould report progress: %v", failed, testID, progress)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report progress.", success, testID)
+		}
+	}
+}
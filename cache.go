@@ -0,0 +1,195 @@
+package graphql
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// Cache represents a store for query responses. Implementations can be
+// backed by memory, an LRU, or a shared store like Redis and must be safe
+// for concurrent use.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte, ttl time.Duration)
+	Delete(key string)
+}
+
+// WithCache enables caching the responses of queries in the specified cache
+// for the specified ttl. Mutations are never cached.
+func WithCache(cache Cache, ttl time.Duration) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.cache = cache
+		gql.cacheTTL = ttl
+	}
+}
+
+// CacheTTL returns a copy of the context that overrides the cache ttl for
+// the call made with it.
+func CacheTTL(ctx context.Context, ttl time.Duration) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.cacheTTL = ttl
+	})
+}
+
+// NoCache returns a copy of the context that bypasses the cache for the
+// call made with it. The response is neither read from nor stored in the
+// cache.
+func NoCache(ctx context.Context) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.noCache = true
+	})
+}
+
+// Invalidate removes the cached response for the specified query and
+// variables executed against the url/graphql endpoint.
+func (g *GraphQL) Invalidate(graphql string, variables ...func(m map[string]interface{})) error {
+	return g.InvalidateOnEndpoint(g.graphqlPath, graphql, variables...)
+}
+
+// InvalidateOnEndpoint removes the cached response for the specified query
+// and variables executed against the url/endpoint.
+func (g *GraphQL) InvalidateOnEndpoint(endpoint string, graphql string, variables ...func(m map[string]interface{})) error {
+	if g.cache == nil {
+		return nil
+	}
+
+	var queryVars map[string]interface{}
+	if len(variables) > 0 {
+		queryVars = make(map[string]interface{})
+		for _, variable := range variables {
+			variable(queryVars)
+		}
+	}
+
+	queryVars, err := g.formatVariables(queryVars)
+	if err != nil {
+		return err
+	}
+
+	key, err := queryCacheKey(endpoint, graphql, queryVars)
+	if err != nil {
+		return err
+	}
+
+	g.cache.Delete(key)
+	return nil
+}
+
+// cachedRequest returns the cached response for the request if one exists,
+// else it executes the request and caches the response.
+func (g *GraphQL) cachedRequest(ctx context.Context, key string, endpoint string, body []byte, response interface{}) error {
+
+	data, ok := g.cache.Get(key)
+	if !ok {
+		var raw json.RawMessage
+		if err := g.execute(ctx, endpoint, body, true, &raw); err != nil {
+			return err
+		}
+
+		ttl := g.cacheTTL
+		if opts := callOpts(ctx); opts.cacheTTL > 0 {
+			ttl = opts.cacheTTL
+		}
+
+		data = raw
+		g.cache.Set(key, data, ttl)
+	}
+
+	return decodeData(data, response, g.decodeHooksFor(ctx), g.errorBodyLimit)
+}
+
+// queryCacheKey produces the key for the query and variables on the
+// endpoint. The query is normalized so formatting differences don't
+// produce different keys. The fingerprint isn't used since it masks the
+// literals, so queries that inline different values would share a key and
+// get each other's responses.
+func queryCacheKey(endpoint string, graphql string, queryVars map[string]interface{}) (string, error) {
+	b, err := encodeQuery(NormalizeQuery(graphql), queryVars)
+	if err != nil {
+		return "", err
+	}
+	return cacheKey(endpoint, b), nil
+}
+
+// cacheKey produces the key for the encoded request on the endpoint. Since
+// variables are encoded with their keys sorted, the same query and variables
+// produce the same key.
+func cacheKey(endpoint string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(endpoint))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// =============================================================================
+
+// MemoryCache is an in-memory implementation of the Cache interface. Expired
+// entries are removed when they are accessed.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+// memoryEntry represents a cached value and when it expires.
+type memoryEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache constructs an empty in-memory cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Get returns the value for the key if it exists and hasn't expired.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+// Set stores the value for the key for the specified ttl.
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = memoryEntry{
+		value:   value,
+		expires: time.Now().Add(ttl),
+	}
+}
+
+// Delete removes the value for the key.
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
+
+// Len returns the number of entries in the cache, including entries that
+// have expired but haven't been removed yet.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
@@ -0,0 +1,86 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestCache(t *testing.T) {
+	type response struct {
+		Name string `json:"name"`
+	}
+
+	t.Log("Given the need to cache query responses.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen executing the same query more than once.", testID)
+		{
+			var calls int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				io.WriteString(w, `{"data": {"name": "cached"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithCache(graphql.NewMemoryCache(), time.Minute))
+			ctx := context.Background()
+			query := `query { name }`
+			vars := graphql.WithVariable("id", "0x01")
+
+			for i := 0; i < 2; i++ {
+				var got response
+				if err := gql.Execute(ctx, query, &got, vars); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+				if got.Name != "cached" {
+					t.Fatalf("\t%s\tTest %d:\tShould get the expected result: %q", failed, testID, got.Name)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query twice.", success, testID)
+
+			if n := atomic.LoadInt32(&calls); n != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould only call the host once: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould only call the host once.", success, testID)
+
+			var got response
+			if err := gql.Execute(graphql.NoCache(ctx), query, &got, vars); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to bypass the cache: %v", failed, testID, err)
+			}
+			if n := atomic.LoadInt32(&calls); n != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould call the host when bypassing the cache: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould call the host when bypassing the cache.", success, testID)
+
+			if err := gql.Invalidate(query, vars); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to invalidate the query: %v", failed, testID, err)
+			}
+			if err := gql.Execute(ctx, query, &got, vars); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if n := atomic.LoadInt32(&calls); n != 3 {
+				t.Fatalf("\t%s\tTest %d:\tShould call the host after invalidation: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould call the host after invalidation.", success, testID)
+
+			for i := 0; i < 2; i++ {
+				if err := gql.Execute(ctx, `mutation { name }`, &got); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the mutation: %v", failed, testID, err)
+				}
+			}
+			if n := atomic.LoadInt32(&calls); n != 5 {
+				t.Fatalf("\t%s\tTest %d:\tShould never cache mutations: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould never cache mutations.", success, testID)
+		}
+	}
+}
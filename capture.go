@@ -0,0 +1,163 @@
+package graphql
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxStreamCapture is the number of bytes captured from a request body
+// that can only be read once. Larger requests are truncated in log
+// messages and errors.
+const maxStreamCapture = 64 * 1024
+
+// WithoutRequestCapture stops the client from capturing request bodies, so
+// a streamed request isn't copied while it's sent. Use it on hot paths that
+// don't need the request in log messages or errors, which omit it.
+func WithoutRequestCapture() func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.noCapture = true
+	}
+}
+
+// requestCapture provides the request body for log messages and errors.
+// Bodies that are already in memory aren't copied unless the request is
+// needed, and streamed bodies are only captured up to a limit.
+type requestCapture struct {
+	data    []byte
+	at      io.ReaderAt
+	offset  int64
+	size    int64
+	stream  *limitedBuffer
+	omitted bool
+}
+
+// captureRequest prepares the capture of the request body and returns the
+// reader to send. Bodies backed by a bytes.Buffer, bytes.Reader or
+// strings.Reader are sent as readers the http package knows the length of,
+// so ContentLength and GetBody are set on the request.
+func captureRequest(r io.Reader) (*requestCapture, io.Reader) {
+	switch src := r.(type) {
+	case *bytes.Buffer:
+		data := src.Bytes()
+		return &requestCapture{data: data}, bytes.NewReader(data)
+
+	case *bytes.Reader:
+		return &requestCapture{at: src, offset: src.Size() - int64(src.Len()), size: src.Size()}, src
+
+	case *strings.Reader:
+		return &requestCapture{at: src, offset: src.Size() - int64(src.Len()), size: src.Size()}, src
+	}
+
+	lb := limitedBuffer{max: maxStreamCapture}
+	return &requestCapture{stream: &lb}, io.TeeReader(r, &lb)
+}
+
+// countRequest prepares a capture that only counts the bytes of the request
+// body, so a streamed body isn't copied. The body is omitted from log
+// messages and errors.
+func countRequest(r io.Reader) (*requestCapture, io.Reader) {
+	switch r.(type) {
+	case *bytes.Buffer, *bytes.Reader, *strings.Reader:
+		rc, r := captureRequest(r)
+		rc.omitted = true
+		return rc, r
+	}
+
+	var lb limitedBuffer
+	return &requestCapture{stream: &lb, omitted: true}, io.TeeReader(r, &lb)
+}
+
+// String returns the captured request body.
+func (rc *requestCapture) String() string {
+	switch {
+	case rc.omitted:
+		return ""
+
+	case rc.data != nil:
+		return string(rc.data)
+
+	case rc.at != nil:
+		b := make([]byte, rc.size-rc.offset)
+		n, _ := rc.at.ReadAt(b, rc.offset)
+		return string(b[:n])
+	}
+
+	return rc.stream.String()
+}
+
+// Excerpt returns the captured request body truncated to the limit, noting
+// how many bytes were left out. Only the bytes kept are copied. A limit of
+// 0 returns the whole body.
+func (rc *requestCapture) Excerpt(limit int) string {
+	n := rc.Len()
+	if rc.omitted || limit <= 0 || n <= limit {
+		return rc.String()
+	}
+
+	var b []byte
+	switch {
+	case rc.data != nil:
+		b = rc.data[:limit]
+
+	case rc.at != nil:
+		b = make([]byte, limit)
+		m, _ := rc.at.ReadAt(b, rc.offset)
+		b = b[:m]
+
+	default:
+		b = rc.stream.buf.Bytes()
+		if len(b) > limit {
+			b = b[:limit]
+		}
+	}
+
+	return fmt.Sprintf("%s...(%d more bytes)", b, n-len(b))
+}
+
+// Len returns the number of bytes in the request body. For streamed bodies
+// this is the number of bytes sent so far.
+func (rc *requestCapture) Len() int {
+	switch {
+	case rc.data != nil:
+		return len(rc.data)
+
+	case rc.at != nil:
+		return int(rc.size - rc.offset)
+	}
+
+	return rc.stream.buf.Len() + rc.stream.dropped
+}
+
+// =============================================================================
+
+// limitedBuffer captures up to max bytes and counts the bytes it drops.
+type limitedBuffer struct {
+	buf     bytes.Buffer
+	max     int
+	dropped int
+}
+
+// Write implements the io.Writer interface. It never fails so the request
+// being captured is unaffected.
+func (lb *limitedBuffer) Write(p []byte) (int, error) {
+	n := len(p)
+	if room := lb.max - lb.buf.Len(); room < len(p) {
+		if room < 0 {
+			room = 0
+		}
+		lb.dropped += len(p) - room
+		p = p[:room]
+	}
+	lb.buf.Write(p)
+	return n, nil
+}
+
+// String returns the captured bytes, noting how many were dropped.
+func (lb *limitedBuffer) String() string {
+	if lb.dropped > 0 {
+		return fmt.Sprintf("%s...(%d more bytes)", lb.buf.String(), lb.dropped)
+	}
+	return lb.buf.String()
+}
@@ -0,0 +1,112 @@
+package graphql_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+// streamReader hides the concrete type of the reader so the request body
+// can only be streamed.
+type streamReader struct {
+	io.Reader
+}
+
+func TestRequestCapture(t *testing.T) {
+	t.Log("Given the need to send requests without buffering them twice.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the host redirects the request.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/old" {
+					http.Redirect(w, r, "/graphql", http.StatusTemporaryRedirect)
+					return
+				}
+
+				b, _ := ioutil.ReadAll(r.Body)
+				if r.ContentLength != int64(len(b)) || len(b) == 0 {
+					t.Fatalf("\t%s\tTest %d:\tShould get the content length: %d", failed, testID, r.ContentLength)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the content length.", success, testID)
+
+				io.WriteString(w, `{"data": {"__typename": "Query"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			body := bytes.NewBufferString(`{"query": "query { __typename }"}`)
+			if err := gql.RawRequest(context.Background(), "old", body, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to follow the redirect: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to follow the redirect.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a large streamed request fails.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(ioutil.Discard, r.Body)
+				io.WriteString(w, `{"errors": [{"message": "too large"}]}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithErrorBodyLimit(0))
+
+			body := streamReader{strings.NewReader(strings.Repeat("a", 100*1024))}
+			err := gql.RawRequest(context.Background(), "graphql", body, nil)
+			if err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get the error.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the error.", success, testID)
+
+			if !strings.Contains(err.Error(), "...(36864 more bytes)") || len(err.Error()) > 70*1024 {
+				t.Fatalf("\t%s\tTest %d:\tShould truncate the captured request.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould truncate the captured request.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen request capture is disabled.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(ioutil.Discard, r.Body)
+				io.WriteString(w, `{"errors": [{"message": "failed"}]}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithoutRequestCapture())
+
+			body := streamReader{strings.NewReader(`{"query": "query { secret }"}`)}
+			err := gql.RawRequest(context.Background(), "graphql", body, nil)
+			if err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get the error.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the error.", success, testID)
+
+			if strings.Contains(err.Error(), "secret") {
+				t.Fatalf("\t%s\tTest %d:\tShould omit the request from the error: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould omit the request from the error.", success, testID)
+
+			if stats := gql.Stats(); stats.BytesSent != 29 {
+				t.Fatalf("\t%s\tTest %d:\tShould still count the bytes sent: %d", failed, testID, stats.BytesSent)
+			}
+			t.Logf("\t%s\tTest %d:\tShould still count the bytes sent.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package graphql
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors matched by the HTTPError and GraphQLError values of
+// requests that failed for a well-known reason. Use errors.Is to check for
+// them instead of matching messages.
+var (
+	ErrUnauthorized = errors.New("graphql unauthorized error")
+	ErrForbidden    = errors.New("graphql forbidden error")
+	ErrNotFound     = errors.New("graphql not found error")
+	ErrValidation   = errors.New("graphql validation error")
+)
+
+// ErrorClassifier maps the *HTTPError or *GraphQLError of a failed request
+// to the error it should match with errors.Is, usually one of the sentinel
+// errors. It returns nil when the error isn't recognized.
+type ErrorClassifier func(err error) error
+
+// WithErrorClassifier replaces the DefaultErrorClassifier used to classify
+// failed requests. Call DefaultErrorClassifier from the classifier to
+// extend the default patterns instead of replacing them.
+func WithErrorClassifier(classifier ErrorClassifier) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.classifier = classifier
+	}
+}
+
+// classCodes maps the error codes reported by Apollo, Hasura and similar
+// hosts to the sentinel errors.
+var classCodes = map[string]error{
+	"UNAUTHENTICATED":             ErrUnauthorized,
+	CodeHasuraInvalidJWT:          ErrUnauthorized,
+	"FORBIDDEN":                   ErrForbidden,
+	CodeHasuraAccessDenied:        ErrForbidden,
+	CodeHasuraPermissionError:     ErrForbidden,
+	"NOT_FOUND":                   ErrNotFound,
+	"GRAPHQL_PARSE_FAILED":        ErrValidation,
+	"GRAPHQL_VALIDATION_FAILED":   ErrValidation,
+	"BAD_USER_INPUT":              ErrValidation,
+	CodeHasuraValidationFailed:    ErrValidation,
+	CodeHasuraConstraintViolation: ErrValidation,
+	CodeHasuraDataException:       ErrValidation,
+}
+
+// classMessages maps fragments of the messages Dgraph reports, which don't
+// come with a code, to the sentinel errors.
+var classMessages = []struct {
+	fragment string
+	err      error
+}{
+	{"Token is expired", ErrUnauthorized},
+	{"no accessJwt available", ErrUnauthorized},
+	{"unauthorized ip address", ErrUnauthorized},
+	{"PermissionDenied", ErrForbidden},
+	{"Unauthorized to", ErrForbidden},
+	{"no nodes found", ErrNotFound},
+	{"Cannot query field", ErrValidation},
+	{"Unknown argument", ErrValidation},
+	{"Unknown type", ErrValidation},
+	{"Expected type", ErrValidation},
+	{"Syntax Error", ErrValidation},
+}
+
+// DefaultErrorClassifier classifies 401, 403, 404, 400, and 422 status
+// codes, the error codes of Apollo and Hasura, and the messages Dgraph
+// reports for expired tokens, missing permissions, missing nodes, and
+// invalid documents. For a graphql error, the first error that is
+// recognized decides.
+func DefaultErrorClassifier(err error) error {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusUnauthorized:
+			return ErrUnauthorized
+		case http.StatusForbidden:
+			return ErrForbidden
+		case http.StatusNotFound:
+			return ErrNotFound
+		case http.StatusBadRequest, http.StatusUnprocessableEntity:
+			return ErrValidation
+		}
+		return nil
+	}
+
+	var gqlErr *GraphQLError
+	if !errors.As(err, &gqlErr) {
+		return nil
+	}
+
+	for _, re := range gqlErr.Errors {
+		if class, ok := classCodes[re.Code()]; ok {
+			return class
+		}
+		for _, cm := range classMessages {
+			if strings.Contains(re.Message, cm.fragment) {
+				return cm.err
+			}
+		}
+	}
+
+	return nil
+}
+
+// classify records the class of the http or graphql error in the chain so
+// errors.Is matches it, and returns the error.
+func (g *GraphQL) classify(err error) error {
+	classifier := g.classifier
+	if classifier == nil {
+		classifier = DefaultErrorClassifier
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		httpErr.class = classifier(httpErr)
+		return err
+	}
+
+	var gqlErr *GraphQLError
+	if errors.As(err, &gqlErr) {
+		gqlErr.class = classifier(gqlErr)
+	}
+
+	return err
+}
@@ -0,0 +1,85 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestErrorClassifier(t *testing.T) {
+	t.Log("Given the need to check for well-known failures with errors.Is.")
+	{
+		tt := []struct {
+			name   string
+			status int
+			body   string
+			exp    error
+		}{
+			{"unauthorized status", http.StatusUnauthorized, ``, graphql.ErrUnauthorized},
+			{"forbidden status", http.StatusForbidden, ``, graphql.ErrForbidden},
+			{"apollo code", http.StatusOK, `{"errors": [{"message": "no", "extensions": {"code": "UNAUTHENTICATED"}}]}`, graphql.ErrUnauthorized},
+			{"hasura code", http.StatusOK, `{"errors": [{"message": "no", "extensions": {"code": "permission-error"}}]}`, graphql.ErrForbidden},
+			{"dgraph message", http.StatusOK, `{"errors": [{"message": "couldn't rewrite mutation updateCity because no nodes found"}]}`, graphql.ErrNotFound},
+			{"validation message", http.StatusOK, `{"errors": [{"message": "Cannot query field \"size\" on type \"City\"."}]}`, graphql.ErrValidation},
+		}
+
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen the host reports a %s.", testID, test.name)
+			{
+				f := func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(test.status)
+					io.WriteString(w, test.body)
+				}
+
+				server := httptest.NewServer(http.HandlerFunc(f))
+				defer server.Close()
+
+				gql := graphql.New(server.URL)
+
+				err := gql.Execute(context.Background(), `query { city { name } }`, nil)
+				if !errors.Is(err, test.exp) {
+					t.Fatalf("\t%s\tTest %d:\tShould match %v: %v", failed, testID, test.exp, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould match %v.", success, testID, test.exp)
+
+				if errors.Is(err, graphql.ErrTimeout) {
+					t.Fatalf("\t%s\tTest %d:\tShould not match other errors.", failed, testID)
+				}
+				t.Logf("\t%s\tTest %d:\tShould not match other errors.", success, testID)
+			}
+		}
+
+		testID := len(tt)
+		t.Logf("\tTest %d:\tWhen a custom classifier extends the default.", testID)
+		{
+			errQuota := errors.New("quota exceeded")
+
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"errors": [{"message": "no", "extensions": {"code": "QUOTA"}}]}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			classifier := func(err error) error {
+				if graphql.ErrorCode(err) == "QUOTA" {
+					return errQuota
+				}
+				return graphql.DefaultErrorClassifier(err)
+			}
+
+			gql := graphql.New(server.URL, graphql.WithErrorClassifier(classifier))
+
+			err := gql.Execute(context.Background(), `query { city { name } }`, nil)
+			if !errors.Is(err, errQuota) || !graphql.IsGraphQLError(err) {
+				t.Fatalf("\t%s\tTest %d:\tShould match the custom error: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould match the custom error.", success, testID)
+		}
+	}
+}
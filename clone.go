@@ -0,0 +1,59 @@
+package graphql
+
+import (
+	"context"
+	"net/url"
+)
+
+// Option represents a function that configures a GraphQL value.
+type Option = func(gql *GraphQL)
+
+// Clone returns a copy of the client with the options applied to the copy.
+// The copy shares the http client and its connection pool, the transport,
+// the host pool, the caches and the registry with the original, so cloning
+// is cheap. Headers, query parameters and the login are copied, so options
+// applied to the copy don't affect the original. Since the cache is shared
+// and doesn't consider headers, provide a separate cache with WithCache
+// when the clones see different data.
+func (g *GraphQL) Clone(options ...Option) *GraphQL {
+	gql := *g
+
+	gql.headers = make(map[string]string, len(g.headers))
+	for key, value := range g.headers {
+		gql.headers[key] = value
+	}
+
+	if g.queryParams != nil {
+		gql.queryParams = make(map[string]url.Values, len(g.queryParams))
+		for endpoint, params := range g.queryParams {
+			values := make(url.Values, len(params))
+			for key, v := range params {
+				values[key] = append([]string(nil), v...)
+			}
+			gql.queryParams[endpoint] = values
+		}
+	}
+
+	gql.closers = newCloserSet()
+	gql.stats = &clientStats{}
+	gql.subs = &subscriptionPool{}
+	gql.ownedTransport = nil
+	gql.ctxHeaders = append([]func(ctx context.Context) map[string]string(nil), g.ctxHeaders...)
+	gql.headerSources = append([]*headerSource(nil), g.headerSources...)
+	gql.rewriters = append([]QueryRewriter(nil), g.rewriters...)
+
+	if g.login != nil {
+		gql.login = &dgraphLogin{
+			namespace: g.login.namespace,
+			userID:    g.login.userID,
+			password:  g.login.password,
+		}
+	}
+
+	for _, option := range options {
+		option(&gql)
+	}
+	gql.ownTransport()
+
+	return &gql
+}
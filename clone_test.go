@@ -0,0 +1,47 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestClone(t *testing.T) {
+	t.Log("Given the need to derive tenant scoped clients.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen cloning a client with an extra header.", testID)
+		{
+			var tenants []string
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-App") != "orders" {
+					t.Fatalf("\t%s\tTest %d:\tShould keep the original headers.", failed, testID)
+				}
+				tenants = append(tenants, r.Header.Get("X-Tenant-Id"))
+				io.WriteString(w, `{"data": {"__typename": "Query"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithHeader("X-App", "orders"))
+			acme := gql.Clone(graphql.WithHeader("X-Tenant-Id", "acme"))
+
+			for _, c := range []*graphql.GraphQL{acme, gql} {
+				if err := c.Execute(context.Background(), `query { __typename }`, nil); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the queries.", success, testID)
+
+			if len(tenants) != 2 || tenants[0] != "acme" || tenants[1] != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould only apply the option to the clone: %v", failed, testID, tenants)
+			}
+			t.Logf("\t%s\tTest %d:\tShould only apply the option to the clone.", success, testID)
+		}
+	}
+}
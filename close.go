@@ -0,0 +1,91 @@
+package graphql
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned for requests made after Close is called.
+var ErrClosed = errors.New("graphql client closed")
+
+// closerSet tracks the background work started for a client, like health
+// checkers and subscriptions, so it can be stopped when the client closes.
+type closerSet struct {
+	mu     sync.Mutex
+	closed bool
+	next   int
+	fns    map[int]func()
+}
+
+// newCloserSet constructs an empty closerSet.
+func newCloserSet() *closerSet {
+	return &closerSet{
+		fns: make(map[int]func()),
+	}
+}
+
+// add registers the function to run on close and returns a function that
+// unregisters it. If the set is already closed the function runs
+// immediately.
+func (cs *closerSet) add(fn func()) func() {
+	cs.mu.Lock()
+	if cs.closed {
+		cs.mu.Unlock()
+		fn()
+		return func() {}
+	}
+
+	id := cs.next
+	cs.next++
+	cs.fns[id] = fn
+	cs.mu.Unlock()
+
+	return func() {
+		cs.mu.Lock()
+		delete(cs.fns, id)
+		cs.mu.Unlock()
+	}
+}
+
+// isClosed reports whether the set has been closed.
+func (cs *closerSet) isClosed() bool {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	return cs.closed
+}
+
+// close runs every registered function once.
+func (cs *closerSet) close() {
+	cs.mu.Lock()
+	if cs.closed {
+		cs.mu.Unlock()
+		return
+	}
+	cs.closed = true
+	fns := cs.fns
+	cs.fns = nil
+	cs.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// Close stops the background work started for the client, like health
+// checkers and open subscriptions, and closes the idle connections of the
+// transport the client owns, which is the one built for WithTLSConfig,
+// WithClientCertificates, and WithProxy. Clients and transports provided
+// with WithClient and WithTransport may be shared, so they're left for the
+// caller to close. Requests made after Close return ErrClosed. Clones have
+// their own lifecycle and aren't closed with the original. It's safe to
+// call Close more than once.
+func (g *GraphQL) Close() error {
+	g.closers.close()
+
+	if g.ownedTransport != nil {
+		g.ownedTransport.CloseIdleConnections()
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestClose(t *testing.T) {
+	t.Log("Given the need to shut down a client cleanly.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a health checker is running.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `[{"instance": "alpha", "address": "localhost:7080", "status": "healthy"}]`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+			hc := graphql.NewHealthChecker(gql, time.Hour)
+			hc.Start()
+
+			done := make(chan struct{})
+			go func() {
+				gql.Close()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("\t%s\tTest %d:\tShould stop the health checker.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould stop the health checker.", success, testID)
+
+			hc.Stop()
+			t.Logf("\t%s\tTest %d:\tShould be able to stop the health checker again.", success, testID)
+
+			err := gql.Execute(context.Background(), `query { __typename }`, nil)
+			if !errors.Is(err, graphql.ErrClosed) {
+				t.Fatalf("\t%s\tTest %d:\tShould reject requests after close: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject requests after close.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the http client is provided by the caller.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"data": {"__typename": "Query"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			rt := idleCounter{RoundTripper: http.DefaultTransport}
+			client := http.Client{Transport: &rt}
+
+			gql := graphql.New(server.URL, graphql.WithClient(&client))
+			if err := gql.Execute(context.Background(), `query { __typename }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			gql.Close()
+
+			if n := atomic.LoadInt32(&rt.closes); n != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould leave the connections of the shared client open: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould leave the connections of the shared client open.", success, testID)
+		}
+	}
+}
+
+// idleCounter counts the calls to close its idle connections.
+type idleCounter struct {
+	http.RoundTripper
+	closes int32
+}
+
+func (ic *idleCounter) CloseIdleConnections() {
+	atomic.AddInt32(&ic.closes, 1)
+}
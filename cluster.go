@@ -0,0 +1,271 @@
+package graphql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// InstanceHealth represents the health of a Dgraph instance as reported by
+// the /health endpoint. Uptime is in seconds.
+type InstanceHealth struct {
+	Instance    string   `json:"instance"`
+	Address     string   `json:"address"`
+	Status      string   `json:"status"`
+	Group       string   `json:"group"`
+	Version     string   `json:"version"`
+	Uptime      int64    `json:"uptime"`
+	LastEcho    int64    `json:"lastEcho"`
+	Ongoing     []string `json:"ongoing"`
+	Indexing    []string `json:"indexing"`
+	EEFeatures  []string `json:"ee_features"`
+	MaxAssigned uint64   `json:"max_assigned"`
+}
+
+// Health returns the health of the Alpha the client is connected to using
+// the url/health endpoint. If all is true the health of every Alpha in the
+// cluster is returned.
+func (g *GraphQL) Health(ctx context.Context, all bool) ([]InstanceHealth, error) {
+	endpoint := "health"
+	if all {
+		endpoint += "?all"
+	}
+
+	data, err := g.get(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []InstanceHealth
+	if err := json.Unmarshal(data, &instances); err != nil {
+		return nil, fmt.Errorf("graphql decoding error: %w response: %s", err, excerpt(data, g.errorBodyLimit))
+	}
+
+	return instances, nil
+}
+
+// =============================================================================
+
+// ClusterMember represents an Alpha or Zero in the cluster state.
+type ClusterMember struct {
+	ID         uint64 `json:"id,string"`
+	GroupID    uint32 `json:"groupId"`
+	Addr       string `json:"addr"`
+	Leader     bool   `json:"leader"`
+	AmDead     bool   `json:"amDead"`
+	LastUpdate uint64 `json:"lastUpdate,string"`
+}
+
+// Tablet represents a predicate served by a group and its size on disk.
+type Tablet struct {
+	GroupID           uint32 `json:"groupId"`
+	Predicate         string `json:"predicate"`
+	Force             bool   `json:"force"`
+	OnDiskBytes       int64  `json:"onDiskBytes,string"`
+	UncompressedBytes int64  `json:"uncompressedBytes,string"`
+	ReadOnly          bool   `json:"readOnly"`
+	MoveTs            uint64 `json:"moveTs,string"`
+}
+
+// ClusterGroup represents an Alpha group, its members, and the tablets it
+// serves.
+type ClusterGroup struct {
+	Members    map[string]ClusterMember `json:"members"`
+	Tablets    map[string]Tablet        `json:"tablets"`
+	SnapshotTs uint64                   `json:"snapshotTs,string"`
+	Checksum   uint64                   `json:"checksum,string"`
+}
+
+// ClusterLicense represents the enterprise license of the cluster.
+type ClusterLicense struct {
+	User     string `json:"user"`
+	MaxNodes uint64 `json:"maxNodes,string"`
+	ExpiryTs int64  `json:"expiryTs,string"`
+	Enabled  bool   `json:"enabled"`
+}
+
+// ClusterState represents the membership of the cluster as reported by the
+// Dgraph /state endpoint. Groups and Zeros are keyed by id.
+type ClusterState struct {
+	Counter  uint64                   `json:"counter,string"`
+	Groups   map[string]ClusterGroup  `json:"groups"`
+	Zeros    map[string]ClusterMember `json:"zeros"`
+	MaxUID   uint64                   `json:"maxUID,string"`
+	MaxTxnTs uint64                   `json:"maxTxnTs,string"`
+	MaxNsID  uint64                   `json:"maxNsID,string"`
+	CID      string                   `json:"cid"`
+	License  *ClusterLicense          `json:"license"`
+}
+
+// Leader returns the member leading the specified group.
+func (s *ClusterState) Leader(group string) (ClusterMember, bool) {
+	for _, m := range s.Groups[group].Members {
+		if m.Leader {
+			return m, true
+		}
+	}
+	return ClusterMember{}, false
+}
+
+// Tablet returns the tablet for the predicate and the group serving it.
+func (s *ClusterState) Tablet(predicate string) (Tablet, string, bool) {
+	for id, g := range s.Groups {
+		if t, ok := g.Tablets[predicate]; ok {
+			return t, id, true
+		}
+	}
+	return Tablet{}, "", false
+}
+
+// State returns the state of the cluster using the url/state endpoint.
+func (g *GraphQL) State(ctx context.Context) (*ClusterState, error) {
+	data, err := g.get(ctx, "state")
+	if err != nil {
+		return nil, err
+	}
+
+	var state ClusterState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("graphql decoding error: %w response: %s", err, excerpt(data, g.errorBodyLimit))
+	}
+
+	return &state, nil
+}
+
+// =============================================================================
+
+// Metric represents a sample from the Prometheus metrics of an instance.
+type Metric struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Metrics represents the samples from the Prometheus metrics of an
+// instance.
+type Metrics []Metric
+
+// Get returns the value of the first sample with the name whose labels
+// include every specified label. Labels are provided as key value pairs.
+func (m Metrics) Get(name string, labels ...string) (float64, bool) {
+next:
+	for _, metric := range m {
+		if metric.Name != name {
+			continue
+		}
+		for i := 0; i+1 < len(labels); i += 2 {
+			if metric.Labels[labels[i]] != labels[i+1] {
+				continue next
+			}
+		}
+		return metric.Value, true
+	}
+	return 0, false
+}
+
+// PrometheusMetrics returns the metrics of the Alpha the client is
+// connected to using the url/debug/prometheus_metrics endpoint.
+func (g *GraphQL) PrometheusMetrics(ctx context.Context) (Metrics, error) {
+	data, err := g.get(ctx, "debug/prometheus_metrics")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMetrics(data)
+}
+
+// parseMetrics parses the samples from metrics in the Prometheus text
+// exposition format. Comments and type information are ignored.
+func parseMetrics(data []byte) (Metrics, error) {
+	var metrics Metrics
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+
+		metric := Metric{
+			Labels: make(map[string]string),
+		}
+
+		rest := line
+		if i := strings.IndexAny(line, "{ "); i >= 0 && line[i] == '{' {
+			end := strings.LastIndexByte(line, '}')
+			if end < i {
+				return nil, fmt.Errorf("graphql metrics error: invalid sample %q", line)
+			}
+			metric.Name = line[:i]
+			if err := parseLabels(line[i+1:end], metric.Labels); err != nil {
+				return nil, fmt.Errorf("graphql metrics error: %w in sample %q", err, line)
+			}
+			rest = line[end+1:]
+		} else if i >= 0 {
+			metric.Name = line[:i]
+			rest = line[i:]
+		}
+
+		fields := strings.Fields(rest)
+		if metric.Name == "" || len(fields) == 0 {
+			return nil, fmt.Errorf("graphql metrics error: invalid sample %q", line)
+		}
+
+		value, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("graphql metrics error: %w in sample %q", err, line)
+		}
+		metric.Value = value
+
+		metrics = append(metrics, metric)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("graphql metrics error: %w", err)
+	}
+
+	sort.SliceStable(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+
+	return metrics, nil
+}
+
+// parseLabels parses the label pairs of a sample, like a="1",b="2".
+func parseLabels(s string, labels map[string]string) error {
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimLeft(s, ", ") {
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 || eq+1 >= len(s) || s[eq+1] != '"' {
+			return fmt.Errorf("invalid labels")
+		}
+		key := strings.TrimSpace(s[:eq])
+
+		// The value is quoted and can contain escaped quotes.
+		var value strings.Builder
+		i := eq + 2
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			value.WriteByte(s[i])
+		}
+		if i >= len(s) {
+			return fmt.Errorf("unterminated label value")
+		}
+
+		labels[key] = value.String()
+		s = s[i+1:]
+	}
+	return nil
+}
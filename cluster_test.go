@@ -0,0 +1,107 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestCluster(t *testing.T) {
+	t.Log("Given the need to inspect the state of a Dgraph cluster.")
+	{
+		f := func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/health":
+				if _, ok := r.URL.Query()["all"]; !ok {
+					t.Fatalf("\t%s\tShould ask for every instance: %s", failed, r.URL)
+				}
+				io.WriteString(w, `[
+					{"instance": "alpha", "address": "alpha1:7080", "status": "healthy", "group": "1", "version": "v23.1.0", "uptime": 120, "ee_features": ["backup_restore"]},
+					{"instance": "alpha", "address": "alpha2:7080", "status": "healthy", "group": "2", "version": "v23.1.0", "uptime": 90}
+				]`)
+
+			case "/state":
+				io.WriteString(w, `{
+					"counter": "15",
+					"groups": {"1": {
+						"members": {"1": {"id": "1", "groupId": 1, "addr": "alpha1:7080", "leader": true, "lastUpdate": "1603379826"}},
+						"tablets": {"name": {"groupId": 1, "predicate": "name", "onDiskBytes": "2048", "uncompressedBytes": "4096"}},
+						"snapshotTs": "13"
+					}},
+					"zeros": {"1": {"id": "1", "addr": "zero1:5080", "leader": true}},
+					"maxUID": "10000",
+					"maxTxnTs": "20000",
+					"cid": "abc"
+				}`)
+
+			case "/debug/prometheus_metrics":
+				io.WriteString(w, "# HELP dgraph_num_queries_total Total number of queries\n"+
+					"# TYPE dgraph_num_queries_total counter\n"+
+					"dgraph_num_queries_total{method=\"Server.Query\",status=\"\"} 42\n"+
+					"dgraph_num_queries_total{method=\"Server.Mutate\",status=\"\"} 7\n"+
+					"go_goroutines 118\n")
+			}
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(f))
+		defer server.Close()
+
+		gql := graphql.New(server.URL)
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen asking for the health of every Alpha.", testID)
+		{
+			instances, err := gql.Health(context.Background(), true)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to get the health: %v", failed, testID, err)
+			}
+			if len(instances) != 2 || instances[0].Uptime != 120 || instances[1].Group != "2" || instances[0].EEFeatures[0] != "backup_restore" {
+				t.Fatalf("\t%s\tTest %d:\tShould get every instance: %+v", failed, testID, instances)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get every instance.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen asking for the state of the cluster.", testID)
+		{
+			state, err := gql.State(context.Background())
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to get the state: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to get the state.", success, testID)
+
+			leader, ok := state.Leader("1")
+			if !ok || leader.Addr != "alpha1:7080" || state.MaxUID != 10000 {
+				t.Fatalf("\t%s\tTest %d:\tShould get the group membership: %+v", failed, testID, state)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the group membership.", success, testID)
+
+			tablet, group, ok := state.Tablet("name")
+			if !ok || group != "1" || tablet.OnDiskBytes != 2048 {
+				t.Fatalf("\t%s\tTest %d:\tShould get the tablet sizes: %+v", failed, testID, tablet)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the tablet sizes.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen asking for the Prometheus metrics.", testID)
+		{
+			metrics, err := gql.PrometheusMetrics(context.Background())
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to get the metrics: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to get the metrics.", success, testID)
+
+			mutations, ok := metrics.Get("dgraph_num_queries_total", "method", "Server.Mutate")
+			goroutines, _ := metrics.Get("go_goroutines")
+			if !ok || mutations != 7 || goroutines != 118 {
+				t.Fatalf("\t%s\tTest %d:\tShould get the samples: %+v", failed, testID, metrics)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the samples.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,500 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// scalars maps the graphql scalars to their Go types. Unknown custom
+// scalars are decoded as json.RawMessage.
+var scalars = map[string]string{
+	"ID":       "string",
+	"String":   "string",
+	"Int":      "int",
+	"Int64":    "int64",
+	"Float":    "float64",
+	"Boolean":  "bool",
+	"DateTime": "time.Time",
+}
+
+// initialisms are the words that are capitalized as a whole in Go names.
+var initialisms = map[string]bool{
+	"ID": true, "URL": true, "URI": true, "API": true, "HTTP": true, "JSON": true, "UUID": true, "IP": true, "SQL": true,
+}
+
+// keywords are the Go keywords that can't be used as parameter names.
+var keywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true, "default": true,
+	"defer": true, "else": true, "fallthrough": true, "for": true, "func": true, "go": true,
+	"goto": true, "if": true, "import": true, "interface": true, "map": true, "package": true,
+	"range": true, "return": true, "select": true, "struct": true, "switch": true, "type": true, "var": true,
+}
+
+// generator produces the Go source for a set of operations.
+type generator struct {
+	schema  *ast.Schema
+	buf     bytes.Buffer
+	types   map[string]bool
+	imports map[string]bool
+}
+
+// generate produces the formatted Go source for every operation in the
+// registry, validated against the schema.
+func generate(pkg string, sdl []string, registry *graphql.Registry) ([]byte, error) {
+	sources := make([]*ast.Source, len(sdl))
+	for i, s := range sdl {
+		sources[i] = &ast.Source{Name: fmt.Sprintf("schema%d", i), Input: s}
+	}
+
+	schema, err := gqlparser.LoadSchema(sources...)
+	if err != nil {
+		return nil, fmt.Errorf("loading schema: %w", err)
+	}
+
+	g := generator{
+		schema: schema,
+		types:  make(map[string]bool),
+		imports: map[string]bool{
+			"context":                      true,
+			"github.com/ardanlabs/graphql": true,
+		},
+	}
+
+	for _, name := range registry.Names() {
+		document, err := registry.Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+
+		doc, errs := gqlparser.LoadQuery(schema, document)
+		if len(errs) > 0 {
+			return nil, fmt.Errorf("validating %s: %w", name, errs)
+		}
+
+		op := doc.Operations.ForName(name)
+		if op == nil {
+			return nil, fmt.Errorf("operation %s not found", name)
+		}
+
+		if err := g.operation(op, document); err != nil {
+			return nil, fmt.Errorf("generating %s: %w", name, err)
+		}
+	}
+
+	var src bytes.Buffer
+	src.WriteString("// Code generated by graphqlgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+
+	imports := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imports = append(imports, imp)
+	}
+	sort.Strings(imports)
+
+	src.WriteString("import (\n")
+	for _, imp := range imports {
+		fmt.Fprintf(&src, "\t%q\n", imp)
+	}
+	src.WriteString(")\n")
+	src.Write(g.buf.Bytes())
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting source: %w\n%s", err, src.String())
+	}
+
+	return formatted, nil
+}
+
+// operation generates the document constant, response types, and execute
+// function for the operation.
+func (g *generator) operation(op *ast.OperationDefinition, document string) error {
+	name := exported(op.Name)
+	respType := name + "Response"
+
+	fmt.Fprintf(&g.buf, "\n// %sDocument is the graphql document for the %s operation.\n", name, op.Name)
+	fmt.Fprintf(&g.buf, "const %sDocument = %s\n", name, goString(document))
+
+	if err := g.selectionStruct(respType, name, fmt.Sprintf("the response for the %s operation", op.Name), op.SelectionSet); err != nil {
+		return err
+	}
+
+	var params, vars []string
+	for _, v := range op.VariableDefinitions {
+		param := unexported(v.Variable)
+		if keywords[param] {
+			param += "Arg"
+		}
+
+		typ, err := g.inputType(v.Type)
+		if err != nil {
+			return err
+		}
+
+		params = append(params, fmt.Sprintf("%s %s", param, typ))
+		vars = append(vars, fmt.Sprintf("graphql.WithVariable(%q, %s)", v.Variable, param))
+	}
+
+	fmt.Fprintf(&g.buf, "\n// %s executes the %s operation.\n", name, op.Name)
+	fmt.Fprintf(&g.buf, "func %s(ctx context.Context, gql *graphql.GraphQL", name)
+	for _, p := range params {
+		fmt.Fprintf(&g.buf, ", %s", p)
+	}
+	fmt.Fprintf(&g.buf, ") (*%s, error) {\n", respType)
+	fmt.Fprintf(&g.buf, "\tvar response %s\n", respType)
+	fmt.Fprintf(&g.buf, "\tif err := gql.Execute(ctx, %sDocument, &response", name)
+	for _, v := range vars {
+		fmt.Fprintf(&g.buf, ",\n\t\t%s", v)
+	}
+	if len(vars) > 0 {
+		g.buf.WriteString(",\n\t")
+	}
+	g.buf.WriteString("); err != nil {\n\t\treturn nil, err\n\t}\n\treturn &response, nil\n}\n")
+
+	return nil
+}
+
+// field represents a field of a generated struct.
+type field struct {
+	name    string
+	typ     string
+	jsonKey string
+}
+
+// nested represents a struct type for an object field that is generated
+// after the struct that references it.
+type nested struct {
+	typeName string
+	sel      *ast.Field
+	def      *ast.Definition
+}
+
+// selectionStruct generates a struct type for the selection set. Struct
+// types for object fields are named using the prefix followed by the field
+// name and are generated after the struct.
+func (g *generator) selectionStruct(typeName string, prefix string, desc string, set ast.SelectionSet) error {
+	var pending []nested
+	fields, err := g.collectFields(prefix, set, false, &pending)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(&g.buf, "\n// %s represents %s.\n", typeName, desc)
+	fmt.Fprintf(&g.buf, "type %s struct {\n", typeName)
+	for _, f := range fields {
+		fmt.Fprintf(&g.buf, "\t%s %s `json:\"%s\"`\n", f.name, f.typ, f.jsonKey)
+	}
+	g.buf.WriteString("}\n")
+
+	for _, n := range pending {
+		desc := fmt.Sprintf("the %s field of type %s", n.sel.Name, n.def.Name)
+		if err := g.selectionStruct(n.typeName, n.typeName, desc, n.sel.SelectionSet); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// collectFields flattens the selection set, including fragments, into the
+// list of fields for a struct. Fields selected through a fragment on a
+// different type are optional since they're only present for that type.
+func (g *generator) collectFields(prefix string, set ast.SelectionSet, optional bool, pending *[]nested) ([]field, error) {
+	var fields []field
+	seen := make(map[string]bool)
+
+	add := func(fs []field) {
+		for _, f := range fs {
+			if !seen[f.jsonKey] {
+				seen[f.jsonKey] = true
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			key := sel.Alias
+			if key == "" {
+				key = sel.Name
+			}
+			if seen[key] {
+				continue
+			}
+
+			typ, err := g.outputType(prefix+exported(key), sel, sel.Definition.Type, pending)
+			if err != nil {
+				return nil, err
+			}
+			if optional && !strings.HasPrefix(typ, "*") && !strings.HasPrefix(typ, "[]") {
+				typ = "*" + typ
+			}
+
+			add([]field{{name: exported(key), typ: typ, jsonKey: key}})
+
+		case *ast.FragmentSpread:
+			fs, err := g.collectFields(prefix, sel.Definition.SelectionSet, optional || !g.sameType(sel.ObjectDefinition, sel.Definition.TypeCondition), pending)
+			if err != nil {
+				return nil, err
+			}
+			add(fs)
+
+		case *ast.InlineFragment:
+			inner := optional
+			if sel.TypeCondition != "" {
+				inner = inner || !g.sameType(sel.ObjectDefinition, sel.TypeCondition)
+			}
+			fs, err := g.collectFields(prefix, sel.SelectionSet, inner, pending)
+			if err != nil {
+				return nil, err
+			}
+			add(fs)
+		}
+	}
+
+	return fields, nil
+}
+
+// sameType reports whether the fragment type condition matches the type
+// the fragment is applied to.
+func (g *generator) sameType(def *ast.Definition, condition string) bool {
+	return def != nil && def.Name == condition
+}
+
+// outputType returns the Go type for a field in the response, queuing the
+// generation of a struct type for object fields.
+func (g *generator) outputType(typeName string, sel *ast.Field, t *ast.Type, pending *[]nested) (string, error) {
+	if t.Elem != nil {
+		elem, err := g.outputType(typeName, sel, t.Elem, pending)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	}
+
+	def := g.schema.Types[t.NamedType]
+	if def == nil {
+		return "", fmt.Errorf("unknown type %s", t.NamedType)
+	}
+
+	var typ string
+	switch def.Kind {
+	case ast.Scalar, ast.Enum:
+		typ = g.scalar(def)
+
+	default:
+		if !g.types[typeName] {
+			g.types[typeName] = true
+			*pending = append(*pending, nested{typeName: typeName, sel: sel, def: def})
+		}
+		typ = typeName
+	}
+
+	if !t.NonNull {
+		typ = "*" + typ
+	}
+
+	return typ, nil
+}
+
+// inputType returns the Go type for a variable, generating struct types for
+// input objects.
+func (g *generator) inputType(t *ast.Type) (string, error) {
+	if t.Elem != nil {
+		elem, err := g.inputType(t.Elem)
+		if err != nil {
+			return "", err
+		}
+		return "[]" + elem, nil
+	}
+
+	def := g.schema.Types[t.NamedType]
+	if def == nil {
+		return "", fmt.Errorf("unknown type %s", t.NamedType)
+	}
+
+	var typ string
+	switch def.Kind {
+	case ast.Scalar, ast.Enum:
+		typ = g.scalar(def)
+
+	case ast.InputObject:
+		typ = exported(def.Name)
+		if !g.types[typ] {
+			g.types[typ] = true
+			if err := g.inputStruct(typ, def); err != nil {
+				return "", err
+			}
+		}
+
+	default:
+		return "", fmt.Errorf("type %s can't be used as input", def.Name)
+	}
+
+	if !t.NonNull {
+		typ = "*" + typ
+	}
+
+	return typ, nil
+}
+
+// inputStruct generates a struct type for the input object.
+func (g *generator) inputStruct(typeName string, def *ast.Definition) error {
+	type inputField struct {
+		name string
+		typ  string
+		key  string
+		opt  bool
+	}
+
+	var fields []inputField
+	for _, f := range def.Fields {
+		typ, err := g.inputType(f.Type)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, inputField{name: exported(f.Name), typ: typ, key: f.Name, opt: !f.Type.NonNull})
+	}
+
+	fmt.Fprintf(&g.buf, "\n// %s represents the %s input type.\n", typeName, def.Name)
+	fmt.Fprintf(&g.buf, "type %s struct {\n", typeName)
+	for _, f := range fields {
+		tag := f.key
+		if f.opt {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&g.buf, "\t%s %s `json:\"%s\"`\n", f.name, f.typ, tag)
+	}
+	g.buf.WriteString("}\n")
+
+	return nil
+}
+
+// scalar returns the Go type for a scalar or enum.
+func (g *generator) scalar(def *ast.Definition) string {
+	if def.Kind == ast.Enum {
+		typ := exported(def.Name)
+		if !g.types[typ] {
+			g.types[typ] = true
+			g.enum(typ, def)
+		}
+		return typ
+	}
+
+	typ, ok := scalars[def.Name]
+	if !ok {
+		g.imports["encoding/json"] = true
+		return "json.RawMessage"
+	}
+
+	if strings.HasPrefix(typ, "time.") {
+		g.imports["time"] = true
+	}
+
+	return typ
+}
+
+// enum generates a string type for the enum with a constant for each of
+// its values. The type implements graphql.Enum so the values are written
+// without quotes in documents.
+func (g *generator) enum(typeName string, def *ast.Definition) {
+	fmt.Fprintf(&g.buf, "\n// %s represents the %s enum.\n", typeName, def.Name)
+	fmt.Fprintf(&g.buf, "type %s string\n", typeName)
+
+	if len(def.EnumValues) > 0 {
+		fmt.Fprintf(&g.buf, "\n// Set of values of the %s enum.\nconst (\n", def.Name)
+		for _, v := range def.EnumValues {
+			name := v.Name
+			if strings.ToUpper(name) == name {
+				name = strings.ToLower(name)
+			}
+			fmt.Fprintf(&g.buf, "\t%s%s %s = %q\n", typeName, exported(name), typeName, v.Name)
+		}
+		g.buf.WriteString(")\n")
+	}
+
+	fmt.Fprintf(&g.buf, "\n// GraphQLEnum implements the graphql.Enum interface.\n")
+	fmt.Fprintf(&g.buf, "func (e %s) GraphQLEnum() string {\n\treturn string(e)\n}\n", typeName)
+}
+
+// exported converts a graphql name into an exported Go name.
+func exported(name string) string {
+	name = strings.TrimLeft(name, "_")
+	if name == "" {
+		return "Typename"
+	}
+
+	words := splitWords(name)
+	for i, w := range words {
+		if upper := strings.ToUpper(w); initialisms[upper] {
+			words[i] = upper
+			continue
+		}
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+
+	return strings.Join(words, "")
+}
+
+// unexported converts a graphql name into an unexported Go name.
+func unexported(name string) string {
+	words := splitWords(strings.TrimLeft(name, "_"))
+	if len(words) == 0 {
+		return "value"
+	}
+
+	words[0] = strings.ToLower(words[0])
+	for i := 1; i < len(words); i++ {
+		if upper := strings.ToUpper(words[i]); initialisms[upper] {
+			words[i] = upper
+			continue
+		}
+		words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+	}
+
+	return strings.Join(words, "")
+}
+
+// splitWords splits a camel case or snake case name into its words.
+func splitWords(name string) []string {
+	var words []string
+	var current []rune
+
+	for _, r := range name {
+		switch {
+		case r == '_':
+			if len(current) > 0 {
+				words = append(words, string(current))
+				current = nil
+			}
+		case unicode.IsUpper(r) && len(current) > 0 && !unicode.IsUpper(current[len(current)-1]):
+			words = append(words, string(current))
+			current = []rune{r}
+		default:
+			current = append(current, r)
+		}
+	}
+
+	if len(current) > 0 {
+		words = append(words, string(current))
+	}
+
+	return words
+}
+
+// goString returns the string as a Go raw string literal if possible.
+func goString(s string) string {
+	if !strings.Contains(s, "`") {
+		return "`" + s + "`"
+	}
+	return fmt.Sprintf("%q", s)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+const schema = `
+type Query {
+	getCity(id: ID!): City
+	queryCity(size: CitySize): [City]
+}
+
+type Mutation {
+	addCity(input: [AddCityInput!]!): AddCityPayload
+}
+
+type City {
+	id: ID!
+	name: String!
+	population: Int
+	location: Location
+	updatedAt: DateTime
+	size: CitySize
+}
+
+enum CitySize {
+	SMALL
+	LARGE
+	inProgress
+}
+
+type Location {
+	lat: Float!
+	lng: Float!
+}
+
+type AddCityPayload {
+	city: [City]
+}
+
+input AddCityInput {
+	name: String!
+	population: Int
+}
+
+scalar DateTime
+`
+
+func TestGenerate(t *testing.T) {
+	t.Log("Given the need to generate typed clients for operations.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen generating a query and a mutation.", testID)
+		{
+			registry := graphql.NewRegistry()
+			err := registry.Add(`
+				query GetCity($id: ID!) {
+					getCity(id: $id) { id ...CityFields location { lat lng } }
+				}
+
+				query QueryCity($size: CitySize) {
+					queryCity(size: $size) { size }
+				}
+
+				mutation AddCity($input: [AddCityInput!]!) {
+					addCity(input: $input) { city { id } }
+				}
+
+				fragment CityFields on City { name population updatedAt }
+			`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to register the operations: %v", failed, testID, err)
+			}
+
+			src, err := generate("queries", []string{schema}, registry)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to generate the code: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to generate the code.", success, testID)
+
+			code := string(src)
+			exps := []string{
+				"// Code generated by graphqlgen. DO NOT EDIT.",
+				"package queries",
+				"type GetCityResponse struct {\n\tGetCity *GetCityGetCity `json:\"getCity\"`\n}",
+				"\tPopulation *int                    `json:\"population\"`",
+				"\tUpdatedAt  *time.Time              `json:\"updatedAt\"`",
+				"\tLocation   *GetCityGetCityLocation `json:\"location\"`",
+				"type AddCityInput struct {\n\tName       string `json:\"name\"`\n\tPopulation *int   `json:\"population,omitempty\"`\n}",
+				"func GetCity(ctx context.Context, gql *graphql.GraphQL, id string) (*GetCityResponse, error) {",
+				"func AddCity(ctx context.Context, gql *graphql.GraphQL, input []AddCityInput) (*AddCityResponse, error) {",
+				`graphql.WithVariable("id", id),`,
+				"fragment CityFields on City { name population updatedAt }",
+				"type CitySize string",
+				"\tCitySizeSmall      CitySize = \"SMALL\"",
+				"\tCitySizeInProgress CitySize = \"inProgress\"",
+				"func (e CitySize) GraphQLEnum() string {",
+				"\tSize *CitySize `json:\"size\"`",
+				"func QueryCity(ctx context.Context, gql *graphql.GraphQL, size *CitySize) (*QueryCityResponse, error) {",
+			}
+			for _, exp := range exps {
+				if !strings.Contains(code, exp) {
+					t.Fatalf("\t%s\tTest %d:\tShould contain:\n%s\n\ngot:\n%s", failed, testID, exp, code)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould contain the expected types and functions.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen an operation is invalid.", testID)
+		{
+			registry := graphql.NewRegistry()
+			if err := registry.Add(`query Bad { getCity { mayor } }`); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to register the operation: %v", failed, testID, err)
+			}
+
+			if _, err := generate("queries", []string{schema}, registry); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould reject the invalid operation.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject the invalid operation.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// This program generates typed Go request and response types, along with
+// functions that execute each operation using the graphql client, from a
+// schema and a directory of .graphql operation files. Enums are generated
+// as string types with a constant for each value.
+//
+// Usage:
+//
+//	graphqlgen -schema schema.graphql -ops ./queries -pkg queries -out queries/queries.go
+//	graphqlgen -url http://localhost:8080 -ops ./queries -pkg queries -out queries/queries.go
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "graphqlgen:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		schemaFiles = flag.String("schema", "", "comma separated list of SDL files describing the schema")
+		url         = flag.String("url", "", "url of a host to fetch the schema from using introspection")
+		opsDir      = flag.String("ops", ".", "directory containing the .graphql operation files")
+		pkg         = flag.String("pkg", "queries", "package name for the generated code")
+		out         = flag.String("out", "", "file to write the generated code to, stdout if empty")
+	)
+	flag.Parse()
+
+	var sdl []string
+	switch {
+	case *schemaFiles != "":
+		for _, file := range strings.Split(*schemaFiles, ",") {
+			data, err := ioutil.ReadFile(file)
+			if err != nil {
+				return err
+			}
+			sdl = append(sdl, string(data))
+		}
+
+	case *url != "":
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		s, err := graphql.New(*url).FetchSDL(ctx)
+		if err != nil {
+			return err
+		}
+		sdl = append(sdl, s)
+
+	default:
+		return fmt.Errorf("either -schema or -url must be provided")
+	}
+
+	registry := graphql.NewRegistry()
+	if err := registry.LoadFS(os.DirFS(*opsDir)); err != nil {
+		return err
+	}
+
+	src, err := generate(*pkg, sdl, registry)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err := os.Stdout.Write(src)
+		return err
+	}
+
+	return ioutil.WriteFile(*out, src, 0644)
+}
@@ -0,0 +1,66 @@
+// This program produces a persisted query manifest from a directory of
+// .graphql operation files. The manifest can be loaded by a persisted query
+// server as its allowlist, and by the client with WithPersistedQueries so
+// only hashes are sent.
+//
+// Usage:
+//
+//	graphqlmanifest -ops ./queries -format apollo -out persisted-queries.json
+//	graphqlmanifest -ops ./queries -format relay -out queryMap.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func main() {
+	if err := run(); err != nil {
+		fmt.Fprintln(os.Stderr, "graphqlmanifest:", err)
+		os.Exit(1)
+	}
+}
+
+func run() error {
+	var (
+		opsDir = flag.String("ops", ".", "directory containing the .graphql operation files")
+		format = flag.String("format", graphql.ManifestApollo, "manifest format, apollo or relay")
+		out    = flag.String("out", "", "file to write the manifest to, stdout if empty")
+	)
+	flag.Parse()
+
+	registry := graphql.NewRegistry()
+	if err := registry.LoadFS(os.DirFS(*opsDir)); err != nil {
+		return err
+	}
+
+	manifest, err := registry.Manifest()
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch *format {
+	case graphql.ManifestApollo:
+		data, err = manifest.ApolloJSON()
+	case graphql.ManifestRelay:
+		data, err = manifest.RelayJSON()
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if *out == "" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+
+	return ioutil.WriteFile(*out, data, 0644)
+}
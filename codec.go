@@ -0,0 +1,97 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"reflect"
+	"sync"
+)
+
+// Codec decodes responses in a binary encoding like CBOR or MessagePack,
+// which can cut the CPU spent decoding very large results. ContentType
+// returns the media type of the encoding, like application/cbor, and
+// Unmarshal decodes a response document into the value. Implementations
+// must decode struct fields by their json tags, which the common CBOR and
+// MessagePack packages can be configured to do, and be safe for concurrent
+// use.
+type Codec interface {
+	ContentType() string
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// WithCodec advertises the content type of the codec in the Accept header
+// with JSON as the fallback, and decodes the responses the host sends in
+// that content type with the codec. The codec is only offered when the
+// response is decoded into a value, so raw messages, writers, decode hooks
+// and the caches keep receiving JSON. Extensions aren't decoded from
+// responses in the codec's content type.
+func WithCodec(codec Codec) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.codec = codec
+	}
+}
+
+// codecTarget reports whether the response of the call can be decoded with
+// the codec.
+func (g *GraphQL) codecTarget(ctx context.Context, response interface{}) bool {
+	if response == nil || len(g.decodeHooksFor(ctx)) > 0 {
+		return false
+	}
+
+	switch response.(type) {
+	case *json.RawMessage, io.Writer:
+		return false
+	}
+
+	rv := reflect.ValueOf(response)
+	return rv.Kind() == reflect.Ptr && !rv.IsNil()
+}
+
+// codecAccept returns the Accept header that offers the codec.
+func (g *GraphQL) codecAccept() string {
+	return g.codec.ContentType() + ", application/json;q=0.9"
+}
+
+// codecResponse reports whether the response is in the codec's content type.
+func (g *GraphQL) codecResponse(resp *Response) bool {
+	mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return err == nil && mediaType == g.codec.ContentType()
+}
+
+// decodeCodec decodes the response document with the codec. The data is
+// decoded straight into the response and the errors are returned.
+func (g *GraphQL) decodeCodec(data []byte, response interface{}) ([]ResponseError, error) {
+	rv := reflect.ValueOf(response)
+
+	doc := reflect.New(codecDocument(rv.Type()))
+	doc.Elem().Field(0).Set(rv)
+
+	if err := g.codec.Unmarshal(data, doc.Interface()); err != nil {
+		return nil, err
+	}
+
+	return doc.Elem().Field(1).Interface().([]ResponseError), nil
+}
+
+// =============================================================================
+
+// codecDocuments holds the document types built for the response types.
+var codecDocuments sync.Map
+
+// codecDocument returns the type of a response document whose data is
+// decoded into a value of the pointer type.
+func codecDocument(ptr reflect.Type) reflect.Type {
+	if t, ok := codecDocuments.Load(ptr); ok {
+		return t.(reflect.Type)
+	}
+
+	t := reflect.StructOf([]reflect.StructField{
+		{Name: "Data", Type: ptr, Tag: `json:"data"`},
+		{Name: "Errors", Type: reflect.TypeOf([]ResponseError(nil)), Tag: `json:"errors"`},
+	})
+
+	codecDocuments.Store(ptr, t)
+	return t
+}
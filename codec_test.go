@@ -0,0 +1,86 @@
+package graphql_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+// prefixCodec is a stand in for a binary codec. It decodes JSON documents
+// that start with a magic prefix.
+type prefixCodec struct{}
+
+func (prefixCodec) ContentType() string { return "application/x-prefixed" }
+
+func (prefixCodec) Unmarshal(data []byte, v interface{}) error {
+	if !bytes.HasPrefix(data, []byte("PFX")) {
+		return errors.New("missing prefix")
+	}
+	return json.Unmarshal(data[3:], v)
+}
+
+func TestCodec(t *testing.T) {
+	t.Log("Given the need to decode responses in a binary encoding.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the host supports the encoding.", testID)
+		{
+			var accept string
+			f := func(w http.ResponseWriter, r *http.Request) {
+				accept = r.Header.Get("Accept")
+				b, _ := io.ReadAll(r.Body)
+
+				body := `{"data": {"name": "Miami"}}`
+				if strings.Contains(string(b), "fail") {
+					body = `{"errors": [{"message": "failed"}]}`
+				}
+
+				if strings.HasPrefix(accept, "application/x-prefixed") {
+					w.Header().Set("Content-Type", "application/x-prefixed; charset=binary")
+					body = "PFX" + body
+				}
+				io.WriteString(w, body)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithCodec(prefixCodec{}))
+			ctx := context.Background()
+
+			var got struct {
+				Name string `json:"name"`
+			}
+			if err := gql.Execute(ctx, `query { name }`, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if accept != "application/x-prefixed, application/json;q=0.9" || got.Name != "Miami" {
+				t.Fatalf("\t%s\tTest %d:\tShould decode the response with the codec: %q %q", failed, testID, accept, got.Name)
+			}
+			t.Logf("\t%s\tTest %d:\tShould decode the response with the codec.", success, testID)
+
+			var gqlErr *graphql.GraphQLError
+			if err := gql.Execute(ctx, `query { fail }`, &got); !errors.As(err, &gqlErr) || gqlErr.Errors[0].Message != "failed" {
+				t.Fatalf("\t%s\tTest %d:\tShould decode the errors with the codec: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould decode the errors with the codec.", success, testID)
+
+			var raw json.RawMessage
+			if err := gql.Execute(ctx, `query { name }`, &raw); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if accept != "application/json" || string(raw) != `{"name": "Miami"}` {
+				t.Fatalf("\t%s\tTest %d:\tShould receive JSON for a raw message: %q %s", failed, testID, accept, raw)
+			}
+			t.Logf("\t%s\tTest %d:\tShould receive JSON for a raw message.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package graphql
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// WithCompression gzips request bodies that are at least the specified
+// number of bytes and sends them with a Content-Encoding of gzip. It also
+// advertises gzip support to the host and decodes gzip responses. Use a
+// threshold of 0 to compress every request.
+func WithCompression(threshold int) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.compression = true
+		gql.compressMin = threshold
+	}
+}
+
+// compress gzips the request body if it meets the compression threshold.
+// The returned context marks the request as compressed.
+func (g *GraphQL) compress(ctx context.Context, r io.Reader) (context.Context, io.Reader, error) {
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return ctx, nil, fmt.Errorf("graphql read request error: %w", err)
+	}
+
+	if len(body) < g.compressMin {
+		return ctx, bytes.NewReader(body), nil
+	}
+
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	if _, err := zw.Write(body); err != nil {
+		return ctx, nil, fmt.Errorf("graphql compression error: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return ctx, nil, fmt.Errorf("graphql compression error: %w", err)
+	}
+
+	ctx = withCallOption(ctx, func(opts *callOptions) {
+		opts.gzipped = true
+	})
+
+	return ctx, &b, nil
+}
+
+// gzipBody wraps the response body so a gzip encoded response is decoded
+// when it's read.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+// Close closes the gzip reader and the underlying response body.
+func (gb *gzipBody) Close() error {
+	gb.Reader.Close()
+	return gb.body.Close()
+}
+
+// decompress replaces the response body with a decoding reader if the host
+// sent a gzip encoded response.
+func decompress(resp *Response) error {
+	if resp.Header.Get("Content-Encoding") != "gzip" {
+		return nil
+	}
+
+	zr, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return fmt.Errorf("graphql decompression error: %w", err)
+	}
+
+	resp.Body = &gzipBody{Reader: zr, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+
+	return nil
+}
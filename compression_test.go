@@ -0,0 +1,96 @@
+package graphql_test
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestCompression(t *testing.T) {
+	t.Log("Given the need to compress large requests.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the request is above the threshold.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Encoding") != "gzip" {
+					t.Fatalf("\t%s\tTest %d:\tShould get a gzip request.", failed, testID)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get a gzip request.", success, testID)
+
+				zr, err := gzip.NewReader(r.Body)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to decode the request: %v", failed, testID, err)
+				}
+				b, _ := ioutil.ReadAll(zr)
+				if !strings.Contains(string(b), "addUser") {
+					t.Fatalf("\t%s\tTest %d:\tShould get the mutation: %s", failed, testID, b)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the mutation.", success, testID)
+
+				if r.Header.Get("Accept-Encoding") != "gzip" {
+					t.Fatalf("\t%s\tTest %d:\tShould accept gzip responses.", failed, testID)
+				}
+				t.Logf("\t%s\tTest %d:\tShould accept gzip responses.", success, testID)
+
+				w.Header().Set("Content-Encoding", "gzip")
+				zw := gzip.NewWriter(w)
+				io.WriteString(zw, `{"data": {"addUser": {"numUids": 1}}}`)
+				zw.Close()
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithCompression(64))
+
+			var response struct {
+				AddUser struct {
+					NumUids int `json:"numUids"`
+				} `json:"addUser"`
+			}
+			err := gql.Execute(context.Background(), `mutation($input: [AddUserInput!]!) { addUser(input: $input) { numUids } }`, &response,
+				graphql.WithVariable("input", []map[string]string{{"name": strings.Repeat("a", 128)}}),
+			)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the mutation: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the mutation.", success, testID)
+
+			if response.AddUser.NumUids != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould decode the gzip response: %+v", failed, testID, response)
+			}
+			t.Logf("\t%s\tTest %d:\tShould decode the gzip response.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the request is below the threshold.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Content-Encoding") != "" {
+					t.Fatalf("\t%s\tTest %d:\tShould get an uncompressed request.", failed, testID)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get an uncompressed request.", success, testID)
+
+				io.WriteString(w, `{"data": {"__typename": "Query"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithCompression(1024))
+
+			if err := gql.Execute(context.Background(), `query { __typename }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package graphql
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config represents the declarative configuration for a client. Zero values
+// leave the default behavior in place.
+type Config struct {
+	URL            string
+	GraphQLPath    string
+	Headers        map[string]string
+	AuthToken      string
+	UserID         string
+	Password       string
+	Namespace      uint64
+	Timeout        time.Duration
+	RequestTimeout time.Duration
+	MaxRetries     int
+	RetryBackoff   time.Duration
+}
+
+// NewFromConfig constructs a GraphQL from the configuration. Additional
+// options are applied after the configuration. The configuration is
+// validated as it is by NewE.
+func NewFromConfig(cfg Config, options ...Option) (*GraphQL, error) {
+	var opts []Option
+
+	if cfg.GraphQLPath != "" {
+		opts = append(opts, WithGraphQLPath(cfg.GraphQLPath))
+	}
+	for key, value := range cfg.Headers {
+		opts = append(opts, WithHeader(key, value))
+	}
+	if cfg.AuthToken != "" {
+		opts = append(opts, WithDgraphAuthToken(cfg.AuthToken))
+	}
+	if cfg.UserID != "" {
+		opts = append(opts, WithLogin(cfg.UserID, cfg.Password))
+		if cfg.Namespace != 0 {
+			opts = append(opts, WithNamespace(cfg.Namespace))
+		}
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, WithClient(&http.Client{
+			Transport: defaultClient.Transport,
+			Timeout:   cfg.Timeout,
+		}))
+	}
+	if cfg.RequestTimeout > 0 {
+		opts = append(opts, WithTimeout(cfg.RequestTimeout))
+	}
+	if cfg.MaxRetries > 0 {
+		opts = append(opts, WithRetries(cfg.MaxRetries, cfg.RetryBackoff))
+	}
+
+	return NewE(cfg.URL, append(opts, options...)...)
+}
+
+// ConfigFromEnv populates a Config from environment variables named with
+// the specified prefix, or GRAPHQL if the prefix is empty. For the GRAPHQL
+// prefix these variables are read:
+//
+//	GRAPHQL_URL              url of the host
+//	GRAPHQL_PATH             path of the graphql endpoint
+//	GRAPHQL_HEADERS          headers as a comma separated list of key=value
+//	GRAPHQL_AUTH_TOKEN       Dgraph auth token for admin operations
+//	GRAPHQL_USER             Dgraph login user id
+//	GRAPHQL_PASSWORD         Dgraph login password
+//	GRAPHQL_NAMESPACE        Dgraph namespace
+//	GRAPHQL_TIMEOUT          http client timeout, like 10s
+//	GRAPHQL_REQUEST_TIMEOUT  default deadline for requests, like 5s
+//	GRAPHQL_MAX_RETRIES      number of retries for rejected requests
+//	GRAPHQL_RETRY_BACKOFF    initial retry backoff, like 250ms
+func ConfigFromEnv(prefix string) (Config, error) {
+	if prefix == "" {
+		prefix = "GRAPHQL"
+	}
+	env := func(name string) string {
+		return strings.TrimSpace(os.Getenv(prefix + "_" + name))
+	}
+
+	cfg := Config{
+		URL:         env("URL"),
+		GraphQLPath: env("PATH"),
+		AuthToken:   env("AUTH_TOKEN"),
+		UserID:      env("USER"),
+		Password:    env("PASSWORD"),
+	}
+
+	if v := env("HEADERS"); v != "" {
+		cfg.Headers = make(map[string]string)
+		for _, pair := range strings.Split(v, ",") {
+			kv := strings.SplitN(pair, "=", 2)
+			if len(kv) != 2 || strings.TrimSpace(kv[0]) == "" {
+				return Config{}, fmt.Errorf("graphql config error: %s_HEADERS: invalid header %q", prefix, pair)
+			}
+			cfg.Headers[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		}
+	}
+
+	var err error
+	if v := env("NAMESPACE"); v != "" {
+		if cfg.Namespace, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return Config{}, fmt.Errorf("graphql config error: %s_NAMESPACE: %w", prefix, err)
+		}
+	}
+	if v := env("TIMEOUT"); v != "" {
+		if cfg.Timeout, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("graphql config error: %s_TIMEOUT: %w", prefix, err)
+		}
+	}
+	if v := env("REQUEST_TIMEOUT"); v != "" {
+		if cfg.RequestTimeout, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("graphql config error: %s_REQUEST_TIMEOUT: %w", prefix, err)
+		}
+	}
+	if v := env("MAX_RETRIES"); v != "" {
+		if cfg.MaxRetries, err = strconv.Atoi(v); err != nil {
+			return Config{}, fmt.Errorf("graphql config error: %s_MAX_RETRIES: %w", prefix, err)
+		}
+	}
+	if v := env("RETRY_BACKOFF"); v != "" {
+		if cfg.RetryBackoff, err = time.ParseDuration(v); err != nil {
+			return Config{}, fmt.Errorf("graphql config error: %s_RETRY_BACKOFF: %w", prefix, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// NewE constructs a GraphQL like New, but validates the url and the
+// configuration produced by the options, so misconfiguration is reported at
+// startup instead of on the first request. The url must be absolute with a
+// host, and must use the http or https scheme unless a transport is
+// provided with WithTransport.
+func NewE(url string, options ...Option) (*GraphQL, error) {
+	gql := New(url, options...)
+
+	if err := gql.validateConfig(); err != nil {
+		return nil, err
+	}
+
+	return gql, nil
+}
+
+// validateConfig checks the configuration for values that would make every
+// request fail.
+func (g *GraphQL) validateConfig() error {
+	if g.configErr != nil {
+		return g.configErr
+	}
+
+	if err := g.validateURL(g.url); err != nil {
+		return err
+	}
+
+	if g.hosts != nil {
+		for _, h := range g.hosts.hosts {
+			if err := g.validateURL(h.url); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch {
+	case g.client == nil && g.transport == nil:
+		return fmt.Errorf("graphql config error: http client is nil")
+	case g.maxHedges < 0:
+		return fmt.Errorf("graphql config error: max hedges is negative: %d", g.maxHedges)
+	case g.maxHedges > 0 && g.hedgeDelay <= 0:
+		return fmt.Errorf("graphql config error: hedge delay must be positive: %v", g.hedgeDelay)
+	case g.maxRetries < 0:
+		return fmt.Errorf("graphql config error: max retries is negative: %d", g.maxRetries)
+	case g.maxRetries > 0 && g.backoff < 0:
+		return fmt.Errorf("graphql config error: retry backoff is negative: %v", g.backoff)
+	case g.cache != nil && g.cacheTTL < 0:
+		return fmt.Errorf("graphql config error: cache ttl is negative: %v", g.cacheTTL)
+	case g.compression && g.compressMin < 0:
+		return fmt.Errorf("graphql config error: compression threshold is negative: %d", g.compressMin)
+	case g.login != nil && g.login.userID == "":
+		return fmt.Errorf("graphql config error: namespace provided without login credentials")
+	}
+
+	return nil
+}
+
+// validateURL checks the url is absolute with a host and a scheme the
+// transport supports.
+func (g *GraphQL) validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("graphql config error: invalid url %q: %w", rawURL, err)
+	}
+
+	if u.Host == "" {
+		return fmt.Errorf("graphql config error: url %q has no host", rawURL)
+	}
+
+	if g.transport == nil && u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("graphql config error: url %q must use http or https", rawURL)
+	}
+
+	return nil
+}
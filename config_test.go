@@ -0,0 +1,122 @@
+package graphql_test
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewE(t *testing.T) {
+	tt := []struct {
+		name    string
+		url     string
+		options []graphql.Option
+		err     string
+	}{
+		{"valid", "http://localhost:8080", nil, ""},
+		{"graphql-suffix", "https://dgraph.example.com/graphql", nil, ""},
+		{"no-host", "localhost:8080", nil, "has no host"},
+		{"bad-scheme", "ftp://localhost", nil, "must use http or https"},
+		{"malformed", "http://local host:80", nil, "invalid url"},
+		{"nil-client", "http://localhost", []graphql.Option{graphql.WithClient(nil)}, "http client is nil"},
+		{"negative-retries", "http://localhost", []graphql.Option{graphql.WithRetries(-1, time.Second)}, "max retries is negative"},
+		{"bad-host", "http://localhost", []graphql.Option{graphql.WithHosts(graphql.RoundRobin, "backup:8080")}, "has no host"},
+		{"namespace-only", "http://localhost", []graphql.Option{graphql.WithNamespace(1)}, "without login credentials"},
+		{"custom-transport", "grpc://localhost:9080", []graphql.Option{graphql.WithTransport(graphql.NewHTTPTransport(http.DefaultClient))}, ""},
+	}
+
+	t.Log("Given the need to validate the client configuration.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen constructing with %s.", testID, test.name)
+				{
+					gql, err := graphql.NewE(test.url, test.options...)
+
+					if test.err == "" {
+						if err != nil || gql == nil {
+							t.Fatalf("\t%s\tTest %d:\tShould construct the client: %v", failed, testID, err)
+						}
+						t.Logf("\t%s\tTest %d:\tShould construct the client.", success, testID)
+						return
+					}
+
+					if err == nil || !strings.Contains(err.Error(), test.err) {
+						t.Fatalf("\t%s\tTest %d:\tShould get the config error %q: %v", failed, testID, test.err, err)
+					}
+					t.Logf("\t%s\tTest %d:\tShould get the config error.", success, testID)
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Log("Given the need to configure the client from the environment.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the environment variables are set.", testID)
+		{
+			env := map[string]string{
+				"APP_DB_URL":           "http://dgraph:8080",
+				"APP_DB_HEADERS":       "X-App=orders, X-Env=prod",
+				"APP_DB_AUTH_TOKEN":    "secret",
+				"APP_DB_TIMEOUT":       "5s",
+				"APP_DB_MAX_RETRIES":   "3",
+				"APP_DB_RETRY_BACKOFF": "250ms",
+				"APP_DB_NAMESPACE":     "2",
+				"APP_DB_USER":          "groot",
+				"APP_DB_PASSWORD":      "password",
+			}
+			for key, value := range env {
+				os.Setenv(key, value)
+				defer os.Unsetenv(key)
+			}
+
+			cfg, err := graphql.ConfigFromEnv("APP_DB")
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to read the environment: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to read the environment.", success, testID)
+
+			exp := graphql.Config{
+				URL:          "http://dgraph:8080",
+				Headers:      map[string]string{"X-App": "orders", "X-Env": "prod"},
+				AuthToken:    "secret",
+				UserID:       "groot",
+				Password:     "password",
+				Namespace:    2,
+				Timeout:      5 * time.Second,
+				MaxRetries:   3,
+				RetryBackoff: 250 * time.Millisecond,
+			}
+			if diff := cmp.Diff(cfg, exp); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the expected config. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the expected config.", success, testID)
+
+			if _, err := graphql.NewFromConfig(cfg); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to construct the client: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to construct the client.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a variable is invalid.", testID)
+		{
+			os.Setenv("APP_DB_TIMEOUT", "soon")
+			defer os.Unsetenv("APP_DB_TIMEOUT")
+
+			if _, err := graphql.ConfigFromEnv("APP_DB"); err == nil || !strings.Contains(err.Error(), "APP_DB_TIMEOUT") {
+				t.Fatalf("\t%s\tTest %d:\tShould get an error naming the variable: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an error naming the variable.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+// ctxKey represents the type of value for the context key.
+type ctxKey int
+
+// callKey is how per-call options are stored and retrieved from the context.
+const callKey ctxKey = 1
+
+// callOptions represents the set of options that can be applied to a single
+// call by attaching them to the context.
+type callOptions struct {
+	cacheTTL     time.Duration
+	noCache      bool
+	contentType  string
+	extensions   *json.RawMessage
+	readOnly     bool
+	bestEffort   bool
+	noLogin      bool
+	queryParams  url.Values
+	gzipped      bool
+	responseInfo *ResponseInfo
+	requestID    string
+	decodeHooks  []DecodeHook
+	operation    Operation
+	fingerprint  string
+	dryRun       DryRunStub
+	priority     int
+	subsBuffer   *subscriptionBuffer
+	touches      []string
+	etag         *etagState
+	codec        bool
+	accept       string
+	logFunc      func(s string)
+	logLevel     LogLevel
+}
+
+// callOpts returns the per-call options attached to the context.
+func callOpts(ctx context.Context) callOptions {
+	if opts, ok := ctx.Value(callKey).(callOptions); ok {
+		return opts
+	}
+	return callOptions{}
+}
+
+// withCallOption returns a copy of the context with the per-call options
+// modified by the specified function.
+func withCallOption(ctx context.Context, f func(opts *callOptions)) context.Context {
+	opts := callOpts(ctx)
+	f(&opts)
+	return context.WithValue(ctx, callKey, opts)
+}
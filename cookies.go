@@ -0,0 +1,34 @@
+package graphql
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+)
+
+// WithCookieJar stores the cookies the host sets in the specified jar and
+// sends them back on later requests. This supports servers that establish
+// a session with Set-Cookie on a login mutation. The jar is used by the
+// http client, so it has no effect when a transport is set with
+// WithTransport. Clones share the jar with the original.
+func WithCookieJar(jar http.CookieJar) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		var client http.Client
+		if gql.client != nil {
+			client = *gql.client
+		}
+
+		client.Jar = jar
+		gql.client = &client
+	}
+}
+
+// WithCookies stores the cookies the host sets in an in-memory jar that
+// belongs to this client. See WithCookieJar.
+func WithCookies() func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+
+		// New only fails when options are invalid and none are provided.
+		jar, _ := cookiejar.New(nil)
+		WithCookieJar(jar)(gql)
+	}
+}
@@ -0,0 +1,59 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestCookies(t *testing.T) {
+	t.Log("Given the need to keep a session established by the server.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the login mutation sets a session cookie.", testID)
+		{
+			var session string
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if c, err := r.Cookie("session"); err == nil {
+					session = c.Value
+				}
+				http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123", Path: "/"})
+				io.WriteString(w, `{"data": {"login": {"ok": true}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithCookies())
+
+			if err := gql.Execute(context.Background(), `mutation { login { ok } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to login: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to login.", success, testID)
+
+			if err := gql.Execute(context.Background(), `query { me { ok } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+
+			if session != "abc123" {
+				t.Fatalf("\t%s\tTest %d:\tShould send the session cookie back: %q", failed, testID, session)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the session cookie back.", success, testID)
+
+			session = ""
+			other := graphql.New(server.URL)
+			if err := other.Execute(context.Background(), `query { me { ok } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+
+			if session != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould not share the cookies with other clients: %q", failed, testID, session)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not share the cookies with other clients.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package graphql
+
+import (
+	"fmt"
+	"math"
+)
+
+// CostConfig holds the settings used to estimate the cost of a document
+// and the budget it must stay within. A field costs its weight, which is
+// DefaultWeight unless Weights has an entry for the field name. The cost
+// of the fields selected under a field in Multipliers, usually a list
+// field, is multiplied by the expected number of items. Limits that are
+// zero aren't enforced.
+type CostConfig struct {
+	DefaultWeight int
+	Weights       map[string]int
+	Multipliers   map[string]int
+	MaxCost       int
+	MaxDepth      int
+	MaxBreadth    int
+
+	// Warn is called instead of rejecting a document that exceeds the
+	// budget when it's set.
+	Warn func(operation Operation, err *CostError)
+}
+
+// QueryCost represents the estimated cost of a document. Depth is the
+// deepest level of nested fields, Breadth is the largest number of fields
+// in a single selection set, and Fields is the number of fields selected.
+type QueryCost struct {
+	Cost    int
+	Depth   int
+	Breadth int
+	Fields  int
+}
+
+// CostError is returned when a document exceeds the cost budget.
+type CostError struct {
+	Cost   QueryCost
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *CostError) Error() string {
+	return "graphql cost error: " + e.Reason
+}
+
+// EstimateCost estimates the cost of the operations in the document using
+// the weights and multipliers of the configuration. Fragments are measured
+// once no matter how many times they're spread, and the totals stop growing
+// at the largest int, so a document that expands to an enormous number of
+// fields is measured quickly.
+func EstimateCost(document string, cfg CostConfig) (QueryCost, error) {
+	fields, err := SelectedFields(document)
+	if err != nil {
+		return QueryCost{}, err
+	}
+
+	sc := cfg.cost(fields, make(map[selectionKey]selectionCost))
+
+	qc := QueryCost{
+		Cost:    sc.cost,
+		Depth:   sc.depth,
+		Breadth: sc.breadth,
+		Fields:  sc.fields,
+	}
+	return qc, nil
+}
+
+// selectionCost represents the cost and shape of a selection set, where
+// depth is relative to the selection set.
+type selectionCost struct {
+	cost    int
+	depth   int
+	breadth int
+	fields  int
+}
+
+// selectionKey identifies a selection set. The fields of a fragment are
+// shared by every spread of it, so the set is identified by its first
+// field and length.
+type selectionKey struct {
+	first *Field
+	len   int
+}
+
+// cost returns the cost and shape of the fields. The result for each
+// selection set is remembered, so shared selection sets are measured once.
+func (cfg CostConfig) cost(fields []Field, seen map[selectionKey]selectionCost) selectionCost {
+	if len(fields) == 0 {
+		return selectionCost{}
+	}
+
+	key := selectionKey{first: &fields[0], len: len(fields)}
+	if sc, ok := seen[key]; ok {
+		return sc
+	}
+
+	sc := selectionCost{
+		depth:   1,
+		breadth: len(fields),
+		fields:  len(fields),
+	}
+	for _, f := range fields {
+		weight, ok := cfg.Weights[f.Name]
+		if !ok {
+			weight = cfg.DefaultWeight
+			if weight == 0 {
+				weight = 1
+			}
+		}
+
+		children := cfg.cost(f.Fields, seen)
+		if children.depth+1 > sc.depth {
+			sc.depth = children.depth + 1
+		}
+		if children.breadth > sc.breadth {
+			sc.breadth = children.breadth
+		}
+		sc.fields = addCapped(sc.fields, children.fields)
+
+		childCost := children.cost
+		if n, ok := cfg.Multipliers[f.Name]; ok {
+			childCost = mulCapped(childCost, n)
+		}
+		sc.cost = addCapped(sc.cost, addCapped(weight, childCost))
+	}
+
+	seen[key] = sc
+	return sc
+}
+
+// addCapped returns the sum of the values, capped at the largest int.
+func addCapped(a int, b int) int {
+	if b > 0 && a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// mulCapped returns the product of the values, capped at the largest int.
+func mulCapped(a int, b int) int {
+	if a > 0 && b > 0 && a > math.MaxInt/b {
+		return math.MaxInt
+	}
+	return a * b
+}
+
+// check estimates the cost of the document and reports the first limit
+// it exceeds.
+func (cfg CostConfig) check(document string) (*CostError, error) {
+	qc, err := EstimateCost(document, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case cfg.MaxCost > 0 && qc.Cost > cfg.MaxCost:
+		return &CostError{Cost: qc, Reason: fmt.Sprintf("cost %d exceeds the budget of %d", qc.Cost, cfg.MaxCost)}, nil
+	case cfg.MaxDepth > 0 && qc.Depth > cfg.MaxDepth:
+		return &CostError{Cost: qc, Reason: fmt.Sprintf("depth %d exceeds the limit of %d", qc.Depth, cfg.MaxDepth)}, nil
+	case cfg.MaxBreadth > 0 && qc.Breadth > cfg.MaxBreadth:
+		return &CostError{Cost: qc, Reason: fmt.Sprintf("breadth %d exceeds the limit of %d", qc.Breadth, cfg.MaxBreadth)}, nil
+	}
+
+	return nil, nil
+}
+
+// WithCostBudget estimates the cost of every document executed against
+// the url/graphql endpoint before it's sent, rejecting the ones that
+// exceed the budget with a *CostError. This protects shared hosts from
+// accidentally explosive nested queries. Set Warn in the configuration to
+// report those documents instead of rejecting them.
+func WithCostBudget(cfg CostConfig) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.costBudget = &cfg
+	}
+}
+
+// checkCost enforces the cost budget on the document if one is set.
+func (g *GraphQL) checkCost(endpoint string, graphql string) error {
+	if g.costBudget == nil || endpoint != g.graphqlPath {
+		return nil
+	}
+
+	costErr, err := g.costBudget.check(graphql)
+	if err != nil || costErr == nil {
+		return err
+	}
+
+	if g.costBudget.Warn != nil {
+		g.costBudget.Warn(ParseOperation(graphql), costErr)
+		return nil
+	}
+
+	return costErr
+}
@@ -0,0 +1,85 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestCostBudget(t *testing.T) {
+	const document = `query {
+	queryCity {
+		name
+		posts { title author { name } }
+	}
+}`
+
+	cfg := graphql.CostConfig{
+		Weights:     map[string]int{"queryCity": 5},
+		Multipliers: map[string]int{"queryCity": 10, "posts": 20},
+	}
+
+	t.Log("Given the need to estimate the cost of documents before they are sent.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen estimating the cost of nested lists.", testID)
+		{
+			got, err := graphql.EstimateCost(document, cfg)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to estimate the cost: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to estimate the cost.", success, testID)
+
+			// queryCity 5 + 10 * (name 1 + posts 1 + 20 * (title 1 + author 1 + name 1)).
+			exp := graphql.QueryCost{Cost: 625, Depth: 4, Breadth: 2, Fields: 6}
+			if got != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould get the expected cost: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the expected cost.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a document exceeds the budget.", testID)
+		{
+			var sent bool
+			f := func(w http.ResponseWriter, r *http.Request) {
+				sent = true
+				io.WriteString(w, `{"data": {}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			reject := cfg
+			reject.MaxCost = 500
+			gql := graphql.New(server.URL, graphql.WithCostBudget(reject))
+
+			err := gql.Execute(context.Background(), document, nil)
+
+			var costErr *graphql.CostError
+			if !errors.As(err, &costErr) || costErr.Cost.Cost != 625 || sent {
+				t.Fatalf("\t%s\tTest %d:\tShould reject the document: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject the document.", success, testID)
+
+			var warned *graphql.CostError
+			warn := cfg
+			warn.MaxDepth = 3
+			warn.Warn = func(op graphql.Operation, err *graphql.CostError) { warned = err }
+			gql = graphql.New(server.URL, graphql.WithCostBudget(warn))
+
+			if err := gql.Execute(context.Background(), document, nil); err != nil || !sent {
+				t.Fatalf("\t%s\tTest %d:\tShould send the document when warning: %v", failed, testID, err)
+			}
+			if warned == nil || warned.Cost.Depth != 4 {
+				t.Fatalf("\t%s\tTest %d:\tShould warn about the depth: %v", failed, testID, warned)
+			}
+			t.Logf("\t%s\tTest %d:\tShould warn about the depth.", success, testID)
+		}
+	}
+}
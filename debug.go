@@ -0,0 +1,113 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ServerLatency represents the time Dgraph spent on the phases of a DQL
+// request.
+type ServerLatency struct {
+	Parsing         time.Duration
+	Processing      time.Duration
+	Encoding        time.Duration
+	AssignTimestamp time.Duration
+	Total           time.Duration
+}
+
+// DebugInfo holds the diagnostics Dgraph reports in the extensions of a
+// response. ServerLatency and Txn are reported for DQL requests, and
+// TouchedUIDs and Duration for graphql requests. Operation is set for
+// graphql documents.
+type DebugInfo struct {
+	Endpoint      string
+	Operation     string
+	ServerLatency *ServerLatency
+	Txn           *DQLTxn
+	TouchedUIDs   uint64
+	Duration      time.Duration
+}
+
+// String returns the diagnostics in a form suitable for logging.
+func (d DebugInfo) String() string {
+	s := fmt.Sprintf("%sendpoint:[%s]", labels("", d.Operation), d.Endpoint)
+	if l := d.ServerLatency; l != nil {
+		s += fmt.Sprintf(" parsing:[%s] processing:[%s] encoding:[%s] assign_timestamp:[%s] total:[%s]",
+			l.Parsing, l.Processing, l.Encoding, l.AssignTimestamp, l.Total)
+	}
+	if d.Txn != nil {
+		s += fmt.Sprintf(" start_ts:[%d]", d.Txn.StartTs)
+	}
+	if d.TouchedUIDs > 0 || d.Duration > 0 {
+		s += fmt.Sprintf(" touched_uids:[%d] duration:[%s]", d.TouchedUIDs, d.Duration)
+	}
+	return s
+}
+
+// WithDebug asks Dgraph for debug information on DQL queries and reports
+// the latency and transaction extensions of every response to the
+// specified function, which may be nil. When logging is enabled the
+// diagnostics are logged as well. In debug mode Dgraph includes the uid of
+// every node in the results of DQL queries.
+func WithDebug(f func(DebugInfo)) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		WithQueryParam("query", "debug", "true")(gql)
+		gql.debug = f
+		gql.debugEnabled = true
+	}
+}
+
+// reportDebug parses the extensions of the response and delivers the
+// diagnostics to the debug function and the logger.
+func (g *GraphQL) reportDebug(ctx context.Context, endpoint string, operation string, extensions json.RawMessage) {
+	var ext struct {
+		ServerLatency *struct {
+			ParsingNs         int64 `json:"parsing_ns"`
+			ProcessingNs      int64 `json:"processing_ns"`
+			EncodingNs        int64 `json:"encoding_ns"`
+			AssignTimestampNs int64 `json:"assign_timestamp_ns"`
+			TotalNs           int64 `json:"total_ns"`
+		} `json:"server_latency"`
+		Txn         *DQLTxn `json:"txn"`
+		TouchedUIDs uint64  `json:"touched_uids"`
+		Tracing     *struct {
+			Duration int64 `json:"duration"`
+		} `json:"tracing"`
+	}
+	if err := json.Unmarshal(extensions, &ext); err != nil {
+		return
+	}
+
+	if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+
+	info := DebugInfo{
+		Endpoint:    endpoint,
+		Operation:   operation,
+		Txn:         ext.Txn,
+		TouchedUIDs: ext.TouchedUIDs,
+	}
+	if l := ext.ServerLatency; l != nil {
+		info.ServerLatency = &ServerLatency{
+			Parsing:         time.Duration(l.ParsingNs),
+			Processing:      time.Duration(l.ProcessingNs),
+			Encoding:        time.Duration(l.EncodingNs),
+			AssignTimestamp: time.Duration(l.AssignTimestampNs),
+			Total:           time.Duration(l.TotalNs),
+		}
+	}
+	if ext.Tracing != nil {
+		info.Duration = time.Duration(ext.Tracing.Duration)
+	}
+
+	if g.debug != nil {
+		g.debug(info)
+	}
+	if log := g.logger(ctx); log != nil {
+		log("debug: " + info.String())
+	}
+}
@@ -0,0 +1,74 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestDebug(t *testing.T) {
+	t.Log("Given the need to see where Dgraph spends the time of a request.")
+	{
+		f := func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/query":
+				if r.URL.Query().Get("debug") != "true" {
+					t.Fatalf("\t%s\tShould ask for debug information: %s", failed, r.URL)
+				}
+				io.WriteString(w, `{"data": {"q": []}, "extensions": {
+					"server_latency": {"parsing_ns": 1000, "processing_ns": 250000, "encoding_ns": 500, "assign_timestamp_ns": 2000, "total_ns": 253500},
+					"txn": {"start_ts": 42}
+				}}`)
+			case "/graphql":
+				io.WriteString(w, `{"data": {"queryCity": []}, "extensions": {"touched_uids": 12, "tracing": {"version": 1, "duration": 3000000}}}`)
+			}
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(f))
+		defer server.Close()
+
+		var infos []graphql.DebugInfo
+		var logs []string
+		gql := graphql.New(server.URL,
+			graphql.WithDebug(func(info graphql.DebugInfo) { infos = append(infos, info) }),
+			graphql.WithLogging(func(s string) { logs = append(logs, s) }),
+		)
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen executing a DQL query.", testID)
+		{
+			if err := gql.QueryDQL(graphql.ReadOnly(context.Background()), `{ q(func: has(name)) { uid } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+
+			if len(infos) != 1 || infos[0].Endpoint != "query" || infos[0].ServerLatency.Processing != 250*time.Microsecond || infos[0].Txn.StartTs != 42 {
+				t.Fatalf("\t%s\tTest %d:\tShould report the server latency: %+v", failed, testID, infos)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the server latency.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen executing a graphql query.", testID)
+		{
+			if err := gql.Execute(context.Background(), `query Cities { queryCity { name } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+
+			if len(infos) != 2 || infos[1].Operation != "query Cities" || infos[1].TouchedUIDs != 12 || infos[1].Duration != 3*time.Millisecond {
+				t.Fatalf("\t%s\tTest %d:\tShould report the tracing extensions: %+v", failed, testID, infos)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the tracing extensions.", success, testID)
+
+			if !strings.HasPrefix(logs[len(logs)-1], "debug: operation:[query Cities] endpoint:[graphql]") {
+				t.Fatalf("\t%s\tTest %d:\tShould log the diagnostics: %v", failed, testID, logs)
+			}
+			t.Logf("\t%s\tTest %d:\tShould log the diagnostics.", success, testID)
+		}
+	}
+}
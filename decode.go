@@ -0,0 +1,306 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DecodeHook converts a value of the response data before it's decoded into
+// the target type. The value is nil, a bool, a json.Number, a string, a
+// []interface{}, or a map[string]interface{}, or the value returned by a
+// previous hook. A hook returns the value unchanged when it doesn't apply.
+// A value the hooks convert to the target type is set directly, anything
+// else is decoded using the encoding/json rules.
+type DecodeHook func(value interface{}, target reflect.Type) (interface{}, error)
+
+// WithDecodeHooks sets the hooks that are applied when decoding the data of
+// every response. Hooks are applied in order.
+func WithDecodeHooks(hooks ...DecodeHook) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.decodeHooks = append(gql.decodeHooks[:len(gql.decodeHooks):len(gql.decodeHooks)], hooks...)
+	}
+}
+
+// DecodeHooks returns a copy of the context that applies the specified
+// hooks, after the hooks configured for the client, when decoding the data
+// of the response for the call made with it.
+func DecodeHooks(ctx context.Context, hooks ...DecodeHook) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.decodeHooks = append(opts.decodeHooks[:len(opts.decodeHooks):len(opts.decodeHooks)], hooks...)
+	})
+}
+
+// WeaklyTyped is a DecodeHook that converts between strings, numbers, and
+// booleans as the target type requires, like int64 IDs provided as strings
+// or numeric codes decoded into string fields.
+func WeaklyTyped(value interface{}, target reflect.Type) (interface{}, error) {
+	switch target.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		switch v := value.(type) {
+		case string:
+			if v == "" {
+				return nil, nil
+			}
+			if _, err := strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("converting %q to %s: %w", v, target, err)
+			}
+			return json.Number(v), nil
+		case bool:
+			if v {
+				return json.Number("1"), nil
+			}
+			return json.Number("0"), nil
+		}
+
+	case reflect.Bool:
+		switch v := value.(type) {
+		case string:
+			if v == "" {
+				return false, nil
+			}
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("converting %q to bool: %w", v, err)
+			}
+			return b, nil
+		case json.Number:
+			return v != "0", nil
+		}
+
+	case reflect.String:
+		switch v := value.(type) {
+		case json.Number:
+			return string(v), nil
+		case bool:
+			return strconv.FormatBool(v), nil
+		}
+	}
+
+	return value, nil
+}
+
+// StringToTime returns a DecodeHook that parses strings decoded into
+// time.Time values using the specified layout, for custom scalars that
+// don't use the RFC 3339 format.
+func StringToTime(layout string) DecodeHook {
+	timeType := reflect.TypeOf(time.Time{})
+
+	return func(value interface{}, target reflect.Type) (interface{}, error) {
+		s, ok := value.(string)
+		if !ok || target != timeType {
+			return value, nil
+		}
+
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			return nil, err
+		}
+		return t, nil
+	}
+}
+
+// decodeHooksFor returns the hooks that apply to the call.
+func (g *GraphQL) decodeHooksFor(ctx context.Context) []DecodeHook {
+	perCall := callOpts(ctx).decodeHooks
+	if len(perCall) == 0 {
+		return g.decodeHooks
+	}
+	return append(g.decodeHooks[:len(g.decodeHooks):len(g.decodeHooks)], perCall...)
+}
+
+// decodeWithHooks decodes the data into the response applying the hooks.
+// Errors embed the data up to the limit.
+func decodeWithHooks(data []byte, response interface{}, hooks []DecodeHook, limit int) error {
+	rv := reflect.ValueOf(response)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("graphql decoding error: response must be a non-nil pointer, got %T", response)
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+
+	var value interface{}
+	if err := d.Decode(&value); err != nil {
+		return fmt.Errorf("graphql decoding error: %w response: %s", err, excerpt(data, limit))
+	}
+
+	hd := hookDecoder{hooks: hooks}
+	if err := hd.decode("data", value, rv.Elem()); err != nil {
+		return fmt.Errorf("graphql decoding error: %w", err)
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// hookDecoder walks the generic representation of the data alongside the
+// target value, applying the hooks to every value.
+type hookDecoder struct {
+	hooks []DecodeHook
+}
+
+// decode sets the target to the value after applying the hooks. The path
+// identifies the value in errors.
+func (hd hookDecoder) decode(path string, value interface{}, target reflect.Value) error {
+	for _, hook := range hd.hooks {
+		var err error
+		if value, err = hook(value, target.Type()); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+
+	if vt := reflect.TypeOf(value); vt == target.Type() {
+		target.Set(reflect.ValueOf(value))
+		return nil
+	}
+
+	// Types that decode themselves are handed the encoded value.
+	pt := reflect.PtrTo(target.Type())
+	if pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType) {
+		return hd.fallback(path, value, target)
+	}
+
+	switch target.Kind() {
+	case reflect.Ptr:
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		return hd.decode(path, value, target.Elem())
+
+	case reflect.Struct:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return hd.fallback(path, value, target)
+		}
+		return hd.decodeStruct(path, m, target)
+
+	case reflect.Slice:
+		list, ok := value.([]interface{})
+		if !ok {
+			return hd.fallback(path, value, target)
+		}
+		s := reflect.MakeSlice(target.Type(), len(list), len(list))
+		for i, item := range list {
+			if err := hd.decode(fmt.Sprintf("%s[%d]", path, i), item, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		target.Set(s)
+		return nil
+
+	case reflect.Map:
+		m, ok := value.(map[string]interface{})
+		if !ok || target.Type().Key().Kind() != reflect.String {
+			return hd.fallback(path, value, target)
+		}
+		mv := reflect.MakeMapWithSize(target.Type(), len(m))
+		for key, item := range m {
+			elem := reflect.New(target.Type().Elem()).Elem()
+			if err := hd.decode(path+"."+key, item, elem); err != nil {
+				return err
+			}
+			mv.SetMapIndex(reflect.ValueOf(key).Convert(target.Type().Key()), elem)
+		}
+		target.Set(mv)
+		return nil
+	}
+
+	return hd.fallback(path, value, target)
+}
+
+// decodeStruct decodes the object into the fields of the struct, matching
+// keys to fields the same way encoding/json does.
+func (hd hookDecoder) decodeStruct(path string, m map[string]interface{}, target reflect.Value) error {
+	t := target.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		// Fields of embedded structs are promoted into the object.
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fv := target.Field(i)
+				if fv.Kind() == reflect.Ptr {
+					if !fv.CanSet() {
+						continue
+					}
+					if fv.IsNil() {
+						fv.Set(reflect.New(ft))
+					}
+					fv = fv.Elem()
+				}
+				if err := hd.decodeStruct(path, m, fv); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		value, ok := m[name]
+		if !ok {
+			for key, v := range m {
+				if strings.EqualFold(key, name) {
+					value, ok = v, true
+					break
+				}
+			}
+		}
+		if !ok {
+			continue
+		}
+
+		if err := hd.decode(path+"."+name, value, target.Field(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fallback decodes the value into the target using encoding/json.
+func (hd hookDecoder) fallback(path string, value interface{}, target reflect.Value) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(b, target.Addr().Interface()); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestDecodeHooks(t *testing.T) {
+	t.Log("Given the need to post-process results while decoding.")
+	{
+		f := func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, `{"data": {"cities": [
+				{"id": "9007199254740993", "name": "Miami", "founded": "28/07/1896", "active": "true", "code": 305},
+				{"id": "2", "name": "Tampa", "founded": "15/07/1887", "active": "false", "code": 813}
+			]}}`)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(f))
+		defer server.Close()
+
+		type city struct {
+			ID      int64     `json:"id"`
+			Name    string    `json:"name"`
+			Founded time.Time `json:"founded"`
+			Active  *bool     `json:"active"`
+			Code    string    `json:"code"`
+		}
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen hooks are configured for the client.", testID)
+		{
+			gql := graphql.New(server.URL,
+				graphql.WithDecodeHooks(graphql.WeaklyTyped, graphql.StringToTime("02/01/2006")),
+			)
+
+			var resp struct {
+				Cities []city `json:"cities"`
+			}
+			if err := gql.Execute(context.Background(), `query { cities { id name founded active code } }`, &resp); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			got := resp.Cities
+			if len(got) != 2 || got[0].ID != 9007199254740993 || got[0].Code != "305" || !*got[0].Active || *got[1].Active {
+				t.Fatalf("\t%s\tTest %d:\tShould convert the weakly typed values: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould convert the weakly typed values.", success, testID)
+
+			if !got[0].Founded.Equal(time.Date(1896, 7, 28, 0, 0, 0, 0, time.UTC)) {
+				t.Fatalf("\t%s\tTest %d:\tShould parse the custom time format: %v", failed, testID, got[0].Founded)
+			}
+			t.Logf("\t%s\tTest %d:\tShould parse the custom time format.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a hook is provided for the call.", testID)
+		{
+			gql := graphql.New(server.URL)
+
+			upper := func(value interface{}, target reflect.Type) (interface{}, error) {
+				if s, ok := value.(string); ok && target.Kind() == reflect.String {
+					return strings.ToUpper(s), nil
+				}
+				return value, nil
+			}
+
+			var resp struct {
+				Cities []struct {
+					Name string `json:"name"`
+				} `json:"cities"`
+			}
+			ctx := graphql.DecodeHooks(context.Background(), upper)
+			if err := gql.Execute(ctx, `query { cities { name } }`, &resp); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if resp.Cities[0].Name != "MIAMI" {
+				t.Fatalf("\t%s\tTest %d:\tShould apply the hook: %+v", failed, testID, resp)
+			}
+			t.Logf("\t%s\tTest %d:\tShould apply the hook.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen decoding without the weakly typed hook.", testID)
+		{
+			gql := graphql.New(server.URL, graphql.WithDecodeHooks(graphql.StringToTime("02/01/2006")))
+
+			var resp struct {
+				Cities []city `json:"cities"`
+			}
+			err := gql.Execute(context.Background(), `query { cities { id } }`, &resp)
+			if err == nil || !strings.Contains(err.Error(), "data.cities[0].id") {
+				t.Fatalf("\t%s\tTest %d:\tShould get an error identifying the value: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an error identifying the value.", success, testID)
+		}
+	}
+}
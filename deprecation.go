@@ -0,0 +1,78 @@
+package graphql
+
+import (
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// WithDeprecationWarnings calls the function with the deprecated fields and
+// enum values a document uses each time one is executed, so their use can
+// be tracked and burned down. This requires validation to be enabled with
+// WithSchema or WithSchemaValidation. Enum values provided in variables
+// aren't detected.
+func WithDeprecationWarnings(warn func(operation Operation, deprecations []Deprecation)) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.deprecationWarn = warn
+	}
+}
+
+// Deprecations validates the document against the schema and returns the
+// deprecated fields and enum values it uses. Each is reported once.
+func (s *Schema) Deprecations(document string) ([]Deprecation, error) {
+	doc, err := s.validateDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.deprecations(doc), nil
+}
+
+// deprecations walks the validated document for the use of deprecated
+// fields and enum values.
+func (s *Schema) deprecations(doc *ast.QueryDocument) []Deprecation {
+	var deps []Deprecation
+	seen := make(map[Deprecation]bool)
+	add := func(dep Deprecation) {
+		if !seen[dep] {
+			seen[dep] = true
+			deps = append(deps, dep)
+		}
+	}
+
+	var events validator.Events
+
+	events.OnField(func(w *validator.Walker, field *ast.Field) {
+		if field.Definition == nil || field.ObjectDefinition == nil {
+			return
+		}
+		if d := field.Definition.Directives.ForName("deprecated"); d != nil {
+			add(Deprecation{Type: field.ObjectDefinition.Name, Name: field.Name, Reason: deprecationReason(d)})
+		}
+	})
+
+	events.OnValue(func(w *validator.Walker, value *ast.Value) {
+		if value.Kind != ast.EnumValue || value.Definition == nil {
+			return
+		}
+		ev := value.Definition.EnumValues.ForName(value.Raw)
+		if ev == nil {
+			return
+		}
+		if d := ev.Directives.ForName("deprecated"); d != nil {
+			add(Deprecation{Type: value.Definition.Name, Name: value.Raw, Reason: deprecationReason(d)})
+		}
+	})
+
+	validator.Walk(s.schema, doc, &events)
+
+	return deps
+}
+
+// deprecationReason returns the reason argument of the deprecated
+// directive.
+func deprecationReason(d *ast.Directive) string {
+	if arg := d.Arguments.ForName("reason"); arg != nil && arg.Value != nil {
+		return arg.Value.Raw
+	}
+	return ""
+}
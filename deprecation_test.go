@@ -0,0 +1,80 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+// deprecatedSchema is a schema with a deprecated field and enum value.
+const deprecatedSchema = `
+enum Size { SMALL LARGE HUGE @deprecated(reason: "use LARGE") }
+
+type City {
+	name: String
+	population: Int @deprecated(reason: "use census")
+	census: Int
+}
+
+type Query {
+	queryCity(size: Size): [City]
+}`
+
+func TestDeprecationWarnings(t *testing.T) {
+	t.Log("Given the need to track the use of deprecated fields and enum values.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a document uses deprecated fields and enum values.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"data": {"queryCity": []}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			schema, err := graphql.LoadSchema(deprecatedSchema)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to load the schema: %v", failed, testID, err)
+			}
+
+			var op graphql.Operation
+			var got []graphql.Deprecation
+			warn := func(operation graphql.Operation, deps []graphql.Deprecation) {
+				op = operation
+				got = deps
+			}
+
+			gql := graphql.New(server.URL, graphql.WithSchema(schema), graphql.WithDeprecationWarnings(warn))
+
+			query := `query Cities { queryCity(size: HUGE) { name population } again: queryCity { population } }`
+			if err := gql.Execute(context.Background(), query, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			exp := []graphql.Deprecation{
+				{Type: "Size", Name: "HUGE", Reason: "use LARGE"},
+				{Type: "City", Name: "population", Reason: "use census"},
+			}
+			if diff := cmp.Diff(got, exp); diff != "" || op.Name != "Cities" {
+				t.Fatalf("\t%s\tTest %d:\tShould report each deprecation once for %s. Diff:\n%s", failed, testID, op, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report each deprecation once.", success, testID)
+
+			got = nil
+			if err := gql.Execute(context.Background(), `query { queryCity { census } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if got != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould not warn for current fields: %v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not warn for current fields.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+// Package dgraphgrpc provides a graphql.Transport that delivers DQL requests
+// to Dgraph over its gRPC API using the dgo client. Swap the transport in
+// with graphql.WithTransport and the QueryDQL, MutateDQL, ExecuteUpsert,
+// CommitDQL and DiscardDQL call sites keep working unchanged.
+//
+// Dgraph only provides DQL over gRPC, so requests to the graphql and admin
+// endpoints are rejected by this transport.
+package dgraphgrpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/dgraph-io/dgo/v230"
+	"github.com/dgraph-io/dgo/v230/protos/api"
+	"google.golang.org/grpc"
+)
+
+// Transport delivers DQL requests over gRPC. Transactions that are left
+// open by a mutation are tracked by their start timestamp so later
+// mutations, commits and discards in that transaction use the same dgo
+// transaction.
+type Transport struct {
+	dg   *dgo.Dgraph
+	conn *grpc.ClientConn
+
+	mu   sync.Mutex
+	txns map[uint64]*dgo.Txn
+}
+
+// New constructs a Transport using the specified dgo client. Any login
+// must already have been performed on the client.
+func New(dg *dgo.Dgraph) *Transport {
+	return &Transport{
+		dg:   dg,
+		txns: make(map[uint64]*dgo.Txn),
+	}
+}
+
+// Dial connects to the Dgraph alpha at the specified gRPC address and
+// constructs a Transport for it. Close releases the connection.
+func Dial(addr string, opts ...grpc.DialOption) (*Transport, error) {
+	conn, err := grpc.Dial(addr, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("graphql grpc error: %w", err)
+	}
+
+	t := New(dgo.NewDgraphClient(api.NewDgraphClient(conn)))
+	t.conn = conn
+
+	return t, nil
+}
+
+// Close releases the connection opened by Dial.
+func (t *Transport) Close() error {
+	if t.conn == nil {
+		return nil
+	}
+	return t.conn.Close()
+}
+
+// Do implements the graphql.Transport interface.
+func (t *Transport) Do(ctx context.Context, req graphql.Request) (graphql.Response, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return graphql.Response{}, fmt.Errorf("graphql grpc error: %w", err)
+	}
+	params := u.Query()
+
+	var body []byte
+	if req.Body != nil {
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return graphql.Response{}, fmt.Errorf("graphql grpc error: %w", err)
+		}
+	}
+
+	endpoint := req.Endpoint
+	if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+		endpoint = endpoint[:i]
+	}
+
+	var data interface{}
+	switch endpoint {
+	case "query":
+		data, err = t.query(ctx, params, body)
+	case "mutate":
+		data, err = t.mutate(ctx, params, req.Header.Get("Content-Type"), body)
+	case "commit":
+		data, err = t.commit(ctx, params)
+	case "alter":
+		data, err = t.alter(ctx, body)
+	default:
+		return graphql.Response{}, fmt.Errorf("graphql grpc error: the %s endpoint isn't supported over gRPC", endpoint)
+	}
+	if err != nil {
+		return graphql.Response{}, err
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return graphql.Response{}, fmt.Errorf("graphql grpc error: %w", err)
+	}
+
+	resp := graphql.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(b)),
+	}
+
+	return resp, nil
+}
+
+// =============================================================================
+
+// result represents the http response document Dgraph produces, so the
+// client decodes gRPC results the same way.
+type result struct {
+	Data       interface{} `json:"data"`
+	Extensions extensions  `json:"extensions"`
+}
+
+// extensions holds the transaction information.
+type extensions struct {
+	Txn txn `json:"txn"`
+}
+
+// txn mirrors the transaction information in the http response.
+type txn struct {
+	StartTs  uint64   `json:"start_ts"`
+	CommitTs uint64   `json:"commit_ts,omitempty"`
+	Aborted  bool     `json:"aborted,omitempty"`
+	Keys     []string `json:"keys,omitempty"`
+	Preds    []string `json:"preds,omitempty"`
+}
+
+// newTxn converts the gRPC transaction context.
+func newTxn(tc *api.TxnContext) txn {
+	if tc == nil {
+		return txn{}
+	}
+	return txn{
+		StartTs:  tc.StartTs,
+		CommitTs: tc.CommitTs,
+		Aborted:  tc.Aborted,
+		Keys:     tc.Keys,
+		Preds:    tc.Preds,
+	}
+}
+
+// query executes a DQL query in a read-only transaction.
+func (t *Transport) query(ctx context.Context, params url.Values, body []byte) (interface{}, error) {
+	var request struct {
+		Query     string            `json:"query"`
+		Variables map[string]string `json:"variables"`
+	}
+	if err := json.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("graphql grpc error: %w", err)
+	}
+
+	tx := t.dg.NewReadOnlyTxn()
+	if params.Get("be") == "true" {
+		tx = tx.BestEffort()
+	}
+	defer tx.Discard(ctx)
+
+	resp, err := tx.Do(ctx, &api.Request{Query: request.Query, Vars: request.Variables})
+	if err != nil {
+		return nil, fmt.Errorf("graphql grpc error: %w", err)
+	}
+
+	return result{Data: json.RawMessage(resp.Json), Extensions: extensions{Txn: newTxn(resp.Txn)}}, nil
+}
+
+// mutate executes a JSON or RDF mutation, continuing the transaction named
+// by the startTs parameter if one is provided.
+func (t *Transport) mutate(ctx context.Context, params url.Values, contentType string, body []byte) (interface{}, error) {
+	req := api.Request{
+		CommitNow: params.Get("commitNow") == "true",
+	}
+
+	switch contentType {
+	case "application/rdf":
+		query, mutations, err := parseRDF(string(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Query = query
+		for _, m := range mutations {
+			req.Mutations = append(req.Mutations, &api.Mutation{
+				Cond:      m.cond,
+				SetNquads: []byte(m.set),
+				DelNquads: []byte(m.del),
+			})
+		}
+
+	default:
+		type mutation struct {
+			Cond   string          `json:"cond"`
+			Set    json.RawMessage `json:"set"`
+			Delete json.RawMessage `json:"delete"`
+		}
+		var request struct {
+			mutation
+			Query     string     `json:"query"`
+			Mutations []mutation `json:"mutations"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			return nil, fmt.Errorf("graphql grpc error: %w", err)
+		}
+
+		mutations := request.Mutations
+		if len(mutations) == 0 {
+			mutations = []mutation{request.mutation}
+		}
+
+		req.Query = request.Query
+		for _, m := range mutations {
+			req.Mutations = append(req.Mutations, &api.Mutation{
+				Cond:       m.Cond,
+				SetJson:    m.Set,
+				DeleteJson: m.Delete,
+			})
+		}
+	}
+
+	tx, err := t.txn(params)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := tx.Do(ctx, &req)
+	if err != nil {
+		t.forget(params)
+		return nil, fmt.Errorf("graphql grpc error: %w", err)
+	}
+
+	if !req.CommitNow && resp.Txn != nil {
+		t.mu.Lock()
+		t.txns[resp.Txn.StartTs] = tx
+		t.mu.Unlock()
+	}
+
+	data := struct {
+		Code    string            `json:"code"`
+		Message string            `json:"message"`
+		Queries json.RawMessage   `json:"queries,omitempty"`
+		UIDs    map[string]string `json:"uids"`
+	}{
+		Code:    "Success",
+		Message: "Done",
+		UIDs:    resp.Uids,
+	}
+	if len(resp.Json) > 0 {
+		data.Queries = json.RawMessage(resp.Json)
+	}
+
+	return result{Data: data, Extensions: extensions{Txn: newTxn(resp.Txn)}}, nil
+}
+
+// commit commits or discards the transaction named by the startTs
+// parameter.
+func (t *Transport) commit(ctx context.Context, params url.Values) (interface{}, error) {
+	tx, err := t.txn(params)
+	if err != nil {
+		return nil, err
+	}
+	defer t.forget(params)
+
+	if params.Get("abort") == "true" {
+		if err := tx.Discard(ctx); err != nil {
+			return nil, fmt.Errorf("graphql grpc error: %w", err)
+		}
+		return result{Data: map[string]string{"code": "Success", "message": "Done"}}, nil
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("graphql grpc error: %w", err)
+	}
+
+	return result{Data: map[string]string{"code": "Success", "message": "Done"}}, nil
+}
+
+// alter applies a schema or drop operation. A JSON body describes a drop
+// operation, anything else is treated as a DQL schema.
+func (t *Transport) alter(ctx context.Context, body []byte) (interface{}, error) {
+	op := api.Operation{Schema: string(body)}
+
+	var drop struct {
+		DropAll  bool   `json:"drop_all"`
+		DropAttr string `json:"drop_attr"`
+	}
+	if json.Unmarshal(body, &drop) == nil {
+		op = api.Operation{DropAll: drop.DropAll, DropAttr: drop.DropAttr}
+	}
+
+	if err := t.dg.Alter(ctx, &op); err != nil {
+		return nil, fmt.Errorf("graphql grpc error: %w", err)
+	}
+
+	return result{Data: map[string]string{"code": "Success", "message": "Done"}}, nil
+}
+
+// txn returns the open transaction named by the startTs parameter, or a
+// new transaction if there is no parameter.
+func (t *Transport) txn(params url.Values) (*dgo.Txn, error) {
+	value := params.Get("startTs")
+	if value == "" {
+		return t.dg.NewTxn(), nil
+	}
+
+	startTs, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("graphql grpc error: invalid startTs: %s", value)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tx, exists := t.txns[startTs]
+	if !exists {
+		return nil, fmt.Errorf("graphql grpc error: unknown transaction: %d", startTs)
+	}
+
+	return tx, nil
+}
+
+// forget stops tracking the transaction named by the startTs parameter.
+func (t *Transport) forget(params url.Values) {
+	startTs, err := strconv.ParseUint(params.Get("startTs"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	delete(t.txns, startTs)
+	t.mu.Unlock()
+}
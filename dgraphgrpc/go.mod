@@ -0,0 +1,25 @@
+module github.com/ardanlabs/graphql/dgraphgrpc
+
+go 1.19
+
+require (
+	github.com/ardanlabs/graphql v0.0.0
+	github.com/dgraph-io/dgo/v230 v230.0.1
+	google.golang.org/grpc v1.27.0
+)
+
+require (
+	github.com/agnivade/levenshtein v1.1.1 // indirect
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/golang/protobuf v1.4.1 // indirect
+	github.com/gorilla/websocket v1.5.0 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	github.com/vektah/gqlparser/v2 v2.5.11 // indirect
+	golang.org/x/net v0.7.0 // indirect
+	golang.org/x/sys v0.5.0 // indirect
+	golang.org/x/text v0.7.0 // indirect
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
+	google.golang.org/protobuf v1.25.0 // indirect
+)
+
+replace github.com/ardanlabs/graphql => ../
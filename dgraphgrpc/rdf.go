@@ -0,0 +1,162 @@
+package dgraphgrpc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rdfMutation represents a single mutation parsed from an RDF request.
+type rdfMutation struct {
+	cond string
+	set  string
+	del  string
+}
+
+// parseRDF parses the RDF mutation formats the Dgraph /mutate endpoint
+// accepts, a plain block of set and delete blocks or an upsert block with a
+// query and conditional mutations, returning the query and mutations.
+func parseRDF(body string) (string, []rdfMutation, error) {
+	body = strings.TrimSpace(body)
+
+	if !strings.HasPrefix(body, "upsert") {
+		inner, _, err := block(body, 0)
+		if err != nil {
+			return "", nil, err
+		}
+		m, err := parseMutation(inner)
+		if err != nil {
+			return "", nil, err
+		}
+		return "", []rdfMutation{m}, nil
+	}
+
+	inner, _, err := block(body, len("upsert"))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var query string
+	var mutations []rdfMutation
+	for i := 0; ; {
+		i = skipSpace(inner, i)
+		if i >= len(inner) {
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(inner[i:], "query"):
+			start := strings.IndexByte(inner[i:], '{')
+			if start < 0 {
+				return "", nil, fmt.Errorf("graphql rdf error: query block has no body")
+			}
+			content, end, err := block(inner, i+start)
+			if err != nil {
+				return "", nil, err
+			}
+			query = "{" + content + "}"
+			i = end
+
+		case strings.HasPrefix(inner[i:], "mutation"):
+			start := strings.IndexByte(inner[i:], '{')
+			if start < 0 {
+				return "", nil, fmt.Errorf("graphql rdf error: mutation block has no body")
+			}
+			cond := strings.TrimSpace(inner[i+len("mutation") : i+start])
+			content, end, err := block(inner, i+start)
+			if err != nil {
+				return "", nil, err
+			}
+			m, err := parseMutation(content)
+			if err != nil {
+				return "", nil, err
+			}
+			m.cond = cond
+			mutations = append(mutations, m)
+			i = end
+
+		default:
+			return "", nil, fmt.Errorf("graphql rdf error: unexpected input at %q", snippet(inner[i:]))
+		}
+	}
+
+	return query, mutations, nil
+}
+
+// parseMutation parses the set and delete blocks of a mutation.
+func parseMutation(s string) (rdfMutation, error) {
+	var m rdfMutation
+	for i := 0; ; {
+		i = skipSpace(s, i)
+		if i >= len(s) {
+			return m, nil
+		}
+
+		var target *string
+		switch {
+		case strings.HasPrefix(s[i:], "set"):
+			target, i = &m.set, i+len("set")
+		case strings.HasPrefix(s[i:], "delete"):
+			target, i = &m.del, i+len("delete")
+		default:
+			return m, fmt.Errorf("graphql rdf error: unexpected input at %q", snippet(s[i:]))
+		}
+
+		content, end, err := block(s, i)
+		if err != nil {
+			return m, err
+		}
+		*target += strings.TrimSpace(content) + "\n"
+		i = end
+	}
+}
+
+// block returns the content of the brace delimited block that starts at or
+// after the specified position and the position following the block.
+// Braces inside quoted strings and IRIs are ignored.
+func block(s string, i int) (string, int, error) {
+	i = skipSpace(s, i)
+	if i >= len(s) || s[i] != '{' {
+		return "", 0, fmt.Errorf("graphql rdf error: expected {")
+	}
+
+	depth := 0
+	for j := i; j < len(s); j++ {
+		switch s[j] {
+		case '"':
+			for j++; j < len(s) && s[j] != '"'; j++ {
+				if s[j] == '\\' {
+					j++
+				}
+			}
+		case '<':
+			if k := strings.IndexByte(s[j:], '>'); k > 0 && !strings.ContainsAny(s[j:j+k], " \n\t") {
+				j += k
+			}
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return s[i+1 : j], j + 1, nil
+			}
+		}
+	}
+
+	return "", 0, fmt.Errorf("graphql rdf error: unterminated block")
+}
+
+// skipSpace returns the position of the next non-space character.
+func skipSpace(s string, i int) int {
+	for i < len(s) && strings.ContainsRune(" \t\r\n", rune(s[i])) {
+		i++
+	}
+	return i
+}
+
+// snippet shortens the input for error messages.
+func snippet(s string) string {
+	if len(s) > 20 {
+		return s[:20] + "..."
+	}
+	return s
+}
@@ -0,0 +1,53 @@
+package dgraphgrpc
+
+import (
+	"testing"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestParseRDF(t *testing.T) {
+	t.Log("Given the need to translate RDF requests for gRPC.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen parsing a plain mutation.", testID)
+		{
+			query, mutations, err := parseRDF("{\n\tset {\n_:a <name> \"Bill {x}\" .\n\t}\n\tdelete {\n<0x1> <name> * .\n\t}\n}\n")
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to parse the mutation: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to parse the mutation.", success, testID)
+
+			if query != "" || len(mutations) != 1 || mutations[0].set != "_:a <name> \"Bill {x}\" .\n" || mutations[0].del != "<0x1> <name> * .\n" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the set and delete blocks: %q %+v", failed, testID, query, mutations)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the set and delete blocks.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen parsing an upsert block.", testID)
+		{
+			body := "upsert {\n\tquery {\n\t\tuser(func: eq(email, \"bill@ardanlabs.com\")) { u as uid }\n\t}\n\tmutation @if(eq(len(u), 0)) {\n\t\tset {\nuid(u) <name> \"Bill\" .\n\t\t}\n\t}\n}\n"
+
+			query, mutations, err := parseRDF(body)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to parse the upsert: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to parse the upsert.", success, testID)
+
+			if query != "{\n\t\tuser(func: eq(email, \"bill@ardanlabs.com\")) { u as uid }\n\t}" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the query: %q", failed, testID, query)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the query.", success, testID)
+
+			if len(mutations) != 1 || mutations[0].cond != "@if(eq(len(u), 0))" || mutations[0].set != "uid(u) <name> \"Bill\" .\n" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the conditional mutation: %+v", failed, testID, mutations)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the conditional mutation.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Set of kinds of changes reported by Diff.
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// ChangeKind represents the kind of a change between two results.
+type ChangeKind string
+
+// Change represents a difference between two results. Path locates the
+// value, like getCity.people[2].name, and is empty for the result itself.
+// Old and New hold the JSON form of the value before and after the change,
+// with numbers kept as json.Number.
+type Change struct {
+	Path string
+	Kind ChangeKind
+	Old  interface{}
+	New  interface{}
+}
+
+// String implements the fmt.Stringer interface.
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s %s: %v", c.Kind, c.Path, c.New)
+	case ChangeRemoved:
+		return fmt.Sprintf("%s %s: %v", c.Kind, c.Path, c.Old)
+	}
+	return fmt.Sprintf("%s %s: %v -> %v", c.Kind, c.Path, c.Old, c.New)
+}
+
+// Diff compares two results structurally and returns the paths that were
+// added, removed, or changed, ordered by path. The results can be decoded
+// values of any type or the raw JSON as a json.RawMessage; both are compared
+// in their JSON form. Lists are compared element by element, so an item
+// inserted at the front of a list reports every following element.
+func Diff(old interface{}, new interface{}) ([]Change, error) {
+	oldTree, err := jsonTree(old)
+	if err != nil {
+		return nil, fmt.Errorf("graphql diff error: %w", err)
+	}
+
+	newTree, err := jsonTree(new)
+	if err != nil {
+		return nil, fmt.Errorf("graphql diff error: %w", err)
+	}
+
+	var changes []Change
+	diffTree("", oldTree, newTree, &changes)
+
+	return changes, nil
+}
+
+// diffTree appends the changes between the two trees at the path.
+func diffTree(path string, old interface{}, new interface{}, changes *[]Change) {
+	switch o := old.(type) {
+	case map[string]interface{}:
+		n, ok := new.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(o)+len(n))
+		for key := range o {
+			keys = append(keys, key)
+		}
+		for key := range n {
+			if _, ok := o[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			field := key
+			if path != "" {
+				field = path + "." + key
+			}
+
+			ov, inOld := o[key]
+			nv, inNew := n[key]
+			switch {
+			case !inOld:
+				*changes = append(*changes, Change{Path: field, Kind: ChangeAdded, New: nv})
+			case !inNew:
+				*changes = append(*changes, Change{Path: field, Kind: ChangeRemoved, Old: ov})
+			default:
+				diffTree(field, ov, nv, changes)
+			}
+		}
+		return
+
+	case []interface{}:
+		n, ok := new.([]interface{})
+		if !ok {
+			break
+		}
+
+		for i := 0; i < len(o) || i < len(n); i++ {
+			item := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(o):
+				*changes = append(*changes, Change{Path: item, Kind: ChangeAdded, New: n[i]})
+			case i >= len(n):
+				*changes = append(*changes, Change{Path: item, Kind: ChangeRemoved, Old: o[i]})
+			default:
+				diffTree(item, o[i], n[i], changes)
+			}
+		}
+		return
+
+	default:
+		switch new.(type) {
+		case map[string]interface{}, []interface{}:
+		default:
+			if old == new {
+				return
+			}
+		}
+	}
+
+	*changes = append(*changes, Change{Path: path, Kind: ChangeModified, Old: old, New: new})
+}
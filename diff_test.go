@@ -0,0 +1,85 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestDiff(t *testing.T) {
+	type person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	type city struct {
+		Name   string   `json:"name"`
+		People []person `json:"people"`
+		Mayor  *person  `json:"mayor,omitempty"`
+	}
+
+	tt := []struct {
+		name string
+		old  interface{}
+		new  interface{}
+		exp  string
+	}{
+		{
+			name: "unchanged",
+			old:  city{Name: "Miami", People: []person{{"bill", 40}}},
+			new:  city{Name: "Miami", People: []person{{"bill", 40}}},
+			exp:  "[]",
+		},
+		{
+			name: "modified",
+			old:  city{Name: "Miami", People: []person{{"bill", 40}, {"jill", 35}}},
+			new:  city{Name: "Miami", People: []person{{"bill", 41}, {"jill", 35}}},
+			exp:  "[modified people[0].age: 40 -> 41]",
+		},
+		{
+			name: "added",
+			old:  city{Name: "Miami", People: []person{{"bill", 40}}},
+			new:  city{Name: "Miami", People: []person{{"bill", 40}, {"jill", 35}}, Mayor: &person{"ed", 60}},
+			exp:  "[added mayor: map[age:60 name:ed] added people[1]: map[age:35 name:jill]]",
+		},
+		{
+			name: "removed",
+			old:  city{Name: "Miami", People: []person{{"bill", 40}, {"jill", 35}}, Mayor: &person{"ed", 60}},
+			new:  city{Name: "Miami", People: []person{{"bill", 40}}},
+			exp:  "[removed mayor: map[age:60 name:ed] removed people[1]: map[age:35 name:jill]]",
+		},
+		{
+			name: "raw",
+			old:  json.RawMessage(`{"name": "Miami", "people": null}`),
+			new:  city{Name: "Miami"},
+			exp:  "[]",
+		},
+		{
+			name: "type",
+			old:  json.RawMessage(`{"name": "Miami", "people": []}`),
+			new:  city{Name: "Miami"},
+			exp:  "[modified people: [] -> <nil>]",
+		},
+	}
+
+	t.Log("Given the need to compare consecutive results.")
+	{
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen comparing %s results.", testID, test.name)
+			{
+				changes, err := graphql.Diff(test.old, test.new)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to diff the results : %s", failed, testID, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to diff the results.", success, testID)
+
+				if got := fmt.Sprint(changes); got != test.exp {
+					t.Fatalf("\t%s\tTest %d:\tShould report the changes: got %s, exp %s", failed, testID, got, test.exp)
+				}
+				t.Logf("\t%s\tTest %d:\tShould report the changes.", success, testID)
+			}
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package graphql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AddDirective returns a copy of the document with the directive added to
+// the fields at the specified path in every operation, after any
+// directives they already have. The path is the dotted list of response
+// keys from the root of the operation, like queryCity.posts, where a key
+// is the alias of a field if it has one. Inline fragments are part of the
+// path of their parent and fragment definitions aren't searched.
+func AddDirective(document string, path string, directive string) (string, error) {
+	directive = strings.TrimSpace(directive)
+	if !strings.HasPrefix(directive, "@") {
+		return "", fmt.Errorf("graphql directive error: %q doesn't start with @", directive)
+	}
+	if _, err := lex(directive); err != nil {
+		return "", err
+	}
+
+	doc, err := parseSelections(document)
+	if err != nil {
+		return "", err
+	}
+
+	var offsets []int
+	for _, op := range doc.operations {
+		offsets = append(offsets, fieldOffsets(op, "", path)...)
+	}
+	if len(offsets) == 0 {
+		return "", fmt.Errorf("graphql directive error: field %q not found", path)
+	}
+
+	// Insert from the end so earlier offsets remain valid.
+	sort.Sort(sort.Reverse(sort.IntSlice(offsets)))
+	for _, offset := range offsets {
+		document = document[:offset] + " " + directive + document[offset:]
+	}
+
+	return document, nil
+}
+
+// AddCascade adds the Dgraph @cascade directive to the fields at the path,
+// so nodes that lack any of the specified fields, or any requested field
+// when none are specified, are removed from the results.
+func AddCascade(document string, path string, fields ...string) (string, error) {
+	if len(fields) == 0 {
+		return AddDirective(document, path, "@cascade")
+	}
+
+	quoted := make([]string, len(fields))
+	for i, field := range fields {
+		quoted[i] = strconv.Quote(field)
+	}
+	return AddDirective(document, path, "@cascade(fields: ["+strings.Join(quoted, ", ")+"])")
+}
+
+// AddInclude adds the @include directive to the fields at the path so they
+// are only selected when include is true.
+func AddInclude(document string, path string, include bool) (string, error) {
+	return AddDirective(document, path, "@include(if: "+strconv.FormatBool(include)+")")
+}
+
+// AddSkip adds the @skip directive to the fields at the path so they are
+// not selected when skip is true.
+func AddSkip(document string, path string, skip bool) (string, error) {
+	return AddDirective(document, path, "@skip(if: "+strconv.FormatBool(skip)+")")
+}
+
+// fieldOffsets returns the offsets just past the arguments and directives
+// of the fields at the path, where prefix is the path of the parent field.
+func fieldOffsets(nodes []fieldNode, prefix string, path string) []int {
+	var offsets []int
+	for _, n := range nodes {
+		if n.spread != "" {
+			continue
+		}
+		p := prefix + n.key
+		if p == path {
+			offsets = append(offsets, n.end)
+		}
+		offsets = append(offsets, fieldOffsets(n.children, p+".", path)...)
+	}
+	return offsets
+}
@@ -0,0 +1,81 @@
+package graphql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAddDirective(t *testing.T) {
+	t.Log("Given the need to add directives to the fields of a document.")
+	{
+		const document = `query GetCity($id: ID!, $in: Filter = {name: "a"}) {
+	city: getCity(id: $id) @auth {
+		name
+		posts(first: 10) { title }
+		... on City { mayor { name } }
+	}
+}
+
+fragment F on City { posts { title } }`
+
+		tt := []struct {
+			name string
+			fn   func() (string, error)
+			exp  string
+		}{
+			{
+				"cascade on an aliased field",
+				func() (string, error) { return graphql.AddCascade(document, "city") },
+				`city: getCity(id: $id) @auth @cascade {`,
+			},
+			{
+				"cascade with fields",
+				func() (string, error) { return graphql.AddCascade(document, "city.posts", "title") },
+				`posts(first: 10) @cascade(fields: ["title"]) { title }`,
+			},
+			{
+				"include inside an inline fragment",
+				func() (string, error) { return graphql.AddInclude(document, "city.mayor", false) },
+				`... on City { mayor @include(if: false) { name } }`,
+			},
+			{
+				"skip on a leaf field",
+				func() (string, error) { return graphql.AddSkip(document, "city.name", true) },
+				"\t\tname @skip(if: true)\n",
+			},
+		}
+
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen adding %s.", testID, test.name)
+			{
+				got, err := test.fn()
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to add the directive: %v", failed, testID, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to add the directive.", success, testID)
+
+				if !strings.Contains(got, test.exp) {
+					t.Fatalf("\t%s\tTest %d:\tShould get the expected document: %s", failed, testID, got)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the expected document.", success, testID)
+
+				if diff := cmp.Diff(got[len(got)-len("fragment F on City { posts { title } }"):], "fragment F on City { posts { title } }"); diff != "" {
+					t.Fatalf("\t%s\tTest %d:\tShould leave fragment definitions alone. Diff:\n%s", failed, testID, diff)
+				}
+				t.Logf("\t%s\tTest %d:\tShould leave fragment definitions alone.", success, testID)
+			}
+		}
+
+		testID := len(tt)
+		t.Logf("\tTest %d:\tWhen the path doesn't exist.", testID)
+		{
+			if _, err := graphql.AddSkip(document, "city.population", true); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get an error.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an error.", success, testID)
+		}
+	}
+}
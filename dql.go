@@ -0,0 +1,247 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// DQLTxn represents the transaction information Dgraph returns in the
+// extensions of a DQL response.
+type DQLTxn struct {
+	StartTs  uint64   `json:"start_ts"`
+	CommitTs uint64   `json:"commit_ts"`
+	Aborted  bool     `json:"aborted"`
+	Keys     []string `json:"keys"`
+	Preds    []string `json:"preds"`
+}
+
+// DQLMutation represents a mutation against the Dgraph /mutate endpoint.
+// Set and Delete are JSON mutations and SetNQuads and DelNQuads are RDF
+// mutations. JSON and RDF mutations can't be mixed in a single mutation.
+// If StartTs is set, the mutation is performed in that transaction.
+type DQLMutation struct {
+	Set       interface{}
+	Delete    interface{}
+	SetNQuads string
+	DelNQuads string
+	CommitNow bool
+	StartTs   uint64
+}
+
+// DQLMutationResult represents the result of a DQL mutation.
+type DQLMutationResult struct {
+	Code    string            `json:"code"`
+	Message string            `json:"message"`
+	UIDs    map[string]string `json:"uids"`
+	Queries json.RawMessage   `json:"queries"`
+	Txn     DQLTxn            `json:"-"`
+}
+
+// ReadOnly returns a copy of the context that marks the DQL query made with
+// it as read-only. Dgraph can serve read-only queries without starting a
+// transaction.
+func ReadOnly(ctx context.Context) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.readOnly = true
+	})
+}
+
+// BestEffort returns a copy of the context that marks the DQL query made
+// with it as best-effort. Best-effort queries are read-only and skip the
+// timestamp request to Zero, trading strict consistency for latency. This
+// is a good fit for analytics queries.
+func BestEffort(ctx context.Context) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.readOnly = true
+		opts.bestEffort = true
+	})
+}
+
+// QueryDQL performs a DQL query against the configured host on the
+// url/query endpoint. Variable names are prefixed with $ if required and
+// values are sent as strings as DQL requires. Use the ReadOnly and
+// BestEffort functions to set the query options for a call.
+func (g *GraphQL) QueryDQL(ctx context.Context, dql string, response interface{}, variables ...func(m map[string]interface{})) error {
+	request := struct {
+		Query     string            `json:"query"`
+		Variables map[string]string `json:"variables,omitempty"`
+	}{
+		Query: dql,
+	}
+
+	if len(variables) > 0 {
+		queryVars := make(map[string]interface{})
+		for _, variable := range variables {
+			variable(queryVars)
+		}
+
+		request.Variables = make(map[string]string, len(queryVars))
+		for key, value := range queryVars {
+			if !strings.HasPrefix(key, "$") {
+				key = "$" + key
+			}
+			request.Variables[key] = fmt.Sprint(value)
+		}
+	}
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(request); err != nil {
+		return fmt.Errorf("graphql encoding error: %w", err)
+	}
+
+	params := url.Values{}
+	if opts := callOpts(ctx); opts.readOnly {
+		params.Set("ro", "true")
+		if opts.bestEffort {
+			params.Set("be", "true")
+		}
+	}
+
+	endpoint := "query"
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	return g.RawRequest(ctx, endpoint, &b, response)
+}
+
+// MutateDQL performs a DQL mutation against the configured host on the
+// url/mutate endpoint.
+func (g *GraphQL) MutateDQL(ctx context.Context, m DQLMutation) (*DQLMutationResult, error) {
+	var b bytes.Buffer
+	contentType := "application/json"
+
+	switch {
+	case m.SetNQuads != "" || m.DelNQuads != "":
+		if m.Set != nil || m.Delete != nil {
+			return nil, fmt.Errorf("graphql mutation error: JSON and RDF mutations can't be mixed")
+		}
+
+		contentType = "application/rdf"
+		b.WriteString("{\n")
+		if m.SetNQuads != "" {
+			fmt.Fprintf(&b, "\tset {\n%s\n\t}\n", m.SetNQuads)
+		}
+		if m.DelNQuads != "" {
+			fmt.Fprintf(&b, "\tdelete {\n%s\n\t}\n", m.DelNQuads)
+		}
+		b.WriteString("}\n")
+
+	default:
+		request := struct {
+			Set    interface{} `json:"set,omitempty"`
+			Delete interface{} `json:"delete,omitempty"`
+		}{
+			Set:    m.Set,
+			Delete: m.Delete,
+		}
+		if err := json.NewEncoder(&b).Encode(request); err != nil {
+			return nil, fmt.Errorf("graphql encoding error: %w", err)
+		}
+	}
+
+	var result DQLMutationResult
+	if err := g.mutate(ctx, contentType, m.CommitNow, m.StartTs, &b, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// CommitDQL commits the transaction started by mutations that were not
+// committed immediately.
+func (g *GraphQL) CommitDQL(ctx context.Context, txn DQLTxn) error {
+	return g.finishTxn(ctx, txn, false)
+}
+
+// DiscardDQL aborts the transaction started by mutations that were not
+// committed immediately.
+func (g *GraphQL) DiscardDQL(ctx context.Context, txn DQLTxn) error {
+	return g.finishTxn(ctx, txn, true)
+}
+
+// mutate sends the mutation body to the url/mutate endpoint and captures
+// the transaction information from the extensions.
+func (g *GraphQL) mutate(ctx context.Context, contentType string, commitNow bool, startTs uint64, body *bytes.Buffer, result *DQLMutationResult) error {
+	params := url.Values{}
+	if commitNow {
+		params.Set("commitNow", "true")
+	}
+	if startTs != 0 {
+		params.Set("startTs", strconv.FormatUint(startTs, 10))
+	}
+
+	endpoint := "mutate"
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+
+	var extensions json.RawMessage
+	ctx = withCallOption(ctx, func(opts *callOptions) {
+		opts.contentType = contentType
+		opts.extensions = &extensions
+	})
+
+	send := func() error {
+		return g.RawRequest(ctx, endpoint, bytes.NewReader(body.Bytes()), result)
+	}
+
+	run := send
+	if g.abortRetries > 0 && commitNow && startTs == 0 {
+		run = func() error {
+			return g.retryAborted(ctx, send)
+		}
+	}
+
+	var err error
+	if g.audit != nil {
+		err = g.audited(ctx, endpoint, Operation{Type: "mutation"}, nil, run)
+	} else {
+		err = run()
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(extensions) > 0 {
+		var ext struct {
+			Txn DQLTxn `json:"txn"`
+		}
+		if err := json.Unmarshal(extensions, &ext); err != nil {
+			return fmt.Errorf("graphql decoding error: %w response: %s", err, excerpt(extensions, g.errorBodyLimit))
+		}
+		result.Txn = ext.Txn
+	}
+
+	return nil
+}
+
+// finishTxn commits or aborts the transaction on the url/commit endpoint.
+func (g *GraphQL) finishTxn(ctx context.Context, txn DQLTxn, abort bool) error {
+	params := url.Values{}
+	params.Set("startTs", strconv.FormatUint(txn.StartTs, 10))
+	if abort {
+		params.Set("abort", "true")
+	}
+
+	request := struct {
+		Keys  []string `json:"keys"`
+		Preds []string `json:"preds"`
+	}{
+		Keys:  txn.Keys,
+		Preds: txn.Preds,
+	}
+
+	var b bytes.Buffer
+	if err := json.NewEncoder(&b).Encode(request); err != nil {
+		return fmt.Errorf("graphql encoding error: %w", err)
+	}
+
+	var response json.RawMessage
+	return g.RawRequest(ctx, "commit?"+params.Encode(), &b, &response)
+}
@@ -0,0 +1,125 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDQL(t *testing.T) {
+	t.Log("Given the need to execute DQL queries and mutations.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen executing a DQL query with variables.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+
+				exp := `{"query":"query q($name: string) { q(func: eq(name, $name)) { uid } }","variables":{"$name":"Miami"}}` + "\n"
+				if diff := cmp.Diff(string(b), exp); r.URL.Path != "/query" || diff != "" {
+					t.Fatalf("\t%s\tTest %d:\tShould get the expected request on %s. Diff:\n%s", failed, testID, r.URL.Path, diff)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the expected request.", success, testID)
+
+				io.WriteString(w, `{"data": {"q": [{"uid": "0x01"}]}, "extensions": {"txn": {"start_ts": 10}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			var got struct {
+				Q []struct {
+					UID string `json:"uid"`
+				} `json:"q"`
+			}
+			err := gql.QueryDQL(context.Background(), `query q($name: string) { q(func: eq(name, $name)) { uid } }`, &got,
+				graphql.WithVariable("name", "Miami"),
+			)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			if len(got.Q) != 1 || got.Q[0].UID != "0x01" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the expected result: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the expected result.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen executing a best-effort DQL query.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("ro") != "true" || r.URL.Query().Get("be") != "true" {
+					t.Fatalf("\t%s\tTest %d:\tShould get the query options: %s", failed, testID, r.URL.RawQuery)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the query options.", success, testID)
+
+				io.WriteString(w, `{"data": {"q": []}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			ctx := graphql.BestEffort(context.Background())
+			if err := gql.QueryDQL(ctx, `{ q(func: has(name)) { uid } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen executing an RDF mutation.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+
+				if r.URL.Path != "/mutate" || r.URL.Query().Get("commitNow") != "true" {
+					t.Fatalf("\t%s\tTest %d:\tShould call mutate with commitNow: %s", failed, testID, r.URL)
+				}
+				if ct := r.Header.Get("Content-Type"); ct != "application/rdf" {
+					t.Fatalf("\t%s\tTest %d:\tShould send the RDF content type: %s", failed, testID, ct)
+				}
+				exp := "{\n\tset {\n_:city <name> \"Miami\" .\n\t}\n}\n"
+				if diff := cmp.Diff(string(b), exp); diff != "" {
+					t.Fatalf("\t%s\tTest %d:\tShould get the expected mutation. Diff:\n%s", failed, testID, diff)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the expected mutation.", success, testID)
+
+				io.WriteString(w, `{
+					"data": {"code": "Success", "message": "Done", "uids": {"city": "0x02"}},
+					"extensions": {"txn": {"start_ts": 11, "commit_ts": 12}}
+				}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			result, err := gql.MutateDQL(context.Background(), graphql.DQLMutation{
+				SetNQuads: `_:city <name> "Miami" .`,
+				CommitNow: true,
+			})
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the mutation: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the mutation.", success, testID)
+
+			if result.UIDs["city"] != "0x02" || result.Txn.CommitTs != 12 {
+				t.Fatalf("\t%s\tTest %d:\tShould get the uids and transaction: %+v", failed, testID, result)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the uids and transaction.", success, testID)
+		}
+	}
+}
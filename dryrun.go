@@ -0,0 +1,79 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+)
+
+// DryRunStub produces the body of the response for a request that isn't
+// sent in dry-run mode. The body is decoded like a response from the host,
+// so it should be a graphql response like {"data": {...}}.
+type DryRunStub func(ctx context.Context, req Request, body []byte) ([]byte, error)
+
+// emptyResponse is the response used in dry-run mode when no stub is
+// provided.
+var emptyResponse = []byte(`{"data": null}`)
+
+// WithDryRun enables dry-run mode. Requests are encoded, validated, and
+// logged as usual, but instead of being sent the stub produces the
+// response. This is useful for verifying migration scripts and for load
+// test harnesses. The stub receives the body as it would be sent, and its
+// responses are never cached. When the stub is nil every response is empty.
+func WithDryRun(stub DryRunStub) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.dryRun = dryRunStub(stub)
+	}
+}
+
+// DryRun returns a copy of the context that runs the call made with it in
+// dry-run mode. See WithDryRun.
+func DryRun(ctx context.Context, stub DryRunStub) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.dryRun = dryRunStub(stub)
+	})
+}
+
+// dryRunStub replaces a nil stub with one that returns an empty response.
+func dryRunStub(stub DryRunStub) DryRunStub {
+	if stub != nil {
+		return stub
+	}
+	return func(ctx context.Context, req Request, body []byte) ([]byte, error) {
+		return emptyResponse, nil
+	}
+}
+
+// dryRunFor returns the stub for the call, giving the per-call stub
+// precedence.
+func (g *GraphQL) dryRunFor(ctx context.Context) DryRunStub {
+	if stub := callOpts(ctx).dryRun; stub != nil {
+		return stub
+	}
+	return g.dryRun
+}
+
+// stubResponse produces the response for the request using the stub.
+func stubResponse(ctx context.Context, req Request, stub DryRunStub) (*Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, fmt.Errorf("graphql read request error: %w", err)
+		}
+	}
+
+	data, err := stub(ctx, req, body)
+	if err != nil {
+		return nil, fmt.Errorf("graphql dry run error: %w", err)
+	}
+
+	return &Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(data)),
+	}, nil
+}
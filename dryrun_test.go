@@ -0,0 +1,93 @@
+package graphql_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Log("Given the need to exercise requests without sending them.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the client is in dry-run mode.", testID)
+		{
+			var sent bool
+			f := func(w http.ResponseWriter, r *http.Request) {
+				sent = true
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			var body string
+			stub := func(ctx context.Context, req graphql.Request, b []byte) ([]byte, error) {
+				body = string(b)
+				return []byte(`{"data": {"addCity": {"numUids": 1}}}`), nil
+			}
+
+			var logged string
+			gql := graphql.New(server.URL,
+				graphql.WithDryRun(stub),
+				graphql.WithLogging(func(s string) { logged = s }),
+			)
+
+			var got struct {
+				AddCity struct {
+					NumUids int `json:"numUids"`
+				} `json:"addCity"`
+			}
+			if err := gql.Execute(context.Background(), `mutation { addCity(input: [{name: "Miami"}]) { numUids } }`, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the mutation: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the mutation.", success, testID)
+
+			if sent {
+				t.Fatalf("\t%s\tTest %d:\tShould not send the request.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not send the request.", success, testID)
+
+			if got.AddCity.NumUids != 1 || !strings.Contains(body, "addCity") {
+				t.Fatalf("\t%s\tTest %d:\tShould decode the stub response for the request: %+v %s", failed, testID, got, body)
+			}
+			t.Logf("\t%s\tTest %d:\tShould decode the stub response for the request.", success, testID)
+
+			if !strings.Contains(logged, "addCity") {
+				t.Fatalf("\t%s\tTest %d:\tShould log the request: %s", failed, testID, logged)
+			}
+			t.Logf("\t%s\tTest %d:\tShould log the request.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a single call is made in dry-run mode.", testID)
+		{
+			var sent int
+			f := func(w http.ResponseWriter, r *http.Request) {
+				sent++
+				w.Write([]byte(`{"data": {}}`))
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			ctx := graphql.DryRun(context.Background(), nil)
+			if err := gql.Execute(ctx, `query { city { name } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if err := gql.Execute(context.Background(), `query { city { name } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+
+			if sent != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould only skip the dry-run call: %d", failed, testID, sent)
+			}
+			t.Logf("\t%s\tTest %d:\tShould only skip the dry-run call.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,573 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithEntityCache enables a normalized cache for the responses of queries.
+// Every object in a response that selects __typename and id is stored once
+// as an entity, so when a later query or mutation returns a newer version
+// of an entity, the cached responses that include it see the new values.
+// Responses and entities are kept for the specified ttl. The scalar fields
+// of an entity are stored by their names and arguments, so aliases of the
+// same field share a value and the same field selected with different
+// arguments doesn't. Objects that aren't entities stay part of the response
+// that selected them. The entity cache takes precedence over WithCache.
+func WithEntityCache(ttl time.Duration) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.entities = newEntityCache(ttl)
+	}
+}
+
+// Touches returns a copy of the context that declares the types of the
+// entities the mutation made with it creates, updates, or deletes. When
+// the mutation is executed, the cached entities of the types and the cached
+// responses that include them are invalidated.
+func Touches(ctx context.Context, typenames ...string) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.touches = append(opts.touches[:len(opts.touches):len(opts.touches)], typenames...)
+	})
+}
+
+// InvalidateEntities removes the cached entities of the specified types and
+// the cached responses that include them.
+func (g *GraphQL) InvalidateEntities(typenames ...string) {
+	if g.entities == nil {
+		return
+	}
+	g.entities.invalidate(typenames)
+}
+
+// CachedEntity decodes the cached scalar fields of the entity with the
+// specified type and id into the value, so an entity already fetched by any
+// query can be read without a request. Fields are named as in the schema,
+// and fields selected with arguments are named with their arguments, like
+// posts(first:10). It reports false if the entity isn't cached.
+func (g *GraphQL) CachedEntity(typename string, id string, entity interface{}) (bool, error) {
+	if g.entities == nil {
+		return false, nil
+	}
+
+	fields, ok := g.entities.entity(typename, id)
+	if !ok {
+		return false, nil
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return false, fmt.Errorf("graphql entity cache error: %w", err)
+	}
+
+	if err := decodeData(data, entity, g.decodeHooks, g.errorBodyLimit); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// entityRequest returns the cached response for the request if one exists
+// and all the entities it includes are still cached, else it executes the
+// request and stores the response. A query that can't be parsed isn't
+// cached.
+func (g *GraphQL) entityRequest(ctx context.Context, key string, endpoint string, graphql string, queryVars map[string]interface{}, body []byte, response interface{}) error {
+	data, ok := g.entities.response(key)
+	if !ok {
+		var raw json.RawMessage
+		if err := g.execute(ctx, endpoint, body, true, &raw); err != nil {
+			return err
+		}
+
+		if sel, err := entitySelections(graphql, queryVars); err == nil {
+			if err := g.entities.store(key, raw, sel); err != nil {
+				return err
+			}
+		}
+		data = raw
+	}
+
+	return decodeData(data, response, g.decodeHooksFor(ctx), g.errorBodyLimit)
+}
+
+// entityMutation returns a function that executes the mutation, stores the
+// entities of its response, and invalidates the types it touches.
+func (g *GraphQL) entityMutation(ctx context.Context, endpoint string, graphql string, queryVars map[string]interface{}, body []byte, response interface{}) func() error {
+	return func() error {
+		if touches := callOpts(ctx).touches; len(touches) > 0 {
+			defer g.entities.invalidate(touches)
+		}
+
+		var raw json.RawMessage
+		if err := g.execute(ctx, endpoint, body, false, &raw); err != nil {
+			return err
+		}
+
+		if sel, err := entitySelections(graphql, queryVars); err == nil {
+			if err := g.entities.store("", raw, sel); err != nil {
+				return err
+			}
+		}
+
+		return decodeData(raw, response, g.decodeHooksFor(ctx), g.errorBodyLimit)
+	}
+}
+
+// =============================================================================
+
+// entityCache stores the scalar fields of entities by type and id, and the
+// responses of queries as trees whose entity objects are resolved against
+// the stored entities.
+type entityCache struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	entities  map[string]map[string]cachedEntity
+	responses map[string]cachedResponse
+	pruned    time.Time
+}
+
+// cachedEntity represents the scalar fields of an entity, keyed by name and
+// arguments, and when they expire.
+type cachedEntity struct {
+	fields  map[string]interface{}
+	expires time.Time
+}
+
+// cachedResponse represents the normalized response of a query, the types
+// of the entities it includes, and when it expires.
+type cachedResponse struct {
+	tree    interface{}
+	types   map[string]struct{}
+	expires time.Time
+}
+
+// entityNode represents an entity object in a normalized response. Leaves
+// maps the response keys of the scalar fields to the keys the entity
+// stores them under, and fields holds the rest of the object, like nested
+// objects and entities, as selected by the query.
+type entityNode struct {
+	typename string
+	id       string
+	leaves   map[string]string
+	fields   map[string]interface{}
+}
+
+// newEntityCache constructs an empty entity cache.
+func newEntityCache(ttl time.Duration) *entityCache {
+	return &entityCache{
+		ttl:       ttl,
+		entities:  make(map[string]map[string]cachedEntity),
+		responses: make(map[string]cachedResponse),
+	}
+}
+
+// store normalizes the data of a response using the selections of its
+// query and merges its entities into the cache. The response itself is
+// stored under the key unless it's empty.
+func (c *entityCache) store(key string, data []byte, sel *entitySelection) error {
+	value, err := jsonTree(json.RawMessage(data))
+	if err != nil {
+		return fmt.Errorf("graphql entity cache error: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.prune(now)
+
+	expires := now.Add(c.ttl)
+	types := make(map[string]struct{})
+	tree := c.normalize(value, sel, types, now, expires)
+
+	if key != "" {
+		c.responses[key] = cachedResponse{
+			tree:    tree,
+			types:   types,
+			expires: expires,
+		}
+	}
+
+	return nil
+}
+
+// normalize replaces the entity objects in the value with entity nodes and
+// merges their scalar fields into the cache. The selection describes the
+// value and may be nil when it's unknown. The types of the entities are
+// added to the set.
+func (c *entityCache) normalize(value interface{}, sel *entitySelection, types map[string]struct{}, now time.Time, expires time.Time) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		typename, id, ok := entityIdentity(v)
+		if !ok {
+			fields := make(map[string]interface{}, len(v))
+			for name, field := range v {
+				fields[name] = c.normalize(field, sel.field(name), types, now, expires)
+			}
+			return fields
+		}
+		types[typename] = struct{}{}
+
+		byID := c.entities[typename]
+		if byID == nil {
+			byID = make(map[string]cachedEntity)
+			c.entities[typename] = byID
+		}
+
+		entity := byID[id]
+		if entity.fields == nil || now.After(entity.expires) {
+			entity.fields = make(map[string]interface{})
+		}
+
+		node := entityNode{
+			typename: typename,
+			id:       id,
+			leaves:   make(map[string]string),
+			fields:   make(map[string]interface{}),
+		}
+		for name, field := range v {
+			child := sel.field(name)
+			if !child.isLeaf(field) {
+				node.fields[name] = c.normalize(field, child, types, now, expires)
+				continue
+			}
+
+			storeKey := name
+			if child != nil {
+				storeKey = child.storeKey
+			}
+			entity.fields[storeKey] = field
+			node.leaves[name] = storeKey
+		}
+		entity.expires = expires
+		byID[id] = entity
+
+		return node
+
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = c.normalize(item, sel, types, now, expires)
+		}
+		return items
+	}
+
+	return value
+}
+
+// response returns the data of the cached response for the key with its
+// entities resolved. It reports false if the response or any of its
+// entities is no longer cached.
+func (c *entityCache) response(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.prune(now)
+
+	cached, ok := c.responses[key]
+	if !ok {
+		return nil, false
+	}
+
+	if now.After(cached.expires) {
+		delete(c.responses, key)
+		return nil, false
+	}
+
+	value, ok := c.resolve(cached.tree, now)
+	if !ok {
+		delete(c.responses, key)
+		return nil, false
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+
+	return data, true
+}
+
+// resolve replaces the entity nodes in the tree with the fields the query
+// selected, taking the scalar values from the cached entities.
+func (c *entityCache) resolve(tree interface{}, now time.Time) (interface{}, bool) {
+	switch v := tree.(type) {
+	case entityNode:
+		entity, ok := c.entities[v.typename][v.id]
+		if !ok || now.After(entity.expires) {
+			return nil, false
+		}
+
+		fields := make(map[string]interface{}, len(v.leaves)+len(v.fields))
+		for name, storeKey := range v.leaves {
+			value, ok := entity.fields[storeKey]
+			if !ok {
+				return nil, false
+			}
+			fields[name] = value
+		}
+		for name, field := range v.fields {
+			value, ok := c.resolve(field, now)
+			if !ok {
+				return nil, false
+			}
+			fields[name] = value
+		}
+		return fields, true
+
+	case map[string]interface{}:
+		fields := make(map[string]interface{}, len(v))
+		for name, field := range v {
+			value, ok := c.resolve(field, now)
+			if !ok {
+				return nil, false
+			}
+			fields[name] = value
+		}
+		return fields, true
+
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			value, ok := c.resolve(item, now)
+			if !ok {
+				return nil, false
+			}
+			items[i] = value
+		}
+		return items, true
+	}
+
+	return tree, true
+}
+
+// entity returns the cached fields of the entity.
+func (c *entityCache) entity(typename string, id string) (map[string]interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entity, ok := c.entities[typename][id]
+	if !ok || time.Now().After(entity.expires) {
+		return nil, false
+	}
+
+	fields := make(map[string]interface{}, len(entity.fields))
+	for name, value := range entity.fields {
+		fields[name] = value
+	}
+	return fields, true
+}
+
+// invalidate removes the entities of the types and the responses that
+// include them.
+func (c *entityCache) invalidate(typenames []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, typename := range typenames {
+		delete(c.entities, typename)
+	}
+
+	for key, cached := range c.responses {
+		for _, typename := range typenames {
+			if _, ok := cached.types[typename]; ok {
+				delete(c.responses, key)
+				break
+			}
+		}
+	}
+}
+
+// prune removes the expired entities and responses. It runs at most once
+// per ttl, so the cost of walking the cache is spread over many requests.
+// The lock must be held.
+func (c *entityCache) prune(now time.Time) {
+	if now.Sub(c.pruned) < c.ttl {
+		return
+	}
+	c.pruned = now
+
+	for typename, byID := range c.entities {
+		for id, entity := range byID {
+			if now.After(entity.expires) {
+				delete(byID, id)
+			}
+		}
+		if len(byID) == 0 {
+			delete(c.entities, typename)
+		}
+	}
+
+	for key, cached := range c.responses {
+		if now.After(cached.expires) {
+			delete(c.responses, key)
+		}
+	}
+}
+
+// entityIdentity returns the type and id of an object that selects both.
+func entityIdentity(object map[string]interface{}) (string, string, bool) {
+	typename, ok := object["__typename"].(string)
+	if !ok || typename == "" {
+		return "", "", false
+	}
+
+	switch id := object["id"].(type) {
+	case string:
+		return typename, id, true
+	case json.Number:
+		return typename, id.String(), true
+	}
+
+	return "", "", false
+}
+
+// =============================================================================
+
+// entitySelection represents a field selected by a query as seen by the
+// entity cache. StoreKey is the name of the field followed by its arguments
+// with the variables replaced by their values, which identifies the value
+// of the field on an entity regardless of its alias. Fields holds the
+// selection set of the field by response key, and leaf is set for fields
+// without a selection set.
+type entitySelection struct {
+	storeKey string
+	leaf     bool
+	fields   map[string]*entitySelection
+}
+
+// entitySelections returns the selections of the operations in the
+// document, with fragment spreads merged into the selection sets that
+// spread them.
+func entitySelections(document string, queryVars map[string]interface{}) (*entitySelection, error) {
+	doc, err := parseSelections(document)
+	if err != nil {
+		return nil, err
+	}
+
+	root := entitySelection{fields: make(map[string]*entitySelection)}
+	merged := make(map[fragmentTarget]bool)
+	for _, op := range doc.operations {
+		if err := doc.mergeEntitySelections(&root, op, queryVars, merged, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return &root, nil
+}
+
+// fragmentTarget identifies a fragment merged into a selection set. A
+// fragment only needs to be merged into the same selection set once, which
+// keeps documents that spread fragments many times from taking exponential
+// time.
+type fragmentTarget struct {
+	sel      *entitySelection
+	fragment string
+}
+
+// mergeEntitySelections adds the nodes to the selection set of the
+// selection. The visiting list detects fragment cycles.
+func (doc *selectionDoc) mergeEntitySelections(sel *entitySelection, nodes []fieldNode, queryVars map[string]interface{}, merged map[fragmentTarget]bool, visiting []string) error {
+	for _, n := range nodes {
+		if n.spread != "" {
+			for _, name := range visiting {
+				if name == n.spread {
+					return fmt.Errorf("graphql syntax error: fragment %q spreads itself", n.spread)
+				}
+			}
+			target := fragmentTarget{sel: sel, fragment: n.spread}
+			if merged[target] {
+				continue
+			}
+			merged[target] = true
+
+			frag, ok := doc.fragments[n.spread]
+			if !ok {
+				return fmt.Errorf("graphql syntax error: unknown fragment %q", n.spread)
+			}
+			if err := doc.mergeEntitySelections(sel, frag, queryVars, merged, append(visiting, n.spread)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		child, ok := sel.fields[n.key]
+		if !ok {
+			child = &entitySelection{
+				storeKey: n.name + argumentsKey(n.args, queryVars),
+				leaf:     len(n.children) == 0,
+				fields:   make(map[string]*entitySelection),
+			}
+			sel.fields[n.key] = child
+		}
+		if err := doc.mergeEntitySelections(child, n.children, queryVars, merged, visiting); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// field returns the selection of the field with the response key, or nil
+// if it's unknown.
+func (sel *entitySelection) field(key string) *entitySelection {
+	if sel == nil {
+		return nil
+	}
+	return sel.fields[key]
+}
+
+// isLeaf reports whether the value of the selected field is a scalar that
+// can be stored on an entity. Without a selection only values that hold no
+// objects are.
+func (sel *entitySelection) isLeaf(value interface{}) bool {
+	if sel != nil {
+		return sel.leaf
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return false
+	case []interface{}:
+		for _, item := range v {
+			if !sel.isLeaf(item) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// argumentsKey writes the argument tokens in a normalized form, with the
+// variables replaced by the JSON encoding of their values.
+func argumentsKey(args []token, queryVars map[string]interface{}) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	var prev tokenKind
+	for i := 0; i < len(args); i++ {
+		kind, value := args[i].kind, args[i].value
+		if kind == tokPunct && value == "$" && i+1 < len(args) {
+			data, err := json.Marshal(queryVars[args[i+1].value])
+			if err != nil {
+				data = []byte("null")
+			}
+			kind, value = tokString, string(data)
+			i++
+		}
+
+		if isWord(prev) && isWord(kind) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(value)
+		prev = kind
+	}
+
+	return b.String()
+}
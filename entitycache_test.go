@@ -0,0 +1,191 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestEntityCache(t *testing.T) {
+	type person struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	type city struct {
+		GetCity struct {
+			Name  string `json:"name"`
+			Mayor person `json:"mayor"`
+		} `json:"getCity"`
+	}
+
+	t.Log("Given the need to cache the entities of query responses.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen queries and mutations return the same entities.", testID)
+		{
+			var calls int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+
+				b, _ := io.ReadAll(r.Body)
+				switch {
+				case strings.Contains(string(b), "getCity"):
+					io.WriteString(w, `{"data": {"getCity": {"__typename": "City", "id": 1, "name": "Miami", "mayor": {"__typename": "Person", "id": "2", "name": "ed"}}}}`)
+				case strings.Contains(string(b), "updatePerson"):
+					io.WriteString(w, `{"data": {"updatePerson": {"__typename": "Person", "id": "2", "name": "eddie"}}}`)
+				default:
+					io.WriteString(w, `{"data": {"addCity": {"id": 3}}}`)
+				}
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithEntityCache(time.Minute))
+			ctx := context.Background()
+			query := `query { getCity(id: 1) { __typename id name mayor { __typename id name } } }`
+
+			for i := 0; i < 2; i++ {
+				var got city
+				if err := gql.Execute(ctx, query, &got); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+				if got.GetCity.Name != "Miami" || got.GetCity.Mayor.Name != "ed" {
+					t.Fatalf("\t%s\tTest %d:\tShould get the expected result: %+v", failed, testID, got)
+				}
+			}
+			if n := atomic.LoadInt32(&calls); n != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould only call the host once: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould only call the host once.", success, testID)
+
+			if err := gql.Execute(ctx, `mutation { updatePerson(id: "2") { __typename id name } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the mutation: %v", failed, testID, err)
+			}
+
+			var got city
+			if err := gql.Execute(ctx, query, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if got.GetCity.Mayor.Name != "eddie" {
+				t.Fatalf("\t%s\tTest %d:\tShould see the updated entity: %+v", failed, testID, got)
+			}
+			if n := atomic.LoadInt32(&calls); n != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould answer the query from the cache: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould see the updated entity.", success, testID)
+
+			var p person
+			ok, err := gql.CachedEntity("Person", "2", &p)
+			if err != nil || !ok || p.Name != "eddie" {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to read the cached entity: %v %v %+v", failed, testID, ok, err, p)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to read the cached entity.", success, testID)
+
+			if err := gql.Execute(graphql.Touches(ctx, "City"), `mutation { addCity(name: "Tampa") { id } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the mutation: %v", failed, testID, err)
+			}
+			if err := gql.Execute(ctx, query, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if n := atomic.LoadInt32(&calls); n != 4 {
+				t.Fatalf("\t%s\tTest %d:\tShould call the host after the mutation touched the type: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould call the host after the mutation touched the type.", success, testID)
+
+			gql.InvalidateEntities("Person")
+			if ok, _ := gql.CachedEntity("Person", "2", &p); ok {
+				t.Fatalf("\t%s\tTest %d:\tShould not find an invalidated entity.", failed, testID)
+			}
+			if err := gql.Execute(ctx, query, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if n := atomic.LoadInt32(&calls); n != 5 {
+				t.Fatalf("\t%s\tTest %d:\tShould call the host after invalidation: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould call the host after invalidation.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen queries select different fields of the same entity.", testID)
+		{
+			var calls int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+
+				b, _ := io.ReadAll(r.Body)
+				switch {
+				case strings.Contains(string(b), "city"):
+					io.WriteString(w, `{"data": {"user": {"__typename": "User", "id": "1", "address": {"city": "Miami"}}}}`)
+				case strings.Contains(string(b), "zip"):
+					io.WriteString(w, `{"data": {"user": {"__typename": "User", "id": "1", "address": {"zip": "33101"}}}}`)
+				case strings.Contains(string(b), `"days":1`):
+					io.WriteString(w, `{"data": {"user": {"__typename": "User", "id": "1", "score": 10}}}`)
+				default:
+					io.WriteString(w, `{"data": {"user": {"__typename": "User", "id": "1", "score": 20}}}`)
+				}
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithEntityCache(time.Minute))
+			ctx := context.Background()
+
+			type address struct {
+				User struct {
+					Address struct {
+						City string `json:"city"`
+						Zip  string `json:"zip"`
+					} `json:"address"`
+				} `json:"user"`
+			}
+
+			queries := []string{
+				`query { user { __typename id address { city } } }`,
+				`query { user { __typename id address { zip } } }`,
+				`query { user { __typename id address { city } } }`,
+			}
+			var got address
+			for _, query := range queries {
+				got = address{}
+				if err := gql.Execute(ctx, query, &got); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+			}
+			if got.User.Address.City != "Miami" || got.User.Address.Zip != "" || atomic.LoadInt32(&calls) != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould keep the nested objects of each query: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep the nested objects of each query.", success, testID)
+
+			type score struct {
+				User struct {
+					Score int `json:"score"`
+				} `json:"user"`
+			}
+
+			query := `query($days: Int!) { user { __typename id score(days: $days) } }`
+			for _, days := range []int{1, 2, 1} {
+				var got score
+				if err := gql.Execute(ctx, query, &got, graphql.WithVariable("days", days)); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+				if exp := days * 10; got.User.Score != exp {
+					t.Fatalf("\t%s\tTest %d:\tShould keep the fields with different arguments apart: got %d, exp %d", failed, testID, got.User.Score, exp)
+				}
+			}
+			if n := atomic.LoadInt32(&calls); n != 4 {
+				t.Fatalf("\t%s\tTest %d:\tShould answer the repeated query from the cache: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep the fields with different arguments apart.", success, testID)
+		}
+	}
+}
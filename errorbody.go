@@ -0,0 +1,29 @@
+package graphql
+
+import "fmt"
+
+// defaultErrorBodyLimit is the number of bytes of a request or response
+// body embedded in errors and log messages by default.
+const defaultErrorBodyLimit = 4096
+
+// WithErrorBodyLimit sets the number of bytes of a request or response body
+// embedded in errors and log messages. Longer bodies are truncated and the
+// number of bytes left out is noted. The default is 4KB and a limit of 0
+// embeds the bodies in full.
+func WithErrorBodyLimit(limit int) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		if limit < 0 {
+			limit = 0
+		}
+		gql.errorBodyLimit = limit
+	}
+}
+
+// excerpt returns the body truncated to the limit for an error or log
+// message.
+func excerpt(body []byte, limit int) string {
+	if limit <= 0 || len(body) <= limit {
+		return string(body)
+	}
+	return fmt.Sprintf("%s...(%d more bytes)", body[:limit], len(body)-limit)
+}
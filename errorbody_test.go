@@ -0,0 +1,116 @@
+package graphql_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/gorilla/websocket"
+)
+
+func TestErrorBodyLimit(t *testing.T) {
+	f := func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(b), "invalid") {
+			io.WriteString(w, `{"data": `+strings.Repeat(" ", 10*1024)+`"invalid"}`)
+			return
+		}
+		io.WriteString(w, `{"errors": [{"message": "failed"}]}`)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(f))
+	defer server.Close()
+
+	query := fmt.Sprintf(`query { name(filter: "%s") }`, strings.Repeat("a", 10*1024))
+
+	tt := []struct {
+		name    string
+		options []func(gql *graphql.GraphQL)
+		max     int
+	}{
+		{name: "default", max: 5 * 1024},
+		{name: "custom", options: []func(gql *graphql.GraphQL){graphql.WithErrorBodyLimit(100)}, max: 400},
+		{name: "disabled", options: []func(gql *graphql.GraphQL){graphql.WithErrorBodyLimit(0)}, max: 20 * 1024},
+	}
+
+	t.Log("Given the need to keep large bodies out of errors and logs.")
+	{
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen using the %s limit.", testID, test.name)
+			{
+				var logged string
+				options := append(test.options, graphql.WithLogging(func(s string) { logged = s }))
+				gql := graphql.New(server.URL, options...)
+
+				err := gql.Execute(context.Background(), query, nil)
+				if err == nil {
+					t.Fatalf("\t%s\tTest %d:\tShould get the error.", failed, testID)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the error.", success, testID)
+
+				truncated := test.name != "disabled"
+				if len(err.Error()) > test.max || strings.Contains(err.Error(), "more bytes)") != truncated {
+					t.Fatalf("\t%s\tTest %d:\tShould limit the request in the error: %d bytes", failed, testID, len(err.Error()))
+				}
+				if len(logged) > 2*test.max || strings.Contains(logged, "more bytes)") != truncated {
+					t.Fatalf("\t%s\tTest %d:\tShould limit the bodies in the log message: %d bytes", failed, testID, len(logged))
+				}
+				t.Logf("\t%s\tTest %d:\tShould limit the bodies in the error and log message.", success, testID)
+
+				var got struct{ Name string }
+				err = gql.Execute(context.Background(), `query { invalid }`, &got)
+				if err == nil || len(err.Error()) > test.max {
+					t.Fatalf("\t%s\tTest %d:\tShould limit the response in a decoding error: %v", failed, testID, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould limit the response in a decoding error.", success, testID)
+			}
+		}
+	}
+}
+
+func TestErrorBodyLimitSubscriptions(t *testing.T) {
+	f := func(w http.ResponseWriter, r *http.Request) {
+		upgrader := websocket.Upgrader{Subprotocols: []string{"graphql-transport-ws"}}
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		var msg map[string]interface{}
+		ws.ReadJSON(&msg)
+		ws.WriteJSON(map[string]interface{}{
+			"type":    "connection_error",
+			"payload": map[string]string{"message": strings.Repeat("a", 10*1024)},
+		})
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(f))
+	defer server.Close()
+
+	t.Log("Given the need to keep large subscription payloads out of errors.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the connection is rejected with a large payload.", testID)
+		{
+			gql := graphql.New(server.URL, graphql.WithErrorBodyLimit(100))
+			defer gql.Close()
+
+			_, err := gql.Subscribe(context.Background(), `subscription { tick }`)
+			if err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get the error.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the error.", success, testID)
+
+			if len(err.Error()) > 400 || !strings.Contains(err.Error(), "more bytes)") {
+				t.Fatalf("\t%s\tTest %d:\tShould limit the payload in the error: %d bytes", failed, testID, len(err.Error()))
+			}
+			t.Logf("\t%s\tTest %d:\tShould limit the payload in the error.", success, testID)
+		}
+	}
+}
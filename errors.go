@@ -0,0 +1,340 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HTTPError is returned when the host responds with a status code other
+// than 200. RetryAfter is set when the host provided a Retry-After header,
+// RequestID is set when request ids are enabled, and Operation is set for
+// graphql documents. Errors with a well-known status code match one of the
+// sentinel errors, like ErrUnauthorized, with errors.Is.
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration
+	RequestID  string
+	Operation  string
+
+	class error
+}
+
+// newHTTPError constructs an HTTPError from the specified response.
+func newHTTPError(resp *Response) *HTTPError {
+	retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return &HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RetryAfter: retryAfter,
+	}
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("graphql op error: %sstatus code: %s", labels(e.RequestID, e.Operation), e.Status)
+}
+
+// Is reports whether the target matches the class of the error, like
+// ErrUnauthorized for a 401 status code.
+func (e *HTTPError) Is(target error) bool {
+	return e.class != nil && errors.Is(e.class, target)
+}
+
+// labels produces the prefix that identifies the request in log messages
+// and errors.
+func labels(requestID string, operation string) string {
+	var prefix string
+	if requestID != "" {
+		prefix += "request_id:[" + requestID + "] "
+	}
+	if operation != "" {
+		prefix += "operation:[" + operation + "] "
+	}
+	return prefix
+}
+
+// =============================================================================
+
+// Error codes reported by hosts in the extensions of a graphql error.
+const (
+	CodeAborted                    = "Aborted"
+	CodePersistedQueryNotFound     = "PERSISTED_QUERY_NOT_FOUND"
+	CodePersistedQueryNotSupported = "PERSISTED_QUERY_NOT_SUPPORTED"
+)
+
+// abortedMessage is the message Dgraph reports for a transaction that was
+// aborted because of a conflict with a concurrent transaction.
+const abortedMessage = "Transaction has been aborted"
+
+// ResponseError represents a single error in the errors list of a graphql
+// response.
+type ResponseError struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Code returns the code provided in the extensions of the error. Dgraph
+// doesn't provide a code for aborted transactions so CodeAborted is
+// returned for those.
+func (e ResponseError) Code() string {
+	if code, ok := e.Extensions["code"].(string); ok && code != "" {
+		return code
+	}
+	if strings.Contains(e.Message, abortedMessage) {
+		return CodeAborted
+	}
+	return ""
+}
+
+// GraphQLError is returned when the host responds with errors in the
+// graphql response. Request is the query that was executed, RequestID is
+// set when request ids are enabled, and Operation is set for graphql
+// documents. Errors of a well-known class match one of the sentinel errors,
+// like ErrNotFound, with errors.Is.
+type GraphQLError struct {
+	Errors    []ResponseError
+	Request   string
+	RequestID string
+	Operation string
+
+	class error
+}
+
+// Error implements the error interface.
+func (e *GraphQLError) Error() string {
+	var msg string
+	if len(e.Errors) > 0 {
+		msg = e.Errors[0].Message
+	}
+	return fmt.Sprintf("graphql op error: %srequest:[%s] error:[%s]", labels(e.RequestID, e.Operation), e.Request, msg)
+}
+
+// Code returns the first error code reported in the response.
+func (e *GraphQLError) Code() string {
+	for _, re := range e.Errors {
+		if code := re.Code(); code != "" {
+			return code
+		}
+	}
+	return ""
+}
+
+// Is reports whether the target matches the class of the error, like
+// ErrValidation for a document the host couldn't validate.
+func (e *GraphQLError) Is(target error) bool {
+	return e.class != nil && errors.Is(e.class, target)
+}
+
+// transportError marks a failure to deliver the request or receive the
+// response from the host.
+type transportError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (e *transportError) Error() string {
+	return fmt.Sprintf("graphql request error: %v", e.err)
+}
+
+// Unwrap returns the underlying error.
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// ErrorCode returns the code of the graphql error in the chain, or an empty
+// string if there isn't one.
+func ErrorCode(err error) string {
+	var gqlErr *GraphQLError
+	if errors.As(err, &gqlErr) {
+		return gqlErr.Code()
+	}
+	return ""
+}
+
+// IsGraphQLError reports whether the error was returned by the host in the
+// errors of a graphql response.
+func IsGraphQLError(err error) bool {
+	var gqlErr *GraphQLError
+	return errors.As(err, &gqlErr)
+}
+
+// IsTransportError reports whether the request failed because the host
+// couldn't be reached or the connection failed, including timeouts.
+func IsTransportError(err error) bool {
+	var tErr *transportError
+	return errors.As(err, &tErr) || errors.Is(err, ErrTimeout)
+}
+
+// IsRetryable reports whether sending the same request again may succeed.
+// This is the case for transport failures other than cancellation, for
+// 429, 502, 503, and 504 status codes, and for aborted transactions.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, ErrClosed) {
+		return false
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	if ErrorCode(err) == CodeAborted {
+		return true
+	}
+
+	return IsTransportError(err)
+}
+
+// =============================================================================
+
+// ErrTimeout is matched by the errors of requests that didn't complete
+// before their deadline, whether it was set by the caller or by
+// WithTimeout. These errors also match context.DeadlineExceeded.
+var ErrTimeout = errors.New("graphql timeout error")
+
+// timeoutError marks a request error caused by an exceeded deadline.
+type timeoutError struct {
+	err error
+}
+
+// Error implements the error interface.
+func (e *timeoutError) Error() string {
+	return fmt.Sprintf("graphql timeout error: %v", e.err)
+}
+
+// Is reports whether the target is ErrTimeout.
+func (e *timeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// Unwrap returns the underlying request error.
+func (e *timeoutError) Unwrap() error {
+	return e.err
+}
+
+// =============================================================================
+
+// OpError represents the failure of a single operation executed as part
+// of a batch. Index is the position of the operation or batch chunk in the
+// original input.
+type OpError struct {
+	Index int
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *OpError) Error() string {
+	return fmt.Sprintf("[%d] %v", e.Index, e.Err)
+}
+
+// Unwrap returns the underlying error for the operation.
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// =============================================================================
+
+// MultiError aggregates the failures of a batch of operations. Each failure
+// is indexed by the position of the operation in the batch. The errors.Is
+// and errors.As functions will inspect every individual failure.
+type MultiError struct {
+	Total  int
+	Errors []*OpError
+}
+
+// newMultiError constructs a MultiError for a batch of the specified size.
+func newMultiError(total int) *MultiError {
+	return &MultiError{
+		Total: total,
+	}
+}
+
+// add records the failure of the operation at the specified index.
+func (m *MultiError) add(index int, err error) {
+	m.Errors = append(m.Errors, &OpError{Index: index, Err: err})
+}
+
+// errOrNil returns the MultiError with the failures sorted by index if any
+// failures were recorded, else it returns nil.
+func (m *MultiError) errOrNil() error {
+	if len(m.Errors) == 0 {
+		return nil
+	}
+
+	sort.Slice(m.Errors, func(i, j int) bool {
+		return m.Errors[i].Index < m.Errors[j].Index
+	})
+
+	return m
+}
+
+// Failed returns the number of operations that failed.
+func (m *MultiError) Failed() int {
+	return len(m.Errors)
+}
+
+// Succeeded returns the number of operations that succeeded.
+func (m *MultiError) Succeeded() int {
+	return m.Total - len(m.Errors)
+}
+
+// ErrorAt returns the error for the operation at the specified index, or
+// nil if that operation succeeded.
+func (m *MultiError) ErrorAt(index int) error {
+	for _, oe := range m.Errors {
+		if oe.Index == index {
+			return oe.Err
+		}
+	}
+	return nil
+}
+
+// Error implements the error interface.
+func (m *MultiError) Error() string {
+	msgs := make([]string, len(m.Errors))
+	for i, oe := range m.Errors {
+		msgs[i] = oe.Error()
+	}
+	return fmt.Sprintf("graphql batch error: %d of %d operations failed: %s", len(m.Errors), m.Total, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual failures.
+func (m *MultiError) Unwrap() []error {
+	errs := make([]error, len(m.Errors))
+	for i, oe := range m.Errors {
+		errs[i] = oe
+	}
+	return errs
+}
+
+// Is reports whether any of the individual failures matches the target.
+func (m *MultiError) Is(target error) bool {
+	for _, oe := range m.Errors {
+		if errors.Is(oe, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first individual failure that matches the target, and if
+// one is found, sets the target to that error value.
+func (m *MultiError) As(target interface{}) bool {
+	for _, oe := range m.Errors {
+		if errors.As(oe, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestMultiError(t *testing.T) {
+	errFirst := errors.New("first failure")
+	errSecond := errors.New("second failure")
+
+	t.Log("Given the need to inspect the failures of a batch.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen two of four operations fail.", testID)
+		{
+			var err error = &graphql.MultiError{
+				Total: 4,
+				Errors: []*graphql.OpError{
+					{Index: 1, Err: errFirst},
+					{Index: 3, Err: errSecond},
+				},
+			}
+
+			if !errors.Is(err, errSecond) {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to match an individual failure.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to match an individual failure.", success, testID)
+
+			var oe *graphql.OpError
+			if !errors.As(err, &oe) || oe.Index != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to extract the first operation error.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to extract the first operation error.", success, testID)
+
+			var me *graphql.MultiError
+			if !errors.As(err, &me) {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to extract the multi error.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to extract the multi error.", success, testID)
+
+			if me.Failed() != 2 || me.Succeeded() != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould get the failure counts: %d/%d", failed, testID, me.Failed(), me.Succeeded())
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the failure counts.", success, testID)
+
+			if me.ErrorAt(3) != errSecond || me.ErrorAt(2) != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to look up failures by index.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to look up failures by index.", success, testID)
+
+			exp := "graphql batch error: 2 of 4 operations failed: [1] first failure; [3] second failure"
+			if err.Error() != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould get the summary message: %s", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the summary message.", success, testID)
+		}
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	t.Log("Given the need to classify the errors of a request.")
+	{
+		tt := []struct {
+			name      string
+			status    int
+			body      string
+			code      string
+			graphql   bool
+			transport bool
+			retryable bool
+		}{
+			{"aborted", http.StatusOK, `{"errors": [{"message": "Transaction has been aborted. Please retry"}]}`, graphql.CodeAborted, true, false, true},
+			{"extensions", http.StatusOK, `{"errors": [{"message": "not found", "extensions": {"code": "PERSISTED_QUERY_NOT_FOUND"}}]}`, graphql.CodePersistedQueryNotFound, true, false, false},
+			{"unavailable", http.StatusServiceUnavailable, ``, "", false, false, true},
+			{"badrequest", http.StatusBadRequest, ``, "", false, false, false},
+			{"transport", 0, ``, "", false, true, true},
+		}
+
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen the request fails with %s.", testID, test.name)
+				{
+					f := func(w http.ResponseWriter, r *http.Request) {
+						if test.status == 0 {
+							hj, _ := w.(http.Hijacker)
+							conn, _, _ := hj.Hijack()
+							conn.Close()
+							return
+						}
+						w.WriteHeader(test.status)
+						io.WriteString(w, test.body)
+					}
+
+					server := httptest.NewServer(http.HandlerFunc(f))
+					defer server.Close()
+
+					gql := graphql.New(server.URL)
+					err := gql.Execute(context.Background(), `query { __typename }`, nil)
+					if err == nil {
+						t.Fatalf("\t%s\tTest %d:\tShould get an error.", failed, testID)
+					}
+
+					if code := graphql.ErrorCode(err); code != test.code {
+						t.Fatalf("\t%s\tTest %d:\tShould get the error code %q: %q", failed, testID, test.code, code)
+					}
+					t.Logf("\t%s\tTest %d:\tShould get the error code.", success, testID)
+
+					if graphql.IsGraphQLError(err) != test.graphql || graphql.IsTransportError(err) != test.transport {
+						t.Fatalf("\t%s\tTest %d:\tShould classify the error: %v", failed, testID, err)
+					}
+					t.Logf("\t%s\tTest %d:\tShould classify the error.", success, testID)
+
+					if graphql.IsRetryable(err) != test.retryable {
+						t.Fatalf("\t%s\tTest %d:\tShould report retryable as %t: %v", failed, testID, test.retryable, err)
+					}
+					t.Logf("\t%s\tTest %d:\tShould report retryable as %t.", success, testID, test.retryable)
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// WithETags enables conditional requests for queries against hosts that
+// return an ETag header. The ETag and data of a response are stored in the
+// specified cache for the specified ttl, keyed by the endpoint, query, and
+// variables, and the next time the query is executed the ETag is sent in
+// the If-None-Match header. When the host responds with 304 Not Modified
+// the stored data is used. Hits and misses are reported by Stats. Queries
+// answered by WithCache or WithEntityCache don't reach the host and aren't
+// conditional.
+func WithETags(cache Cache, ttl time.Duration) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.etags = cache
+		gql.etagTTL = ttl
+	}
+}
+
+// etagRequest executes the request with the stored ETag of the query, if
+// there is one, and uses the stored data when the host reports the response
+// hasn't changed.
+func (g *GraphQL) etagRequest(ctx context.Context, key string, endpoint string, body []byte, response interface{}) error {
+	var state etagState
+	var stored []byte
+	if entry, ok := g.etags.Get(key); ok {
+		if i := bytes.IndexByte(entry, 0); i > 0 {
+			state.ifNoneMatch = string(entry[:i])
+			stored = entry[i+1:]
+		}
+	}
+
+	ctx = withCallOption(ctx, func(opts *callOptions) {
+		opts.etag = &state
+	})
+
+	var raw json.RawMessage
+	if err := g.execute(ctx, endpoint, body, true, &raw); err != nil {
+		return err
+	}
+
+	data := []byte(raw)
+	etag, notModified := state.result()
+
+	switch {
+	case notModified:
+		data = stored
+		if g.stats != nil {
+			g.stats.etagHits.Add(1)
+		}
+
+	default:
+		if etag != "" {
+			entry := make([]byte, 0, len(etag)+1+len(raw))
+			entry = append(entry, etag...)
+			entry = append(entry, 0)
+			entry = append(entry, raw...)
+			g.etags.Set(key, entry, g.etagTTL)
+		}
+		if g.stats != nil {
+			g.stats.etagMisses.Add(1)
+		}
+	}
+
+	return decodeData(data, response, g.decodeHooksFor(ctx), g.errorBodyLimit)
+}
+
+// =============================================================================
+
+// etagState carries the ETag sent with a conditional request and what the
+// host answered. Hedged attempts share it, so it's guarded.
+type etagState struct {
+	ifNoneMatch string
+
+	mu          sync.Mutex
+	etag        string
+	notModified bool
+}
+
+// setHeader adds the If-None-Match header when an ETag is stored.
+func (s *etagState) setHeader(header http.Header) {
+	if s.ifNoneMatch != "" {
+		header.Set("If-None-Match", s.ifNoneMatch)
+	}
+}
+
+// response records the answer of the host and reports whether the response
+// is a 304 for the ETag that was sent.
+func (s *etagState) response(resp *Response) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if resp.StatusCode == http.StatusNotModified && s.ifNoneMatch != "" {
+		s.notModified = true
+		return true
+	}
+
+	s.etag = resp.Header.Get("ETag")
+	return false
+}
+
+// result returns the ETag of the response and whether it wasn't modified.
+func (s *etagState) result() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.etag, s.notModified
+}
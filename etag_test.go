@@ -0,0 +1,83 @@
+package graphql_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestETags(t *testing.T) {
+	type response struct {
+		Name string `json:"name"`
+	}
+
+	t.Log("Given the need to make conditional requests for queries.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the host returns an ETag.", testID)
+		{
+			var mu sync.Mutex
+			version := 1
+			var notModified int
+
+			f := func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				etag := fmt.Sprintf(`"v%d"`, version)
+				if r.Header.Get("If-None-Match") == etag {
+					notModified++
+					w.WriteHeader(http.StatusNotModified)
+					return
+				}
+				w.Header().Set("ETag", etag)
+				fmt.Fprintf(w, `{"data": {"name": "version %d"}}`, version)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithETags(graphql.NewMemoryCache(), time.Minute))
+			ctx := context.Background()
+			query := `query { name }`
+
+			for i := 0; i < 3; i++ {
+				var got response
+				if err := gql.Execute(ctx, query, &got); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+				if got.Name != "version 1" {
+					t.Fatalf("\t%s\tTest %d:\tShould get the stored data: %q", failed, testID, got.Name)
+				}
+			}
+			if notModified != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould get a 304 for the repeated queries: %d", failed, testID, notModified)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get a 304 for the repeated queries.", success, testID)
+
+			mu.Lock()
+			version = 2
+			mu.Unlock()
+
+			var got response
+			if err := gql.Execute(ctx, query, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if got.Name != "version 2" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the modified data: %q", failed, testID, got.Name)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the modified data.", success, testID)
+
+			if stats := gql.Stats(); stats.ETagHits != 2 || stats.ETagMisses != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould count the hits and misses: %d %d", failed, testID, stats.ETagHits, stats.ETagMisses)
+			}
+			t.Logf("\t%s\tTest %d:\tShould count the hits and misses.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+)
+
+// Call represents a graphql operation executed with ExecuteAll. The
+// response is decoded into Response like it is for Execute.
+type Call struct {
+	Query     string
+	Response  interface{}
+	Variables []func(m map[string]interface{})
+}
+
+// ExecuteAll executes independent operations against the url/graphql
+// endpoint, running up to concurrency of them at the same time. This suits
+// dashboards that fan out a number of reads. Each response is decoded into
+// the Response of its call, so the results keep the order of the calls.
+// If any calls fail, a *MultiError is returned where each failure is
+// indexed by the position of the call. Calls that haven't started when the
+// context is canceled are reported with the context error.
+func (g *GraphQL) ExecuteAll(ctx context.Context, calls []Call, concurrency int) error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(calls) {
+		concurrency = len(calls)
+	}
+
+	merr := newMultiError(len(calls))
+
+	var mu sync.Mutex
+	fail := func(index int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		merr.add(index, err)
+	}
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+			for index := range work {
+				c := calls[index]
+				if err := g.Execute(ctx, c.Query, c.Response, c.Variables...); err != nil {
+					fail(index, err)
+				}
+			}
+		}()
+	}
+
+	for index := range calls {
+		select {
+		case work <- index:
+		case <-ctx.Done():
+			fail(index, ctx.Err())
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return merr.errOrNil()
+}
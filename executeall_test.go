@@ -0,0 +1,100 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestExecuteAll(t *testing.T) {
+	t.Log("Given the need to execute independent operations in parallel.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen executing operations with bounded concurrency.", testID)
+		{
+			var mu sync.Mutex
+			var active, peak int
+
+			f := func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				active++
+				if active > peak {
+					peak = active
+				}
+				mu.Unlock()
+
+				time.Sleep(20 * time.Millisecond)
+
+				mu.Lock()
+				active--
+				mu.Unlock()
+
+				var req struct {
+					Variables map[string]interface{} `json:"variables"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+
+				id := req.Variables["id"].(float64)
+				if id == 3 {
+					fmt.Fprint(w, `{"errors": [{"message": "no nodes found"}]}`)
+					return
+				}
+				fmt.Fprintf(w, `{"data": {"city": {"id": %v}}}`, id)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			type city struct {
+				City struct {
+					ID int `json:"id"`
+				} `json:"city"`
+			}
+
+			results := make([]city, 6)
+			calls := make([]graphql.Call, len(results))
+			for i := range calls {
+				calls[i] = graphql.Call{
+					Query:     `query($id: Int!) { city(id: $id) { id } }`,
+					Response:  &results[i],
+					Variables: []func(m map[string]interface{}){graphql.WithVariable("id", i)},
+				}
+			}
+
+			err := gql.ExecuteAll(context.Background(), calls, 2)
+
+			var me *graphql.MultiError
+			if !errors.As(err, &me) || me.Failed() != 1 || me.ErrorAt(3) == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould report the failed operation by index: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the failed operation by index.", success, testID)
+
+			if !errors.Is(err, graphql.ErrNotFound) {
+				t.Fatalf("\t%s\tTest %d:\tShould classify the failure: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould classify the failure.", success, testID)
+
+			for i, r := range results {
+				if i != 3 && r.City.ID != i {
+					t.Fatalf("\t%s\tTest %d:\tShould decode the results in order: %+v", failed, testID, results)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould decode the results in order.", success, testID)
+
+			if peak > 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould run at most 2 operations at a time: %d", failed, testID, peak)
+			}
+			t.Logf("\t%s\tTest %d:\tShould run at most 2 operations at a time.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package graphql
+
+import (
+	"fmt"
+)
+
+// Field represents a field selected by a graphql document. Key is the
+// alias of the field if it has one, else its name, and is the key of the
+// field in the response. Conditional is set for fields that can be missing
+// from the response because they are selected in an inline fragment with
+// a type condition or with the @include or @skip directive.
+type Field struct {
+	Key         string
+	Name        string
+	Conditional bool
+	Fields      []Field
+}
+
+// SelectedFields returns the fields the operations in the document select,
+// which are the fields expected in the data of the response. Fragment
+// spreads are replaced by the fields of the fragment and fields selected
+// more than once are merged.
+func SelectedFields(document string) ([]Field, error) {
+	doc, err := parseSelections(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields []Field
+	for _, op := range doc.operations {
+		expanded, err := doc.expand(op, false, nil)
+		if err != nil {
+			return nil, err
+		}
+		fields = mergeFields(fields, expanded)
+	}
+
+	return fields, nil
+}
+
+// expand converts the nodes to fields, replacing fragment spreads with the
+// fields of the fragment. The visiting list detects fragment cycles. The
+// fields of each fragment are expanded once and shared by every spread of
+// it, so documents that spread fragments many times don't take exponential
+// time. Shared fields are never modified, see mergeFields.
+func (doc *selectionDoc) expand(nodes []fieldNode, conditional bool, visiting []string) ([]Field, error) {
+	var fields []Field
+	for _, n := range nodes {
+		if n.spread != "" {
+			for _, name := range visiting {
+				if name == n.spread {
+					return nil, fmt.Errorf("graphql syntax error: fragment %q spreads itself", n.spread)
+				}
+			}
+
+			key := expandedFragment{name: n.spread, conditional: conditional || n.conditional}
+			spread, ok := doc.expanded[key]
+			if !ok {
+				frag, ok := doc.fragments[n.spread]
+				if !ok {
+					return nil, fmt.Errorf("graphql syntax error: unknown fragment %q", n.spread)
+				}
+
+				var err error
+				if spread, err = doc.expand(frag, key.conditional, append(visiting, n.spread)); err != nil {
+					return nil, err
+				}
+				doc.expanded[key] = spread
+			}
+			fields = mergeFields(fields, spread)
+			continue
+		}
+
+		children, err := doc.expand(n.children, false, visiting)
+		if err != nil {
+			return nil, err
+		}
+
+		f := Field{
+			Key:         n.key,
+			Name:        n.name,
+			Conditional: conditional || n.conditional,
+			Fields:      children,
+		}
+		fields = mergeFields(fields, []Field{f})
+	}
+
+	return fields, nil
+}
+
+// mergeFields adds the fields to the list, merging the selections of
+// fields with the same key. A merged field is only conditional when every
+// selection of it is. The selections of the fields may be shared with the
+// expansion of a fragment, so they are copied before they are merged into.
+func mergeFields(fields []Field, add []Field) []Field {
+next:
+	for _, f := range add {
+		for i := range fields {
+			if fields[i].Key == f.Key {
+				fields[i].Conditional = fields[i].Conditional && f.Conditional
+				if len(f.Fields) > 0 {
+					fields[i].Fields = mergeFields(append([]Field(nil), fields[i].Fields...), f.Fields)
+				}
+				continue next
+			}
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+// =============================================================================
+
+// fieldNode represents a field or fragment spread in a selection set. The
+// fields of inline fragments are included in the selection set of their
+// parent. Args holds the tokens of the arguments of the field, parentheses
+// included. End is the offset just past the arguments and directives of the
+// field.
+type fieldNode struct {
+	key         string
+	name        string
+	spread      string
+	conditional bool
+	args        []token
+	end         int
+	children    []fieldNode
+}
+
+// selectionDoc represents the selection sets of the operations and
+// fragments in a document, and the fragments expanded so far.
+type selectionDoc struct {
+	operations [][]fieldNode
+	fragments  map[string][]fieldNode
+	expanded   map[expandedFragment][]Field
+}
+
+// expandedFragment identifies the expansion of a fragment, which depends on
+// whether it's spread conditionally.
+type expandedFragment struct {
+	name        string
+	conditional bool
+}
+
+// parseSelections parses the selection sets of every definition in the
+// document.
+func parseSelections(document string) (*selectionDoc, error) {
+	tokens, err := lex(document)
+	if err != nil {
+		return nil, err
+	}
+
+	sp := selectionParser{tokens: tokens}
+	doc := selectionDoc{
+		fragments: make(map[string][]fieldNode),
+		expanded:  make(map[expandedFragment][]Field),
+	}
+
+	for sp.pos < len(sp.tokens) {
+		t := sp.tokens[sp.pos]
+
+		switch {
+		case t.kind == tokPunct && t.value == "{":
+			nodes, err := sp.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, nodes)
+
+		case t.kind == tokName && (t.value == "query" || t.value == "mutation" || t.value == "subscription" || t.value == "fragment"):
+			var name string
+			sp.pos++
+			if sp.pos < len(sp.tokens) && sp.tokens[sp.pos].kind == tokName {
+				name = sp.tokens[sp.pos].value
+			}
+
+			// Skip the name, variables, type condition, and directives
+			// that come before the selection set.
+			for sp.pos < len(sp.tokens) && sp.tokens[sp.pos].value != "{" {
+				if sp.tokens[sp.pos].value == "(" {
+					if err := sp.skipParens(); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				sp.pos++
+			}
+			if sp.pos == len(sp.tokens) {
+				return nil, fmt.Errorf("graphql syntax error: definition at offset %d has no selection set", t.start)
+			}
+
+			nodes, err := sp.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			if t.value == "fragment" {
+				doc.fragments[name] = nodes
+				continue
+			}
+			doc.operations = append(doc.operations, nodes)
+
+		default:
+			return nil, fmt.Errorf("graphql syntax error: unexpected %q at offset %d", t.value, t.start)
+		}
+	}
+
+	return &doc, nil
+}
+
+// selectionParser parses selection sets from the tokens of a document.
+type selectionParser struct {
+	tokens []token
+	pos    int
+}
+
+// selectionSet parses the selection set that starts at the current
+// position.
+func (sp *selectionParser) selectionSet() ([]fieldNode, error) {
+	open := sp.tokens[sp.pos]
+	sp.pos++
+
+	var nodes []fieldNode
+	for {
+		if sp.pos >= len(sp.tokens) {
+			return nil, fmt.Errorf("graphql syntax error: unterminated selection set at offset %d", open.start)
+		}
+
+		t := sp.tokens[sp.pos]
+		switch {
+		case t.kind == tokPunct && t.value == "}":
+			sp.pos++
+			return nodes, nil
+
+		case t.kind == tokPunct && t.value == "...":
+			sp.pos++
+
+			var typed bool
+			switch {
+			case sp.peek("on"):
+				typed = true
+				sp.pos += 2
+
+			case sp.pos < len(sp.tokens) && sp.tokens[sp.pos].kind == tokName:
+				n := fieldNode{spread: sp.tokens[sp.pos].value}
+				sp.pos++
+				var err error
+				if n.conditional, err = sp.directives(); err != nil {
+					return nil, err
+				}
+				nodes = append(nodes, n)
+				continue
+			}
+
+			conditional, err := sp.directives()
+			if err != nil {
+				return nil, err
+			}
+			if !sp.peek("{") {
+				return nil, fmt.Errorf("graphql syntax error: inline fragment at offset %d has no selection set", t.start)
+			}
+			children, err := sp.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			for _, n := range children {
+				n.conditional = n.conditional || typed || conditional
+				nodes = append(nodes, n)
+			}
+
+		case t.kind == tokName:
+			n := fieldNode{key: t.value, name: t.value}
+			sp.pos++
+			if sp.peek(":") && sp.pos+1 < len(sp.tokens) {
+				n.name = sp.tokens[sp.pos+1].value
+				sp.pos += 2
+			}
+			if sp.peek("(") {
+				start := sp.pos
+				if err := sp.skipParens(); err != nil {
+					return nil, err
+				}
+				n.args = sp.tokens[start:sp.pos]
+			}
+
+			var err error
+			if n.conditional, err = sp.directives(); err != nil {
+				return nil, err
+			}
+			n.end = sp.tokens[sp.pos-1].end
+
+			if sp.peek("{") {
+				if n.children, err = sp.selectionSet(); err != nil {
+					return nil, err
+				}
+			}
+			nodes = append(nodes, n)
+
+		default:
+			return nil, fmt.Errorf("graphql syntax error: unexpected %q at offset %d", t.value, t.start)
+		}
+	}
+}
+
+// directives moves past any directives at the current position and
+// reports whether @include or @skip was among them.
+func (sp *selectionParser) directives() (bool, error) {
+	var conditional bool
+	for sp.peek("@") {
+		if sp.pos+1 < len(sp.tokens) {
+			switch sp.tokens[sp.pos+1].value {
+			case "include", "skip":
+				conditional = true
+			}
+		}
+		sp.pos += 2
+		if sp.peek("(") {
+			if err := sp.skipParens(); err != nil {
+				return false, err
+			}
+		}
+	}
+	return conditional, nil
+}
+
+// skipParens moves past the parenthesized tokens that start at the current
+// position.
+func (sp *selectionParser) skipParens() error {
+	start := sp.tokens[sp.pos].start
+
+	var depth int
+	for ; sp.pos < len(sp.tokens); sp.pos++ {
+		if sp.tokens[sp.pos].kind != tokPunct {
+			continue
+		}
+		switch sp.tokens[sp.pos].value {
+		case "(":
+			depth++
+		case ")":
+			depth--
+			if depth == 0 {
+				sp.pos++
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("graphql syntax error: unterminated arguments at offset %d", start)
+}
+
+// peek reports whether the token at the current position is the specified
+// punctuation or name.
+func (sp *selectionParser) peek(value string) bool {
+	if sp.pos >= len(sp.tokens) {
+		return false
+	}
+	t := sp.tokens[sp.pos]
+	return (t.kind == tokPunct || t.kind == tokName) && t.value == value
+}
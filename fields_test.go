@@ -0,0 +1,60 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSelectedFields(t *testing.T) {
+	t.Log("Given the need to know the fields a document selects.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the document has aliases, fragments, and directives.", testID)
+		{
+			document := `query {
+	city: getCity(id: "0x1") {
+		name
+		...CityFields
+		... on City { mayor { name } }
+		posts @include(if: $withPosts) { title }
+	}
+}
+
+fragment CityFields on City { name population }`
+
+			got, err := graphql.SelectedFields(document)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to get the fields: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to get the fields.", success, testID)
+
+			exp := []graphql.Field{
+				{Key: "city", Name: "getCity", Fields: []graphql.Field{
+					{Key: "name", Name: "name"},
+					{Key: "population", Name: "population"},
+					{Key: "mayor", Name: "mayor", Conditional: true, Fields: []graphql.Field{
+						{Key: "name", Name: "name"},
+					}},
+					{Key: "posts", Name: "posts", Conditional: true, Fields: []graphql.Field{
+						{Key: "title", Name: "title"},
+					}},
+				}},
+			}
+			if diff := cmp.Diff(got, exp); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the expected fields. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the expected fields.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a fragment spreads itself.", testID)
+		{
+			if _, err := graphql.SelectedFields(`{ a { ...F } } fragment F on A { b { ...F } }`); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get an error.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an error.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package graphql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// NormalizeQuery returns the document with comments, commas, and
+// insignificant whitespace removed, so documents that only differ in
+// formatting produce the same result. Literals are kept as they are.
+func NormalizeQuery(document string) string {
+	return normalize(document, false)
+}
+
+// Fingerprint returns a hash that identifies the shape of the document.
+// The document is normalized and string and number literals are masked,
+// so queries built with different formatting or inlined values produce
+// the same fingerprint. It's suitable for grouping metrics and logs.
+func Fingerprint(document string) string {
+	sum := sha256.Sum256([]byte(normalize(document, true)))
+	return hex.EncodeToString(sum[:])
+}
+
+// normalize writes the tokens of the document separated by a single space
+// only where two names or numbers would otherwise run together. String and
+// number literals are replaced with ? when masking. A document that can't
+// be scanned is returned as it is.
+func normalize(document string, mask bool) string {
+	tokens, err := lex(document)
+	if err != nil {
+		return document
+	}
+
+	var b strings.Builder
+	b.Grow(len(document))
+
+	var prev tokenKind
+	for _, t := range tokens {
+		kind, value := t.kind, t.value
+		if mask && (kind == tokString || kind == tokNumber) {
+			kind, value = tokNumber, "?"
+		}
+
+		if isWord(prev) && isWord(kind) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(value)
+		prev = kind
+	}
+
+	return b.String()
+}
+
+// isWord reports whether tokens of the kind need to be separated by a space.
+func isWord(kind tokenKind) bool {
+	return kind == tokName || kind == tokNumber
+}
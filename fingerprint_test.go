@@ -0,0 +1,84 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Log("Given the need to treat equivalent queries as one.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen normalizing a formatted query.", testID)
+		{
+			query := `
+				# Find the city.
+				query GetCity($id: ID!, $first: Int = 10) {
+					getCity(id: $id, name: "Miami  Beach") {
+						name
+						... on City { lat }
+					}
+				}`
+
+			exp := `query GetCity($id:ID!$first:Int=10){getCity(id:$id name:"Miami  Beach"){name...on City{lat}}}`
+			if got := graphql.NormalizeQuery(query); got != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould get the normalized query:\n%s\n%s", failed, testID, got, exp)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the normalized query.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen fingerprinting queries with different literals.", testID)
+		{
+			a := graphql.Fingerprint(`query { queryCity(filter: {name: {eq: "Miami"}}, first: 10) { name } }`)
+			b := graphql.Fingerprint(`query{queryCity(filter:{name:{eq:"Tampa"}} first:-5){name}}`)
+			c := graphql.Fingerprint(`query { queryCity(filter: {name: {eq: "Miami"}}, first: 10) { name lat } }`)
+
+			if a != b {
+				t.Fatalf("\t%s\tTest %d:\tShould get the same fingerprint: %s %s", failed, testID, a, b)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the same fingerprint.", success, testID)
+
+			if a == c {
+				t.Fatalf("\t%s\tTest %d:\tShould get a different fingerprint for a different shape.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get a different fingerprint for a different shape.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen caching queries that differ in formatting.", testID)
+		{
+			var calls int
+			f := func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				io.WriteString(w, `{"data": {"getCity": {"name": "Miami"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithCache(graphql.NewMemoryCache(), time.Minute))
+
+			queries := []string{
+				`query { getCity(id: "0x1") { name } }`,
+				"query {\n\tgetCity(id: \"0x1\") {\n\t\tname\n\t}\n}",
+			}
+			for _, query := range queries {
+				if err := gql.Execute(context.Background(), query, nil); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+			}
+
+			if calls != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould serve the second query from the cache: %d calls", failed, testID, calls)
+			}
+			t.Logf("\t%s\tTest %d:\tShould serve the second query from the cache.", success, testID)
+		}
+	}
+}
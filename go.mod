@@ -1,5 +1,11 @@
 module github.com/ardanlabs/graphql
 
-go 1.14
+go 1.19
 
-require github.com/google/go-cmp v0.5.5
+require (
+	github.com/google/go-cmp v0.5.5
+	github.com/gorilla/websocket v1.5.0
+	github.com/vektah/gqlparser/v2 v2.5.11
+)
+
+require github.com/agnivade/levenshtein v1.1.1 // indirect
@@ -6,11 +6,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -37,32 +39,101 @@ var defaultClient = http.Client{
 // GraphQL represents a client that can execute graphql and raw requests
 // against a host.
 type GraphQL struct {
-	url     string
-	headers map[string]string
-	client  *http.Client
-	logFunc func(s string)
+	url              string
+	graphqlPath      string
+	queryParams      map[string]url.Values
+	headers          map[string]string
+	client           *http.Client
+	logFunc          func(s string)
+	logLevel         LogLevel
+	hedgeDelay       time.Duration
+	maxHedges        int
+	hosts            *hostPool
+	maxRetries       int
+	backoff          time.Duration
+	cache            Cache
+	cacheTTL         time.Duration
+	entities         *entityCache
+	etags            Cache
+	etagTTL          time.Duration
+	bodyTransformer  BodyTransformer
+	codec            Codec
+	errorBodyLimit   int
+	noCapture        bool
+	registry         *Registry
+	validation       *schemaValidation
+	login            *dgraphLogin
+	authToken        string
+	compression      bool
+	compressMin      int
+	transport        Transport
+	ctxHeaders       []func(ctx context.Context) map[string]string
+	headerSources    []*headerSource
+	requestIDHeader  string
+	timeout          time.Duration
+	closers          *closerSet
+	ownedTransport   *http.Transport
+	transportOpts    []func(t *http.Transport)
+	configErr        error
+	connTrace        func(ConnTrace)
+	connTraceEnabled bool
+	abortRetries     int
+	abortBackoff     time.Duration
+	decodeHooks      []DecodeHook
+	persisted        map[string]bool
+	debug            func(DebugInfo)
+	debugEnabled     bool
+	signer           RequestSigner
+	throttle         *throttler
+	bodyHash         *bodyHash
+	classifier       ErrorClassifier
+	dryRun           DryRunStub
+	rewriters        []QueryRewriter
+	deprecationWarn  func(operation Operation, deprecations []Deprecation)
+	costBudget       *CostConfig
+	limits           *queryLimits
+	timeFormat       TimeFormat
+	audit            *AuditConfig
+	slowThreshold    time.Duration
+	slowQuery        func(SlowQuery)
+	slowQueryEnabled bool
+	stats            *clientStats
+	shedder          *shedder
+	subs             *subscriptionPool
+	subsConfig       subscriptionConfig
 }
 
 // New constructs a GraphQL that can be used to execute graphql and raw requests
 // against the specified url. The url represents a fully qualified URL without
 // the `graphql` endpoint attached. If `/graphql` is provided, it's trimmed off.
 func New(url string, options ...func(gql *GraphQL)) *GraphQL {
-	url = strings.TrimSuffix(url, "/graphql")
-	url = strings.TrimSuffix(url, "/") + "/"
-
 	gql := GraphQL{
-		url:     url,
-		headers: make(map[string]string),
-		client:  &defaultClient,
+		url:         normalizeURL(url),
+		graphqlPath: "graphql",
+		headers:     make(map[string]string),
+		client:      &defaultClient,
+		closers:     newCloserSet(),
+		stats:       &clientStats{},
+		subs:        &subscriptionPool{},
+
+		errorBodyLimit: defaultErrorBodyLimit,
 	}
 
 	for _, option := range options {
 		option(&gql)
 	}
+	gql.ownTransport()
 
 	return &gql
 }
 
+// normalizeURL trims off the `graphql` endpoint and guarantees the url ends
+// with a trailing slash.
+func normalizeURL(url string) string {
+	url = strings.TrimSuffix(url, "/graphql")
+	return strings.TrimSuffix(url, "/") + "/"
+}
+
 // WithClient adds a custom client for processing requests. It's recommend
 // to not use the default client and provide your own.
 func WithClient(client *http.Client) func(gql *GraphQL) {
@@ -72,7 +143,7 @@ func WithClient(client *http.Client) func(gql *GraphQL) {
 }
 
 // WithLogging acceps a function for capturing raw execution messages for the
-// purpose of application logging.
+// purpose of application logging. What is logged depends on the log level.
 func WithLogging(logFunc func(s string)) func(gql *GraphQL) {
 	return func(gql *GraphQL) {
 		gql.logFunc = logFunc
@@ -91,6 +162,82 @@ func WithHeader(key string, value string) func(gql *GraphQL) {
 	}
 }
 
+// WithContextHeaders adds a function that extracts request scoped values
+// from the context, like a tenant id, trace id or user token placed there by
+// upstream middleware, and returns them as headers to add to the request.
+// The function is called for every request and the headers it returns take
+// precedence over the ones provided with WithHeader.
+func WithContextHeaders(f func(ctx context.Context) map[string]string) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		if f != nil {
+			gql.ctxHeaders = append(gql.ctxHeaders, f)
+		}
+	}
+}
+
+// WithTimeout sets a deadline of the specified duration on every request
+// whose context doesn't already have one, so a stalled host can't hang the
+// caller. Requests that exceed a deadline return an error that matches
+// ErrTimeout.
+func WithTimeout(timeout time.Duration) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.timeout = timeout
+	}
+}
+
+// WithGraphQLPath sets the path of the graphql endpoint for hosts that don't
+// mount it at url/graphql. The path is relative to the url, for example
+// "api/v2/graphql". Every call that uses the url/graphql endpoint by
+// default uses this path instead.
+func WithGraphQLPath(path string) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		if path = strings.Trim(path, "/"); path != "" {
+			gql.graphqlPath = path
+		}
+	}
+}
+
+// WithQueryParam adds a query parameter to every request made to the
+// specified endpoint, like Dgraph's timeout parameter on the query
+// endpoint. Use the QueryParam function to add a parameter to a single
+// call.
+func WithQueryParam(endpoint string, key string, value string) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		endpoint = strings.Trim(endpoint, "/")
+		if gql.queryParams == nil {
+			gql.queryParams = make(map[string]url.Values)
+		}
+		if gql.queryParams[endpoint] == nil {
+			gql.queryParams[endpoint] = make(url.Values)
+		}
+		gql.queryParams[endpoint].Add(key, value)
+	}
+}
+
+// QueryParam returns a copy of the context that adds the query parameter to
+// the request made with it, like Dgraph's debug parameter.
+func QueryParam(ctx context.Context, key string, value string) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		params := make(url.Values, len(opts.queryParams)+1)
+		for k, v := range opts.queryParams {
+			params[k] = append([]string(nil), v...)
+		}
+		params.Add(key, value)
+		opts.queryParams = params
+	})
+}
+
+// WithHedging enables request hedging for read-only queries. If an attempt
+// hasn't responded within the specified delay, a duplicate request is issued
+// up to maxHedges times. The first successful response is returned and the
+// remaining requests are canceled. Mutations are never hedged.
+func WithHedging(delay time.Duration, maxHedges int) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.hedgeDelay = delay
+		gql.maxHedges = maxHedges
+	}
+}
+
 // WithVariable allows for the submission of variables when executing graphql
 // against the host for queries that supports variable substitution.
 func WithVariable(key string, value interface{}) func(m map[string]interface{}) {
@@ -100,7 +247,9 @@ func WithVariable(key string, value interface{}) func(m map[string]interface{})
 }
 
 // Execute performs a graphql request against the configured host on the
-// url/graphql endpoint.
+// url/graphql endpoint. The data of the response is decoded into the
+// response value. If the response is a *json.RawMessage or an io.Writer the
+// data is provided verbatim without being decoded.
 func (g *GraphQL) Execute(ctx context.Context, graphql string, response interface{}, variables ...func(m map[string]interface{})) error {
 	var queryVars map[string]interface{}
 	if len(variables) > 0 {
@@ -109,7 +258,7 @@ func (g *GraphQL) Execute(ctx context.Context, graphql string, response interfac
 			variable(queryVars)
 		}
 	}
-	return g.query(ctx, "graphql", graphql, queryVars, response)
+	return g.query(ctx, g.graphqlPath, graphql, queryVars, response)
 }
 
 // ExecuteOnEndpoint performs a graphql request against the configured host on
@@ -129,6 +278,119 @@ func (g *GraphQL) ExecuteOnEndpoint(ctx context.Context, endpoint string, graphq
 // around the query and variables. Then executes the request against the
 // configured url/endpoint.
 func (g *GraphQL) query(ctx context.Context, endpoint string, graphql string, queryVars map[string]interface{}, response interface{}) error {
+	pq, err := g.prepare(ctx, endpoint, graphql, queryVars)
+	if err != nil {
+		return err
+	}
+	ctx, graphql, queryVars, b, op := pq.ctx, pq.graphql, pq.vars, pq.body, pq.op
+
+	readOnly := op.readOnly()
+	cacheable := readOnly && !callOpts(ctx).noCache && g.dryRunFor(ctx) == nil
+	if cacheable && (g.entities != nil || g.cache != nil || g.etags != nil) {
+		key, err := queryCacheKey(endpoint, graphql, queryVars)
+		if err != nil {
+			return err
+		}
+		switch {
+		case g.entities != nil:
+			return g.entityRequest(ctx, key, endpoint, graphql, queryVars, b, response)
+		case g.cache != nil:
+			return g.cachedRequest(ctx, key, endpoint, b, response)
+		}
+		return g.etagRequest(ctx, key, endpoint, b, response)
+	}
+
+	send := func() error {
+		return g.execute(ctx, endpoint, b, readOnly, response)
+	}
+	if g.entities != nil && op.Type == "mutation" && g.dryRunFor(ctx) == nil {
+		send = g.entityMutation(ctx, endpoint, graphql, queryVars, b, response)
+	}
+
+	if g.audit != nil && op.Type == "mutation" {
+		return g.audited(ctx, endpoint, op, queryVars, send)
+	}
+
+	return send()
+}
+
+// preparedQuery represents a graphql request that is ready to be sent.
+type preparedQuery struct {
+	ctx     context.Context
+	graphql string
+	vars    map[string]interface{}
+	body    []byte
+	op      Operation
+}
+
+// prepare applies the rewriters and the checks to the query and variables,
+// and encodes the request. The context of the prepared query labels the
+// request with its operation.
+func (g *GraphQL) prepare(ctx context.Context, endpoint string, graphql string, queryVars map[string]interface{}) (preparedQuery, error) {
+	if len(g.rewriters) > 0 {
+		var err error
+		if graphql, queryVars, err = g.rewrite(ctx, graphql, queryVars); err != nil {
+			return preparedQuery{}, err
+		}
+	}
+
+	queryVars, err := g.formatVariables(queryVars)
+	if err != nil {
+		return preparedQuery{}, err
+	}
+
+	if err := g.checkLimits(endpoint, graphql); err != nil {
+		return preparedQuery{}, err
+	}
+
+	if err := g.checkCost(endpoint, graphql); err != nil {
+		return preparedQuery{}, err
+	}
+
+	if err := g.validate(ctx, endpoint, graphql); err != nil {
+		return preparedQuery{}, err
+	}
+
+	// Only the url/graphql endpoint serves persisted queries, so the
+	// documents for the other endpoints, like the login, are sent in full.
+	var b []byte
+	if g.persisted != nil && endpoint == g.graphqlPath {
+		b, err = g.encodePersisted(graphql, queryVars)
+	} else {
+		b, err = encodeQuery(graphql, queryVars)
+	}
+	if err != nil {
+		return preparedQuery{}, err
+	}
+
+	// The operation labels log messages, errors, and traces, and traces,
+	// slow queries, and audit records are grouped by the fingerprint of the
+	// query. The fingerprint is only computed when one of them is enabled.
+	op := ParseOperation(graphql)
+	var fingerprint string
+	if g.connTraceEnabled || g.slowQueryEnabled || g.audit != nil {
+		fingerprint = Fingerprint(graphql)
+	}
+	ctx = withCallOption(ctx, func(opts *callOptions) {
+		opts.operation = op
+		opts.fingerprint = fingerprint
+	})
+
+	pq := preparedQuery{
+		ctx:     ctx,
+		graphql: graphql,
+		vars:    queryVars,
+		body:    b,
+		op:      op,
+	}
+
+	return pq, nil
+}
+
+// encodeQuery applies the graphql request document around the query and
+// variables. The document is encoded in a pooled buffer and copied out, so
+// the returned slice is exactly sized and the buffer growth is reused.
+func encodeQuery(graphql string, queryVars map[string]interface{}) ([]byte, error) {
 	request := struct {
 		Query     string                 `json:"query"`
 		Variables map[string]interface{} `json:"variables"`
@@ -137,72 +399,475 @@ func (g *GraphQL) query(ctx context.Context, endpoint string, graphql string, qu
 		Variables: queryVars,
 	}
 
-	var b bytes.Buffer
-	if err := json.NewEncoder(&b).Encode(request); err != nil {
-		return fmt.Errorf("graphql encoding error: %w", err)
+	b := getBuffer()
+	defer putBuffer(b)
+
+	if err := json.NewEncoder(b).Encode(request); err != nil {
+		return nil, fmt.Errorf("graphql encoding error: %w", err)
 	}
 
-	return g.RawRequest(ctx, endpoint, &b, response)
+	return append([]byte(nil), b.Bytes()...), nil
+}
+
+// decodeData decodes the data of a response into the destination. A
+// *json.RawMessage receives the data verbatim and an io.Writer has the data
+// written to it, else the hooks are applied when there are any. Errors embed
+// the data up to the limit.
+func decodeData(data []byte, response interface{}, hooks []DecodeHook, limit int) error {
+	if len(data) == 0 || response == nil {
+		return nil
+	}
+
+	if w, ok := response.(io.Writer); ok {
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("graphql copy error: %w", err)
+		}
+		return nil
+	}
+
+	if _, ok := response.(*json.RawMessage); !ok && len(hooks) > 0 {
+		return decodeWithHooks(data, response, hooks, limit)
+	}
+
+	if err := json.Unmarshal(data, response); err != nil {
+		return fmt.Errorf("graphql decoding error: %w response: %s", err, excerpt(data, limit))
+	}
+
+	return nil
+}
+
+// execute sends the encoded graphql request, hedging the request if
+// hedging is enabled and the request is read-only.
+func (g *GraphQL) execute(ctx context.Context, endpoint string, body []byte, readOnly bool, response interface{}) error {
+	if g.maxHedges > 0 && readOnly {
+		return g.hedgedRequest(ctx, endpoint, body, response)
+	}
+
+	if g.abortRetries > 0 && !readOnly {
+		return g.retryAborted(ctx, func() error {
+			return g.RawRequest(ctx, endpoint, bytes.NewReader(body), response)
+		})
+	}
+
+	return g.RawRequest(ctx, endpoint, bytes.NewReader(body), response)
 }
 
 // RawRequest performs the actual execution of a request against the specified
 // url/endpoint. Use this function only when the request doesn't require a
 // graphql document wrapper.
 func (g *GraphQL) RawRequest(ctx context.Context, endpoint string, r io.Reader, response interface{}) error {
+	return g.gate(ctx, func(ctx context.Context) error {
+		return g.rawRequest(ctx, endpoint, r, response)
+	})
+}
+
+// gate applies the load shedder, stats, and timeout every request goes
+// through before it's performed by the specified function, and classifies
+// the error it returns.
+func (g *GraphQL) gate(ctx context.Context, do func(ctx context.Context) error) (err error) {
+	if g.shedder != nil {
+		var inFlight int64
+		if g.stats != nil {
+			inFlight = g.stats.inFlight.Load()
+		}
+		if err := g.shedder.shed(callOpts(ctx).priority, inFlight); err != nil {
+			if g.stats != nil {
+				g.stats.shed.Add(1)
+			}
+			return err
+		}
+		defer func() { g.shedder.record(err) }()
+	}
 
-	// Use the TeeReader to capture the request being sent. This is needed if the
-	// requrest fails for the error being returned or for logging if a log
-	// function is provided. The TeeReader will write the request to this buffer
-	// during the http operation.
-	var request bytes.Buffer
-	r = io.TeeReader(r, &request)
+	if g.stats != nil {
+		g.stats.inFlight.Add(1)
+		defer func() { g.stats.done(err) }()
+	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, g.url+endpoint, r)
-	if err != nil {
-		return fmt.Errorf("graphql create request error: %w", err)
+	if g.closers.isClosed() {
+		return ErrClosed
 	}
 
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-	for key, value := range g.headers {
-		req.Header.Set(key, value)
+	if g.configErr != nil {
+		return g.configErr
+	}
+
+	if g.timeout > 0 {
+		if _, ok := ctx.Deadline(); !ok {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, g.timeout)
+			defer cancel()
+		}
+	}
+
+	if err := do(ctx); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return &timeoutError{err: err}
+		}
+		return g.classify(err)
+	}
+
+	return nil
+}
+
+// rawRequest sends the request and decodes the response.
+func (g *GraphQL) rawRequest(ctx context.Context, endpoint string, r io.Reader, response interface{}) error {
+
+	// Capture the request being sent. This is needed if the request fails for
+	// the error being returned or for logging if a log function is provided.
+	// The request is only copied when it's needed, and never when capture
+	// is disabled.
+	var request *requestCapture
+	if g.noCapture {
+		request, r = countRequest(r)
+	} else {
+		request, r = captureRequest(r)
 	}
 
-	resp, err := g.client.Do(req)
+	// Generate the id that correlates this request with the host's logs. The
+	// id is shared by every attempt made to deliver the request.
+	var requestID string
+	if g.requestIDHeader != "" {
+		requestID = newRequestID()
+		ctx = withCallOption(ctx, func(opts *callOptions) {
+			opts.requestID = requestID
+		})
+	}
+
+	if g.throttle != nil {
+		if err := g.throttle.wait(ctx, g); err != nil {
+			return err
+		}
+	}
+
+	if g.codec != nil && g.codecTarget(ctx, response) {
+		ctx = withCallOption(ctx, func(opts *callOptions) {
+			opts.codec = true
+		})
+	}
+
+	start := time.Now()
+	resp, err := g.send(ctx, endpoint, r)
 	if err != nil {
-		return fmt.Errorf("graphql request error: %w", err)
+		return err
 	}
 	defer resp.Body.Close()
 
-	data, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
+	if g.throttle != nil {
+		g.throttle.updateHeader(resp.Header)
+	}
+
+	// The response is read into a pooled buffer. Everything that outlives
+	// this call is copied out of it by the decoder.
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
 		return fmt.Errorf("graphql copy error: %w", err)
 	}
+	data := buf.Bytes()
+
+	if g.stats != nil {
+		g.stats.bytesSent.Add(uint64(request.Len()))
+		g.stats.bytesReceived.Add(uint64(len(data)))
+	}
+
+	if g.slowQueryEnabled {
+		g.reportSlowQuery(ctx, SlowQuery{
+			Endpoint:      endpoint,
+			Operation:     callOpts(ctx).operation.String(),
+			Fingerprint:   callOpts(ctx).fingerprint,
+			RequestID:     requestID,
+			Duration:      time.Since(start),
+			RequestBytes:  request.Len(),
+			ResponseBytes: len(data),
+		})
+	}
+
+	info := callOpts(ctx).responseInfo
+	if info != nil {
+		info.StatusCode = resp.StatusCode
+		info.Header = resp.Header
+		info.RequestID = requestID
+	}
+
+	// A conditional request that wasn't modified has no body to decode, the
+	// stored data is used instead.
+	if etag := callOpts(ctx).etag; etag != nil && etag.response(resp) {
+		return nil
+	}
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("graphql op error: status code: %s", resp.Status)
+		httpErr := newHTTPError(resp)
+		httpErr.RequestID = requestID
+		httpErr.Operation = callOpts(ctx).operation.String()
+		return httpErr
 	}
 
-	if g.logFunc != nil {
-		g.logFunc(fmt.Sprintf("request:[%s] data:[%s]", request.String(), string(data)))
+	if g.bodyTransformer != nil {
+		if data, err = g.decodeBody(ctx, data); err != nil {
+			return err
+		}
 	}
 
-	result := struct {
-		Data   interface{}
-		Errors []struct {
-			Message string
+	// The request id and operation prefix the log message and errors when
+	// they are known.
+	operation := callOpts(ctx).operation.String()
+	prefix := labels(requestID, operation)
+
+	if log := g.logger(ctx); log != nil {
+		if g.logLevelFor(ctx) == LogDebug {
+			log(fmt.Sprintf("%srequest:[%s] data:[%s]", prefix, request.Excerpt(g.errorBodyLimit), excerpt(data, g.errorBodyLimit)))
+		} else {
+			log(fmt.Sprintf("%sendpoint:[%s] duration:[%s] request:[%d bytes] data:[%d bytes]", prefix, endpoint, time.Since(start), request.Len(), len(data)))
 		}
+	}
+
+	// A writer receives the data verbatim and hooks are applied once the
+	// response is known to have no errors.
+	var raw json.RawMessage
+	dest := response
+	_, isWriter := response.(io.Writer)
+	hooks := g.decodeHooksFor(ctx)
+	if isWriter || len(hooks) > 0 {
+		dest = &raw
+	}
+
+	result := struct {
+		Data       interface{}
+		Errors     []ResponseError
+		Extensions json.RawMessage
 	}{
-		Data: response,
+		Data: dest,
 	}
-	if err := json.Unmarshal(data, &result); err != nil {
-		return fmt.Errorf("graphql decoding error: %w response: %s", err, string(data))
+	if callOpts(ctx).codec && g.codecResponse(resp) {
+		if result.Errors, err = g.decodeCodec(data, response); err != nil {
+			return fmt.Errorf("graphql decoding error: %s%s: %w", prefix, g.codec.ContentType(), err)
+		}
+	} else if err := json.Unmarshal(data, &result); err != nil {
+		return fmt.Errorf("graphql decoding error: %s%w response: %s", prefix, err, excerpt(data, g.errorBodyLimit))
+	}
+
+	// Throttled requests report the budget too, so it's recorded before the
+	// errors are checked.
+	if g.throttle != nil && len(result.Extensions) > 0 {
+		g.throttle.updateExtensions(result.Extensions, time.Now())
 	}
 
 	if len(result.Errors) > 0 {
-		return fmt.Errorf("graphql op error: request:[%s] error:[%s]", request.String(), result.Errors[0].Message)
+		return &GraphQLError{
+			Errors:    result.Errors,
+			Request:   request.Excerpt(g.errorBodyLimit),
+			RequestID: requestID,
+			Operation: operation,
+		}
+	}
+
+	if dest == &raw {
+		if err := decodeData(raw, response, hooks, g.errorBodyLimit); err != nil {
+			return err
+		}
+	}
+
+	if opts := callOpts(ctx); opts.extensions != nil {
+		*opts.extensions = result.Extensions
+	}
+
+	if info != nil {
+		info.Extensions = result.Extensions
+	}
+
+	if g.debugEnabled && len(result.Extensions) > 0 {
+		g.reportDebug(ctx, endpoint, operation, result.Extensions)
 	}
 
 	return nil
 }
+
+// send executes the http request against the configured host. If retries
+// are enabled, requests rejected by the host are retried.
+func (g *GraphQL) send(ctx context.Context, endpoint string, r io.Reader) (*Response, error) {
+	if g.bodyTransformer != nil {
+		var err error
+		if r, err = g.encodeBody(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+
+	if g.compression {
+		var err error
+		if ctx, r, err = g.compress(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+
+	if g.maxRetries > 0 {
+		return g.sendWithRetry(ctx, endpoint, r)
+	}
+	return g.sendOnce(ctx, endpoint, r)
+}
+
+// sendOnce executes the http request against the configured host. If
+// multiple hosts are configured, the host is selected by the host policy.
+func (g *GraphQL) sendOnce(ctx context.Context, endpoint string, r io.Reader) (*Response, error) {
+	if g.hosts != nil {
+		return g.hosts.send(ctx, g, endpoint, r)
+	}
+	return g.sendTo(ctx, g.url, endpoint, r)
+}
+
+// sendTo executes the http request against the specified host url.
+func (g *GraphQL) sendTo(ctx context.Context, url string, endpoint string, r io.Reader) (*Response, error) {
+	contentType := "application/json"
+	if opts := callOpts(ctx); opts.contentType != "" {
+		contentType = opts.contentType
+	}
+
+	header := make(http.Header)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Content-Type", contentType)
+	header.Set("Accept", "application/json")
+	if callOpts(ctx).codec {
+		header.Set("Accept", g.codecAccept())
+	}
+	if accept := callOpts(ctx).accept; accept != "" {
+		header.Set("Accept", accept)
+	}
+	if g.compression {
+		header.Set("Accept-Encoding", "gzip")
+		if callOpts(ctx).gzipped {
+			header.Set("Content-Encoding", "gzip")
+		}
+	}
+	g.setHeaders(ctx, header)
+	if err := g.setProvidedHeaders(ctx, header); err != nil {
+		return nil, err
+	}
+
+	if id := callOpts(ctx).requestID; id != "" {
+		header.Set(g.requestIDHeader, id)
+	}
+
+	if etag := callOpts(ctx).etag; etag != nil {
+		etag.setHeader(header)
+	}
+
+	if g.authToken != "" && isAdminEndpoint(endpoint) {
+		header.Set("X-Dgraph-AuthToken", g.authToken)
+	}
+
+	if g.login != nil && g.login.userID != "" && !callOpts(ctx).noLogin {
+		token, err := g.login.token(ctx, g, false)
+		if err != nil {
+			return nil, err
+		}
+		header.Set("X-Dgraph-AccessToken", token)
+	}
+
+	req := Request{
+		Method:   http.MethodPost,
+		URL:      g.endpointURL(ctx, url, endpoint),
+		Endpoint: endpoint,
+		Header:   header,
+		Body:     r,
+	}
+
+	resp, err := g.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		g.expireProvidedHeaders()
+	}
+
+	if g.compression {
+		if err := decompress(resp); err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// get performs a GET request against the configured host on the specified
+// url/endpoint and returns the response body. This is used for the
+// non-graphql endpoints a host provides, like health checks.
+func (g *GraphQL) get(ctx context.Context, endpoint string) ([]byte, error) {
+	header := make(http.Header)
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Accept", "application/json")
+	g.setHeaders(ctx, header)
+	if err := g.setProvidedHeaders(ctx, header); err != nil {
+		return nil, err
+	}
+
+	req := Request{
+		Method:   http.MethodGet,
+		URL:      g.endpointURL(ctx, g.url, endpoint),
+		Endpoint: endpoint,
+		Header:   header,
+	}
+
+	resp, err := g.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("graphql copy error: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, g.classify(newHTTPError(resp))
+	}
+
+	return data, nil
+}
+
+// endpointURL returns the url for the endpoint on the specified host with
+// the configured and per-call query parameters applied.
+func (g *GraphQL) endpointURL(ctx context.Context, host string, endpoint string) string {
+	path, query := endpoint, ""
+	if i := strings.IndexByte(endpoint, '?'); i >= 0 {
+		path, query = endpoint[:i], endpoint[i+1:]
+	}
+
+	configured := g.queryParams[path]
+	perCall := callOpts(ctx).queryParams
+	if len(configured) == 0 && len(perCall) == 0 {
+		return host + endpoint
+	}
+
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return host + endpoint
+	}
+	for _, set := range []url.Values{configured, perCall} {
+		for key, values := range set {
+			for _, value := range values {
+				params.Add(key, value)
+			}
+		}
+	}
+
+	return host + path + "?" + params.Encode()
+}
+
+// setHeaders adds the configured headers and the headers extracted from the
+// context to the request header.
+func (g *GraphQL) setHeaders(ctx context.Context, header http.Header) {
+	for key, value := range g.headers {
+		header.Set(key, value)
+	}
+
+	for _, f := range g.ctxHeaders {
+		for key, value := range f(ctx) {
+			if key != "" {
+				header.Set(key, value)
+			}
+		}
+	}
+}
@@ -21,7 +21,8 @@ const (
 // TestGraphQL validates all the client support.
 func TestGraphQL(t *testing.T) {
 	t.Run("query", query)
-	t.Run("error", errors)
+	t.Run("error", queryError)
+	t.Run("path", queryPath)
 }
 
 func query(t *testing.T) {
@@ -105,7 +106,7 @@ func query(t *testing.T) {
 	}
 }
 
-func errors(t *testing.T) {
+func queryError(t *testing.T) {
 	type document struct {
 		Field1 string  `json:"field1"`
 		Field2 int     `json:"field2"`
@@ -165,3 +166,40 @@ func errors(t *testing.T) {
 		}
 	}
 }
+
+func queryPath(t *testing.T) {
+	t.Log("Given the need to execute queries against a non-standard mount.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen using a custom path and query parameters.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v2/graphql" {
+					t.Fatalf("\t%s\tTest %d:\tShould use the custom path: %s", failed, testID, r.URL.Path)
+				}
+				t.Logf("\t%s\tTest %d:\tShould use the custom path.", success, testID)
+
+				if diff := cmp.Diff(r.URL.RawQuery, "debug=true&timeout=5s"); diff != "" {
+					t.Fatalf("\t%s\tTest %d:\tShould get the query parameters. Diff:\n%s", failed, testID, diff)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the query parameters.", success, testID)
+
+				io.WriteString(w, `{"data": {"__typename": "Query"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL,
+				graphql.WithGraphQLPath("/api/v2/graphql"),
+				graphql.WithQueryParam("api/v2/graphql", "timeout", "5s"),
+			)
+
+			ctx := graphql.QueryParam(context.Background(), "debug", "true")
+			if err := gql.Execute(ctx, `query { __typename }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package graphqltest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ardanlabs/graphql"
+)
+
+// AssertResponse fails the test if the decoded response doesn't match the
+// fields the document selects. See CheckResponse.
+func AssertResponse(t testing.TB, document string, response interface{}, nullable ...string) {
+	t.Helper()
+
+	if err := CheckResponse(document, response, nullable...); err != nil {
+		t.Errorf("%v", err)
+	}
+}
+
+// CheckResponse compares the decoded response, the value the data of the
+// response was decoded into, with the fields the document selects. This
+// catches drift between a query and its response type. Every selected
+// field must have a struct field and every struct field must be selected.
+// Selected fields must not be null unless they are conditional or their
+// path, like queryCity.mayor, is listed as nullable. Null is detected for
+// pointers, slices, maps, and interfaces. Struct fields are matched to
+// response keys like SelectionSet names them.
+func CheckResponse(document string, response interface{}, nullable ...string) error {
+	fields, err := graphql.SelectedFields(document)
+	if err != nil {
+		return fmt.Errorf("graphqltest: parsing document: %w", err)
+	}
+
+	rc := responseChecker{
+		nullable: make(map[string]bool),
+	}
+	for _, path := range nullable {
+		rc.nullable[path] = true
+	}
+
+	v := reflect.ValueOf(response)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return errors.New("graphqltest: response is nil")
+		}
+		v = v.Elem()
+	}
+	rc.checkSelection(v, fields, "", "")
+
+	if len(rc.problems) > 0 {
+		return fmt.Errorf("graphqltest: response doesn't match the document: %s", strings.Join(rc.problems, "; "))
+	}
+	return nil
+}
+
+// responseChecker collects the differences between a response and the
+// fields selected for it.
+type responseChecker struct {
+	nullable map[string]bool
+	problems []string
+}
+
+// addf records a problem.
+func (rc *responseChecker) addf(format string, a ...interface{}) {
+	rc.problems = append(rc.problems, fmt.Sprintf(format, a...))
+}
+
+// checkSelection compares the struct or map value with the fields of the
+// selection set. The path is the dotted list of keys used to match
+// nullable fields and the location adds the indexes of list items.
+func (rc *responseChecker) checkSelection(v reflect.Value, fields []graphql.Field, path string, loc string) {
+	switch {
+	case v.Kind() == reflect.Struct:
+		sfs := make(map[string]reflect.Value)
+		var keys []string
+		structFields(v, sfs, &keys)
+
+		for _, f := range fields {
+			sv, ok := sfs[f.Key]
+			if !ok {
+				rc.addf("%s is selected but %s has no field for it", join(loc, f.Key), v.Type())
+				continue
+			}
+			rc.checkValue(sv, f, join(path, f.Key), join(loc, f.Key))
+		}
+
+		for _, key := range keys {
+			if !selected(fields, key) {
+				rc.addf("%s is decoded but not selected", join(loc, key))
+			}
+		}
+
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String:
+		for _, f := range fields {
+			mv := v.MapIndex(reflect.ValueOf(f.Key).Convert(v.Type().Key()))
+			if !mv.IsValid() {
+				if !f.Conditional {
+					rc.addf("%s is missing", join(loc, f.Key))
+				}
+				continue
+			}
+			rc.checkValue(mv, f, join(path, f.Key), join(loc, f.Key))
+		}
+
+	default:
+		rc.addf("%s has a selection set but decodes into %s", loc, v.Type())
+	}
+}
+
+// checkValue checks the value decoded for the selected field.
+func (rc *responseChecker) checkValue(v reflect.Value, f graphql.Field, path string, loc string) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface || v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+		if v.IsNil() {
+			if !f.Conditional && !rc.nullable[path] {
+				rc.addf("%s is null", loc)
+			}
+			return
+		}
+		if v.Kind() != reflect.Ptr && v.Kind() != reflect.Interface {
+			break
+		}
+		v = v.Elem()
+	}
+
+	if len(f.Fields) == 0 {
+		return
+	}
+
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		for i := 0; i < v.Len(); i++ {
+			rc.checkValue(v.Index(i), f, path, fmt.Sprintf("%s[%d]", loc, i))
+		}
+		return
+	}
+
+	rc.checkSelection(v, f.Fields, path, loc)
+}
+
+// structFields maps the response keys of the struct fields to their values,
+// flattening embedded structs and inline fragments.
+func structFields(v reflect.Value, sfs map[string]reflect.Value, keys *[]string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		gqlTag, hasGQL := field.Tag.Lookup("graphql")
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if gqlTag == "-" || (!hasGQL && jsonName == "-") {
+			continue
+		}
+
+		fv := v.Field(i)
+		inline := hasGQL && strings.HasPrefix(gqlTag, "...")
+		if inline || (field.Anonymous && !hasGQL && jsonName == "") {
+			for fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					fv = reflect.New(fv.Type().Elem()).Elem()
+					break
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				structFields(fv, sfs, keys)
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		var key string
+		switch {
+		case hasGQL:
+			key = responseKey(gqlTag)
+		case jsonName != "":
+			key = jsonName
+		default:
+			r, n := utf8.DecodeRuneInString(field.Name)
+			key = string(unicode.ToLower(r)) + field.Name[n:]
+		}
+
+		if _, ok := sfs[key]; !ok {
+			*keys = append(*keys, key)
+		}
+		sfs[key] = fv
+	}
+}
+
+// responseKey returns the response key of a field written in a graphql
+// tag, like getCity(id: $id), which is the alias if one is provided, else
+// the field name.
+func responseKey(tag string) string {
+	tag = strings.TrimSpace(tag)
+	end := strings.IndexFunc(tag, func(r rune) bool {
+		return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if end < 0 {
+		return tag
+	}
+	return tag[:end]
+}
+
+// selected reports whether a field with the key is selected.
+func selected(fields []graphql.Field, key string) bool {
+	for _, f := range fields {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// join appends the key to the path.
+func join(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
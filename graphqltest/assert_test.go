@@ -0,0 +1,112 @@
+package graphqltest_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql/graphqltest"
+)
+
+func TestCheckResponse(t *testing.T) {
+	const document = `query {
+	queryCity {
+		name
+		mayor { name }
+		... on City { population @include(if: $withPopulation) }
+	}
+}`
+
+	type mayor struct {
+		Name string `json:"name"`
+	}
+
+	type city struct {
+		Name       string `json:"name"`
+		Mayor      *mayor `json:"mayor"`
+		Population *int   `json:"population"`
+	}
+
+	type response struct {
+		QueryCity []city `json:"queryCity"`
+	}
+
+	t.Log("Given the need to catch drift between a query and its response type.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the response matches the document.", testID)
+		{
+			resp := response{
+				QueryCity: []city{
+					{Name: "Miami", Mayor: &mayor{Name: "Francis"}},
+				},
+			}
+
+			graphqltest.AssertResponse(t, document, &resp)
+			t.Logf("\t%s\tTest %d:\tShould accept the response.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a field is null.", testID)
+		{
+			resp := response{
+				QueryCity: []city{
+					{Name: "Miami", Mayor: &mayor{Name: "Francis"}},
+					{Name: "Denver"},
+				},
+			}
+
+			err := graphqltest.CheckResponse(document, &resp)
+			if err == nil || !strings.Contains(err.Error(), "queryCity[1].mayor is null") {
+				t.Fatalf("\t%s\tTest %d:\tShould report the null field: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the null field.", success, testID)
+
+			if err := graphqltest.CheckResponse(document, &resp, "queryCity.mayor"); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould accept a nullable field: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould accept a nullable field.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the struct and the document have drifted.", testID)
+		{
+			type drifted struct {
+				Name  string `json:"name"`
+				State string `json:"state"`
+			}
+
+			resp := struct {
+				QueryCity []drifted `json:"queryCity"`
+			}{
+				QueryCity: []drifted{{Name: "Miami"}},
+			}
+
+			err := graphqltest.CheckResponse(document, &resp)
+			if err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould report the drift.", failed, testID)
+			}
+			for _, exp := range []string{"queryCity[0].mayor is selected", "queryCity[0].population is selected", "queryCity[0].state is decoded but not selected"} {
+				if !strings.Contains(err.Error(), exp) {
+					t.Fatalf("\t%s\tTest %d:\tShould report %q: %v", failed, testID, exp, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the drift.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the response is decoded into a map.", testID)
+		{
+			resp := map[string]interface{}{
+				"queryCity": []interface{}{
+					map[string]interface{}{"name": "Miami", "mayor": nil},
+				},
+			}
+
+			err := graphqltest.CheckResponse(document, resp)
+			if err == nil || !strings.Contains(err.Error(), "queryCity[0].mayor is null") {
+				t.Fatalf("\t%s\tTest %d:\tShould report the null field: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the null field.", success, testID)
+		}
+	}
+}
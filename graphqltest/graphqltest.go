@@ -0,0 +1,297 @@
+// Package graphqltest provides a mock graphql server for testing code that
+// uses the graphql client. Tests register expectations that match requests
+// by operation name or query, specify the response to return, and assert
+// the variables that were sent.
+package graphqltest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// Request represents a graphql request received by the server.
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Matcher reports whether a request matches an expectation.
+type Matcher func(req Request) bool
+
+// OperationName matches requests for the named operation.
+func OperationName(name string) Matcher {
+	return func(req Request) bool {
+		return req.OperationName == name
+	}
+}
+
+// QueryContains matches requests where the query contains the substring.
+func QueryContains(substr string) Matcher {
+	return func(req Request) bool {
+		return strings.Contains(req.Query, substr)
+	}
+}
+
+// QueryMatches matches requests where the query matches the regular
+// expression.
+func QueryMatches(re *regexp.Regexp) Matcher {
+	return func(req Request) bool {
+		return re.MatchString(req.Query)
+	}
+}
+
+// Any matches every request.
+func Any() Matcher {
+	return func(req Request) bool {
+		return true
+	}
+}
+
+// =============================================================================
+
+// Expectation represents a request the server expects to receive and the
+// response it will return.
+type Expectation struct {
+	match     Matcher
+	desc      string
+	variables map[string]interface{}
+	status    int
+	body      []byte
+	times     int
+	calls     int
+}
+
+// WithVariables asserts the request was sent with the specified variables.
+func (e *Expectation) WithVariables(variables map[string]interface{}) *Expectation {
+	e.variables = variables
+	return e
+}
+
+// Respond sets the value that is returned as the data of the response.
+func (e *Expectation) Respond(data interface{}) *Expectation {
+	body, err := json.Marshal(struct {
+		Data interface{} `json:"data"`
+	}{
+		Data: data,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("graphqltest: marshaling response: %v", err))
+	}
+	e.body = body
+	return e
+}
+
+// RespondJSON sets the raw JSON document that is returned as the response.
+func (e *Expectation) RespondJSON(document string) *Expectation {
+	e.body = []byte(document)
+	return e
+}
+
+// RespondError sets the graphql errors that are returned in the response.
+func (e *Expectation) RespondError(messages ...string) *Expectation {
+	type gqlError struct {
+		Message string `json:"message"`
+	}
+
+	errs := make([]gqlError, len(messages))
+	for i, msg := range messages {
+		errs[i] = gqlError{Message: msg}
+	}
+
+	body, err := json.Marshal(struct {
+		Errors []gqlError `json:"errors"`
+	}{
+		Errors: errs,
+	})
+	if err != nil {
+		panic(fmt.Sprintf("graphqltest: marshaling errors: %v", err))
+	}
+	e.body = body
+	return e
+}
+
+// RespondStatus sets the http status code of the response.
+func (e *Expectation) RespondStatus(statusCode int) *Expectation {
+	e.status = statusCode
+	return e
+}
+
+// Times limits the number of requests the expectation will match. By default
+// an expectation matches any number of requests.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+// =============================================================================
+
+// Server is a mock graphql server.
+type Server struct {
+	*httptest.Server
+	t testing.TB
+
+	mu           sync.Mutex
+	expectations []*Expectation
+	requests     []Request
+}
+
+// NewServer starts a mock graphql server. The server is closed and all the
+// expectations are asserted when the test completes.
+func NewServer(t testing.TB) *Server {
+	s := Server{
+		t: t,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	t.Cleanup(func() {
+		s.Close()
+		s.AssertExpectations()
+	})
+
+	return &s
+}
+
+// Expect registers an expectation for requests that match. Expectations are
+// evaluated in the order they are registered.
+func (s *Server) Expect(match Matcher) *Expectation {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e := Expectation{
+		match:  match,
+		desc:   fmt.Sprintf("expectation %d", len(s.expectations)),
+		status: http.StatusOK,
+		body:   []byte(`{"data": null}`),
+	}
+	s.expectations = append(s.expectations, &e)
+
+	return &e
+}
+
+// ExpectOperation registers an expectation for requests of the named
+// operation.
+func (s *Server) ExpectOperation(name string) *Expectation {
+	e := s.Expect(OperationName(name))
+	e.desc = fmt.Sprintf("operation %q", name)
+	return e
+}
+
+// Requests returns the requests the server has received.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// AssertExpectations fails the test if any expectation didn't receive a
+// request.
+func (s *Server) AssertExpectations() {
+	s.t.Helper()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.expectations {
+		if e.calls == 0 {
+			s.t.Errorf("graphqltest: %s was never called", e.desc)
+		}
+	}
+}
+
+// handle processes requests against the registered expectations.
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		s.t.Errorf("graphqltest: reading request: %v", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req Request
+	if err := json.Unmarshal(b, &req); err != nil {
+		s.t.Errorf("graphqltest: decoding request: %v: %s", err, string(b))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if req.OperationName == "" {
+		req.OperationName = operationName(req.Query)
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	e := s.find(req)
+	s.mu.Unlock()
+
+	if e == nil {
+		s.t.Errorf("graphqltest: unexpected request: %s", req.Query)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"errors": [{"message": "graphqltest: unexpected request"}]}`)
+		return
+	}
+
+	if e.variables != nil {
+		if diff := cmp.Diff(normalize(e.variables), req.Variables); diff != "" {
+			s.t.Errorf("graphqltest: %s received unexpected variables. Diff:\n%s", e.desc, diff)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(e.status)
+	w.Write(e.body)
+}
+
+// find returns the first expectation that matches the request and still
+// accepts calls. The caller must hold the lock.
+func (s *Server) find(req Request) *Expectation {
+	for _, e := range s.expectations {
+		if e.times > 0 && e.calls >= e.times {
+			continue
+		}
+		if e.match(req) {
+			e.calls++
+			return e
+		}
+	}
+	return nil
+}
+
+// normalize converts the variables through JSON so they can be compared
+// with the variables decoded from a request.
+func normalize(variables map[string]interface{}) map[string]interface{} {
+	b, err := json.Marshal(variables)
+	if err != nil {
+		return variables
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return variables
+	}
+
+	return m
+}
+
+// opName matches the name of a named operation.
+var opName = regexp.MustCompile(`^\s*(?:query|mutation|subscription)\s+([_A-Za-z][_0-9A-Za-z]*)`)
+
+// operationName extracts the name of the operation from the query, if the
+// operation is named.
+func operationName(query string) string {
+	m := opName.FindStringSubmatch(query)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
@@ -0,0 +1,73 @@
+package graphqltest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/ardanlabs/graphql/graphqltest"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+func TestServer(t *testing.T) {
+	type city struct {
+		Name string `json:"name"`
+	}
+
+	type response struct {
+		GetCity city `json:"getCity"`
+	}
+
+	t.Log("Given the need to mock a graphql server.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen expecting a named operation.", testID)
+		{
+			server := graphqltest.NewServer(t)
+			server.ExpectOperation("GetCity").
+				WithVariables(map[string]interface{}{"id": "0x01"}).
+				Respond(response{GetCity: city{Name: "Miami"}})
+
+			gql := graphql.New(server.URL)
+
+			var got response
+			err := gql.Execute(context.Background(), `query GetCity($id: ID!) { getCity(id: $id) { name } }`, &got,
+				graphql.WithVariable("id", "0x01"),
+			)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			if got.GetCity.Name != "Miami" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the fixture: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the fixture.", success, testID)
+
+			if reqs := server.Requests(); len(reqs) != 1 || reqs[0].OperationName != "GetCity" {
+				t.Fatalf("\t%s\tTest %d:\tShould record the request: %+v", failed, testID, reqs)
+			}
+			t.Logf("\t%s\tTest %d:\tShould record the request.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen expecting a query that fails.", testID)
+		{
+			server := graphqltest.NewServer(t)
+			server.Expect(graphqltest.QueryContains("getCity")).RespondError("no nodes found")
+
+			gql := graphql.New(server.URL)
+
+			var got response
+			if err := gql.Execute(context.Background(), `query { getCity(id: "0x02") { name } }`, &got); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get the error.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the error.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package graphqltest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Mode determines if a Recorder records live traffic or replays recorded
+// traffic.
+type Mode int
+
+// Set of modes a Recorder can operate in.
+const (
+	// ModeReplay serves responses from the golden files and fails any
+	// request that wasn't recorded.
+	ModeReplay Mode = iota
+
+	// ModeRecord sends requests to the host and writes each request and
+	// response pair to a golden file.
+	ModeRecord
+)
+
+// Interaction represents a recorded request and response pair.
+type Interaction struct {
+	Request struct {
+		Method string `json:"method"`
+		URL    string `json:"url"`
+		Body   string `json:"body"`
+	} `json:"request"`
+	Response struct {
+		StatusCode int         `json:"status_code"`
+		Header     http.Header `json:"header"`
+		Body       string      `json:"body"`
+	} `json:"response"`
+}
+
+// Recorder is an http.RoundTripper that records traffic to golden files and
+// replays it deterministically. Use it with graphql.WithClient so tests can
+// run against recorded traffic without a live host.
+type Recorder struct {
+	mode Mode
+	dir  string
+	next http.RoundTripper
+
+	mu  sync.Mutex
+	seq map[string]int
+}
+
+// NewRecorder constructs a Recorder that reads and writes golden files in
+// the specified directory. In ModeRecord requests are sent using the next
+// round tripper, or http.DefaultTransport if next is nil.
+func NewRecorder(mode Mode, dir string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Recorder{
+		mode: mode,
+		dir:  dir,
+		next: next,
+		seq:  make(map[string]int),
+	}
+}
+
+// Client returns an http.Client that uses the recorder as its transport.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, fmt.Errorf("graphqltest: reading request: %w", err)
+		}
+		req.Body.Close()
+	}
+
+	file := r.file(req, body)
+
+	if r.mode == ModeReplay {
+		return r.replay(req, file)
+	}
+
+	return r.record(req, body, file)
+}
+
+// file returns the golden file for the request. The file name is derived
+// from the request and the number of times the same request was seen, so
+// repeated requests are replayed in the order they were recorded.
+func (r *Recorder) file(req *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method))
+	h.Write([]byte(req.URL.RequestURI()))
+	h.Write(body)
+	key := hex.EncodeToString(h.Sum(nil))[:16]
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.seq[key]++
+	return filepath.Join(r.dir, fmt.Sprintf("%s_%d.json", key, r.seq[key]))
+}
+
+// replay serves the response recorded in the golden file.
+func (r *Recorder) replay(req *http.Request, file string) (*http.Response, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("graphqltest: no recording for %s %s: %w", req.Method, req.URL.RequestURI(), err)
+	}
+
+	var it Interaction
+	if err := json.Unmarshal(data, &it); err != nil {
+		return nil, fmt.Errorf("graphqltest: decoding recording %s: %w", file, err)
+	}
+
+	resp := http.Response{
+		Status:        fmt.Sprintf("%d %s", it.Response.StatusCode, http.StatusText(it.Response.StatusCode)),
+		StatusCode:    it.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        it.Response.Header,
+		Body:          ioutil.NopCloser(bytes.NewReader([]byte(it.Response.Body))),
+		ContentLength: int64(len(it.Response.Body)),
+		Request:       req,
+	}
+	if resp.Header == nil {
+		resp.Header = make(http.Header)
+	}
+
+	return &resp, nil
+}
+
+// record sends the request to the host and writes the interaction to the
+// golden file. A round tripper must not modify the request, so a copy with
+// the body restored is sent.
+func (r *Recorder) record(req *http.Request, body []byte, file string) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("graphqltest: reading response: %w", err)
+	}
+
+	var it Interaction
+	it.Request.Method = req.Method
+	it.Request.URL = req.URL.RequestURI()
+	it.Request.Body = string(body)
+	it.Response.StatusCode = resp.StatusCode
+	it.Response.Header = resp.Header
+	it.Response.Body = string(respBody)
+
+	data, err := json.MarshalIndent(it, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("graphqltest: encoding recording: %w", err)
+	}
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return nil, fmt.Errorf("graphqltest: creating recording directory: %w", err)
+	}
+
+	if err := ioutil.WriteFile(file, data, 0644); err != nil {
+		return nil, fmt.Errorf("graphqltest: writing recording: %w", err)
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
@@ -0,0 +1,86 @@
+package graphqltest_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/ardanlabs/graphql/graphqltest"
+)
+
+func TestRecorder(t *testing.T) {
+	type response struct {
+		Name string `json:"name"`
+	}
+
+	dir, err := ioutil.TempDir("", "recorder")
+	if err != nil {
+		t.Fatalf("creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	query := `query { name }`
+
+	t.Log("Given the need to record and replay traffic.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen recording traffic from a live host.", testID)
+		{
+			server := graphqltest.NewServer(t)
+			server.Expect(graphqltest.Any()).Respond(response{Name: "recorded"})
+
+			rec := graphqltest.NewRecorder(graphqltest.ModeRecord, dir, nil)
+			gql := graphql.New(server.URL, graphql.WithClient(rec.Client()))
+
+			var got response
+			if err := gql.Execute(context.Background(), query, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			body := ioutil.NopCloser(strings.NewReader(`{"query": "query { name }"}`))
+			req, err := http.NewRequest(http.MethodPost, server.URL+"/graphql", body)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to create the request: %v", failed, testID, err)
+			}
+
+			resp, err := rec.RoundTrip(req)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to send the request: %v", failed, testID, err)
+			}
+			resp.Body.Close()
+
+			if req.Body != body {
+				t.Fatalf("\t%s\tTest %d:\tShould leave the request unchanged.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould leave the request unchanged.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen replaying the recorded traffic.", testID)
+		{
+			rec := graphqltest.NewRecorder(graphqltest.ModeReplay, dir, nil)
+			gql := graphql.New("http://recorded.invalid", graphql.WithClient(rec.Client()))
+
+			var got response
+			if err := gql.Execute(context.Background(), query, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to replay the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to replay the query.", success, testID)
+
+			if got.Name != "recorded" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the recorded result: %q", failed, testID, got.Name)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the recorded result.", success, testID)
+
+			if err := gql.Execute(context.Background(), `query { other }`, &got); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould fail a query that wasn't recorded.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould fail a query that wasn't recorded.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestHandlerTransport(t *testing.T) {
+	t.Log("Given the need to execute requests against an in-process handler.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen executing a query.", testID)
+		{
+			h := func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/graphql" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				var req struct {
+					Variables map[string]string
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+
+				fmt.Fprintf(w, `{"data": {"getCity": {"name": %q}}}`, req.Variables["name"])
+			}
+
+			gql := graphql.New("http://in-process", graphql.WithTransport(graphql.NewHandlerTransport(http.HandlerFunc(h))))
+
+			var response struct {
+				GetCity struct {
+					Name string `json:"name"`
+				} `json:"getCity"`
+			}
+			err := gql.Execute(context.Background(), `query($name: String!) { getCity(name: $name) { name } }`, &response,
+				graphql.WithVariable("name", "Miami"),
+			)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			if response.GetCity.Name != "Miami" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the handler's response: %+v", failed, testID, response)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the handler's response.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the handler fails the request.", testID)
+		{
+			h := func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+
+			gql := graphql.New("http://in-process", graphql.WithTransport(graphql.NewHandlerTransport(http.HandlerFunc(h))))
+
+			err := gql.Execute(context.Background(), `query { __typename }`, nil)
+
+			var httpErr *graphql.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusServiceUnavailable {
+				t.Fatalf("\t%s\tTest %d:\tShould get the status code: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the status code.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package graphql
+
+// Error codes Hasura reports in the extensions of a graphql error.
+const (
+	CodeHasuraValidationFailed    = "validation-failed"
+	CodeHasuraConstraintViolation = "constraint-violation"
+	CodeHasuraPermissionError     = "permission-error"
+	CodeHasuraAccessDenied        = "access-denied"
+	CodeHasuraInvalidJWT          = "invalid-jwt"
+	CodeHasuraDataException       = "data-exception"
+)
+
+// WithHasuraAdminSecret sets the admin secret of a Hasura host, which is
+// sent in the x-hasura-admin-secret header on every request.
+func WithHasuraAdminSecret(secret string) func(gql *GraphQL) {
+	return WithHeader("x-hasura-admin-secret", secret)
+}
+
+// WithHasuraRole sets the role requests are executed as on a Hasura host,
+// which is sent in the x-hasura-role header on every request.
+func WithHasuraRole(role string) func(gql *GraphQL) {
+	return WithHeader("x-hasura-role", role)
+}
+
+// ExtensionsPath returns the path provided in the extensions of the error.
+// Hasura reports the JSON path of the part of the request that failed
+// there, like $.selectionSet.queryCity.
+func (e ResponseError) ExtensionsPath() string {
+	path, _ := e.Extensions["path"].(string)
+	return path
+}
@@ -0,0 +1,50 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestHasura(t *testing.T) {
+	t.Log("Given the need to execute requests against a Hasura host.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen Hasura rejects the query.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-Hasura-Admin-Secret") != "secret" || r.Header.Get("X-Hasura-Role") != "editor" {
+					t.Fatalf("\t%s\tTest %d:\tShould send the Hasura headers: %v", failed, testID, r.Header)
+				}
+				t.Logf("\t%s\tTest %d:\tShould send the Hasura headers.", success, testID)
+
+				io.WriteString(w, `{"errors": [{"extensions": {"path": "$.selectionSet.city", "code": "validation-failed"}, "message": "field 'city' not found in type: 'query_root'"}]}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL,
+				graphql.WithHasuraAdminSecret("secret"),
+				graphql.WithHasuraRole("editor"),
+			)
+
+			err := gql.Execute(context.Background(), `query { city { name } }`, nil)
+
+			var gqlErr *graphql.GraphQLError
+			if !errors.As(err, &gqlErr) {
+				t.Fatalf("\t%s\tTest %d:\tShould get a graphql error: %v", failed, testID, err)
+			}
+
+			if gqlErr.Code() != graphql.CodeHasuraValidationFailed || gqlErr.Errors[0].ExtensionsPath() != "$.selectionSet.city" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the code and path: %+v", failed, testID, gqlErr.Errors)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the code and path.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// HeaderProvider provides headers for requests, like short-lived tokens
+// issued by a secrets manager. Headers returns the headers and how long
+// they can be reused, where a ttl of 0 asks for the provider to be called
+// for every request. Implementations must be safe for concurrent use.
+type HeaderProvider interface {
+	Headers(ctx context.Context) (headers map[string]string, ttl time.Duration, err error)
+}
+
+// WithHeaderProvider adds a provider of headers for every request. The
+// headers are kept for the ttl the provider returns and are fetched again
+// once it expires, or sooner if the host responds with 401 Unauthorized.
+// Requests fail when the provider does. The headers take precedence over
+// the ones provided with WithHeader and WithContextHeaders.
+func WithHeaderProvider(provider HeaderProvider) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		if provider != nil {
+			gql.headerSources = append(gql.headerSources, &headerSource{provider: provider})
+		}
+	}
+}
+
+// setProvidedHeaders adds the headers of the providers to the request.
+func (g *GraphQL) setProvidedHeaders(ctx context.Context, header http.Header) error {
+	for _, hs := range g.headerSources {
+		headers, err := hs.headers(ctx)
+		if err != nil {
+			return err
+		}
+		for key, value := range headers {
+			if key != "" {
+				header.Set(key, value)
+			}
+		}
+	}
+	return nil
+}
+
+// expireProvidedHeaders drops the headers kept for the providers, so they
+// are fetched again for the next request.
+func (g *GraphQL) expireProvidedHeaders() {
+	for _, hs := range g.headerSources {
+		hs.expire()
+	}
+}
+
+// =============================================================================
+
+// headerSource keeps the headers of a provider until they expire.
+type headerSource struct {
+	provider HeaderProvider
+
+	mu      sync.Mutex
+	cached  map[string]string
+	expires time.Time
+}
+
+// headers returns the kept headers or fetches them from the provider.
+func (hs *headerSource) headers(ctx context.Context) (map[string]string, error) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	if hs.cached != nil && time.Now().Before(hs.expires) {
+		return hs.cached, nil
+	}
+
+	headers, ttl, err := hs.provider.Headers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("graphql header provider error: %w", err)
+	}
+
+	hs.cached = nil
+	if ttl > 0 {
+		hs.cached = headers
+		hs.expires = time.Now().Add(ttl)
+	}
+
+	return headers, nil
+}
+
+// expire drops the kept headers.
+func (hs *headerSource) expire() {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	hs.cached = nil
+}
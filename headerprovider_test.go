@@ -0,0 +1,99 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+// tokenProvider issues a new token every time it's called.
+type tokenProvider struct {
+	mu    sync.Mutex
+	calls int
+	ttl   time.Duration
+	err   error
+}
+
+func (tp *tokenProvider) Headers(ctx context.Context) (map[string]string, time.Duration, error) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	if tp.err != nil {
+		return nil, 0, tp.err
+	}
+
+	tp.calls++
+	return map[string]string{"Authorization": fmt.Sprintf("Bearer token-%d", tp.calls)}, tp.ttl, nil
+}
+
+func TestHeaderProvider(t *testing.T) {
+	t.Log("Given the need to add headers that expire to requests.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the provider returns headers with a ttl.", testID)
+		{
+			var mu sync.Mutex
+			var got []string
+			var revoked bool
+			f := func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				auth := r.Header.Get("Authorization")
+				got = append(got, auth)
+				if revoked && auth == "Bearer token-1" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				io.WriteString(w, `{"data": {}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			provider := tokenProvider{ttl: time.Minute}
+			gql := graphql.New(server.URL, graphql.WithHeader("Authorization", "static"), graphql.WithHeaderProvider(&provider))
+			ctx := context.Background()
+
+			for i := 0; i < 2; i++ {
+				if err := gql.Execute(ctx, `query { name }`, nil); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+			}
+			if fmt.Sprint(got) != "[Bearer token-1 Bearer token-1]" {
+				t.Fatalf("\t%s\tTest %d:\tShould reuse the headers within the ttl: %v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reuse the headers within the ttl.", success, testID)
+
+			mu.Lock()
+			revoked = true
+			got = nil
+			mu.Unlock()
+
+			if err := gql.Execute(ctx, `query { name }`, nil); !errors.Is(err, graphql.ErrUnauthorized) {
+				t.Fatalf("\t%s\tTest %d:\tShould get the revoked token rejected: %v", failed, testID, err)
+			}
+			if err := gql.Execute(ctx, `query { name }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if fmt.Sprint(got) != "[Bearer token-1 Bearer token-2]" {
+				t.Fatalf("\t%s\tTest %d:\tShould fetch the headers again after a 401: %v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould fetch the headers again after a 401.", success, testID)
+
+			gql = graphql.New(server.URL, graphql.WithHeaderProvider(&tokenProvider{err: errors.New("vault sealed")}))
+			if err := gql.Execute(ctx, `query { name }`, nil); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould fail when the provider fails.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould fail when the provider fails.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+type tenantKey struct{}
+
+func TestContextHeaders(t *testing.T) {
+	t.Log("Given the need to propagate request scoped values as headers.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the context holds a tenant id.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-Tenant-Id") != "acme" {
+					t.Fatalf("\t%s\tTest %d:\tShould get the tenant header: %q", failed, testID, r.Header.Get("X-Tenant-Id"))
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the tenant header.", success, testID)
+
+				io.WriteString(w, `{"data": {"__typename": "Query"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			tenant := func(ctx context.Context) map[string]string {
+				if id, ok := ctx.Value(tenantKey{}).(string); ok {
+					return map[string]string{"X-Tenant-Id": id}
+				}
+				return nil
+			}
+
+			gql := graphql.New(server.URL,
+				graphql.WithHeader("X-Tenant-Id", "default"),
+				graphql.WithContextHeaders(tenant),
+			)
+
+			ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+			if err := gql.Execute(ctx, `query { __typename }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+		}
+	}
+}
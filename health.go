@@ -0,0 +1,212 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// HealthState represents the health of the host as determined by the last
+// health probe.
+type HealthState int
+
+// Set of health states a host can be in.
+const (
+	HealthUnknown HealthState = iota
+	Healthy
+	Unhealthy
+)
+
+// String implements the fmt.Stringer interface.
+func (s HealthState) String() string {
+	switch s {
+	case Healthy:
+		return "healthy"
+	case Unhealthy:
+		return "unhealthy"
+	}
+	return "unknown"
+}
+
+// HealthProbe checks the health of the host the client is configured for.
+type HealthProbe func(ctx context.Context, gql *GraphQL) error
+
+// DgraphHealthProbe checks the health of the host using the Dgraph /health
+// endpoint. The host is healthy if every instance reports itself healthy.
+func DgraphHealthProbe(ctx context.Context, gql *GraphQL) error {
+	instances, err := gql.Health(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	for _, inst := range instances {
+		if inst.Status != "healthy" {
+			return fmt.Errorf("graphql health error: %s %s is %s", inst.Instance, inst.Address, inst.Status)
+		}
+	}
+
+	return nil
+}
+
+// IntrospectionProbe checks the health of the host by executing a minimal
+// introspection query. Use this for hosts that don't provide a /health
+// endpoint.
+func IntrospectionProbe(ctx context.Context, gql *GraphQL) error {
+	var response struct {
+		Typename string `json:"__typename"`
+	}
+	return gql.Execute(ctx, `query { __typename }`, &response)
+}
+
+// =============================================================================
+
+// HealthChecker periodically probes the health of a host in the background
+// and tracks the current state.
+type HealthChecker struct {
+	gql      *GraphQL
+	interval time.Duration
+	probe    HealthProbe
+	onChange func(state HealthState, err error)
+
+	mu        sync.RWMutex
+	state     HealthState
+	lastErr   error
+	lastCheck time.Time
+
+	startOnce  sync.Once
+	once       sync.Once
+	shutdown   chan struct{}
+	wg         sync.WaitGroup
+	unregister func()
+}
+
+// defaultHealthInterval is the interval used by a HealthChecker constructed
+// with an interval that isn't positive.
+const defaultHealthInterval = 10 * time.Second
+
+// NewHealthChecker constructs a HealthChecker that probes the host the
+// client is configured for on the specified interval. An interval of zero
+// or less uses the default of 10 seconds. By default the DgraphHealthProbe
+// is used. Call Start to begin probing.
+func NewHealthChecker(gql *GraphQL, interval time.Duration, options ...func(hc *HealthChecker)) *HealthChecker {
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+
+	hc := HealthChecker{
+		gql:      gql,
+		interval: interval,
+		probe:    DgraphHealthProbe,
+		shutdown: make(chan struct{}),
+	}
+
+	for _, option := range options {
+		option(&hc)
+	}
+
+	return &hc
+}
+
+// WithHealthProbe replaces the probe used to check the health of the host.
+func WithHealthProbe(probe HealthProbe) func(hc *HealthChecker) {
+	return func(hc *HealthChecker) {
+		hc.probe = probe
+	}
+}
+
+// WithHealthCallback adds a function that is called every time the health
+// state of the host changes.
+func WithHealthCallback(onChange func(state HealthState, err error)) func(hc *HealthChecker) {
+	return func(hc *HealthChecker) {
+		hc.onChange = onChange
+	}
+}
+
+// Start performs an initial probe and then continues to probe the host on
+// the configured interval until Stop is called or the client is closed.
+// It's safe to call Start more than once, only the first call starts the
+// probing.
+func (hc *HealthChecker) Start() {
+	hc.startOnce.Do(func() {
+		hc.unregister = hc.gql.closers.add(hc.stop)
+
+		hc.wg.Add(1)
+		go func() {
+			defer hc.wg.Done()
+
+			ticker := time.NewTicker(hc.interval)
+			defer ticker.Stop()
+
+			for {
+				hc.Check(context.Background())
+
+				select {
+				case <-ticker.C:
+				case <-hc.shutdown:
+					return
+				}
+			}
+		}()
+	})
+}
+
+// Stop stops the background probing and waits for any probe in progress
+// to complete. It's safe to call Stop more than once.
+func (hc *HealthChecker) Stop() {
+	if hc.unregister != nil {
+		hc.unregister()
+	}
+	hc.stop()
+}
+
+// stop signals the background probing to end and waits for it.
+func (hc *HealthChecker) stop() {
+	hc.once.Do(func() {
+		close(hc.shutdown)
+	})
+	hc.wg.Wait()
+}
+
+// Check probes the host immediately and returns the resulting state.
+func (hc *HealthChecker) Check(ctx context.Context) HealthState {
+	ctx, cancel := context.WithTimeout(ctx, hc.interval)
+	defer cancel()
+
+	err := hc.probe(ctx, hc.gql)
+
+	state := Healthy
+	if err != nil {
+		state = Unhealthy
+	}
+
+	hc.mu.Lock()
+	changed := hc.state != state
+	hc.state = state
+	hc.lastErr = err
+	hc.lastCheck = time.Now()
+	hc.mu.Unlock()
+
+	if changed && hc.onChange != nil {
+		hc.onChange(state, err)
+	}
+
+	return state
+}
+
+// State returns the current health state of the host, along with the error
+// from the last probe if the host is unhealthy.
+func (hc *HealthChecker) State() (HealthState, error) {
+	hc.mu.RLock()
+	defer hc.mu.RUnlock()
+
+	return hc.state, hc.lastErr
+}
+
+// LastCheck returns the time the last probe completed.
+func (hc *HealthChecker) LastCheck() time.Time {
+	hc.mu.RLock()
+	defer hc.mu.RUnlock()
+
+	return hc.lastCheck
+}
@@ -0,0 +1,111 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestHealthChecker(t *testing.T) {
+	t.Log("Given the need to track the health of a host.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the host goes from healthy to unhealthy.", testID)
+		{
+			var down int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodGet || r.URL.Path != "/health" {
+					t.Fatalf("\t%s\tTest %d:\tShould see a GET call to /health: %s %s", failed, testID, r.Method, r.URL.Path)
+				}
+				status := "healthy"
+				if atomic.LoadInt32(&down) == 1 {
+					status = "unhealthy"
+				}
+				io.WriteString(w, `[{"instance": "alpha", "address": "localhost:7080", "status": "`+status+`"}]`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			states := make(chan graphql.HealthState, 10)
+			hc := graphql.NewHealthChecker(graphql.New(server.URL), time.Hour,
+				graphql.WithHealthCallback(func(state graphql.HealthState, err error) {
+					states <- state
+				}),
+			)
+
+			hc.Start()
+			if state := <-states; state != graphql.Healthy {
+				t.Fatalf("\t%s\tTest %d:\tShould report the host is healthy: %v", failed, testID, state)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the host is healthy.", success, testID)
+			hc.Stop()
+
+			atomic.StoreInt32(&down, 1)
+			hc.Check(context.Background())
+
+			if state := <-states; state != graphql.Unhealthy {
+				t.Fatalf("\t%s\tTest %d:\tShould report the host is unhealthy: %v", failed, testID, state)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the host is unhealthy.", success, testID)
+
+			if state, err := hc.State(); state != graphql.Unhealthy || err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould retain the state and error: %v %v", failed, testID, state, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould retain the state and error.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the interval isn't positive.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `[{"instance": "alpha", "address": "localhost:7080", "status": "healthy"}]`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			states := make(chan graphql.HealthState, 10)
+			hc := graphql.NewHealthChecker(graphql.New(server.URL), 0,
+				graphql.WithHealthCallback(func(state graphql.HealthState, err error) {
+					states <- state
+				}),
+			)
+
+			hc.Start()
+			defer hc.Stop()
+
+			if state := <-states; state != graphql.Healthy {
+				t.Fatalf("\t%s\tTest %d:\tShould probe with the default interval: %v", failed, testID, state)
+			}
+			t.Logf("\t%s\tTest %d:\tShould probe with the default interval.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen Start is called more than once.", testID)
+		{
+			var probes int32
+			probe := func(ctx context.Context, gql *graphql.GraphQL) error {
+				atomic.AddInt32(&probes, 1)
+				return nil
+			}
+
+			hc := graphql.NewHealthChecker(graphql.New("http://localhost"), time.Hour, graphql.WithHealthProbe(probe))
+
+			hc.Start()
+			hc.Start()
+			hc.Stop()
+
+			if got := atomic.LoadInt32(&probes); got != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould probe from a single goroutine: %d", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould probe from a single goroutine.", success, testID)
+		}
+	}
+}
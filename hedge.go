@@ -0,0 +1,72 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// hedgedRequest executes the request and issues duplicate requests every
+// hedge delay until a response is received or the maximum number of hedges
+// is reached. The first successful response is decoded into the response
+// and all other in-flight requests are canceled.
+func (g *GraphQL) hedgedRequest(ctx context.Context, endpoint string, body []byte, response interface{}) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		data json.RawMessage
+		info ResponseInfo
+		err  error
+	}
+
+	// Every attempt captures its own response metadata so only the winner
+	// is provided to the caller.
+	callerInfo := callOpts(ctx).responseInfo
+
+	// The channel is buffered for every possible attempt so the attempts
+	// that lose the race can complete without blocking.
+	results := make(chan result, g.maxHedges+1)
+	attempt := func() {
+		var data json.RawMessage
+		var info ResponseInfo
+		err := g.RawRequest(CaptureResponse(ctx, &info), endpoint, bytes.NewReader(body), &data)
+		results <- result{data: data, info: info, err: err}
+	}
+
+	timer := time.NewTimer(g.hedgeDelay)
+	defer timer.Stop()
+
+	go attempt()
+	launched, inflight := 1, 1
+
+	var firstErr error
+	for {
+		select {
+		case <-timer.C:
+			if launched <= g.maxHedges {
+				go attempt()
+				launched++
+				inflight++
+				timer.Reset(g.hedgeDelay)
+			}
+
+		case r := <-results:
+			inflight--
+			if callerInfo != nil && (r.err == nil || firstErr == nil) {
+				*callerInfo = r.info
+			}
+			if r.err == nil {
+				return decodeData(r.data, response, g.decodeHooksFor(ctx), g.errorBodyLimit)
+			}
+
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			if inflight == 0 {
+				return firstErr
+			}
+		}
+	}
+}
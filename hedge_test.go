@@ -0,0 +1,118 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestHedging(t *testing.T) {
+	type response struct {
+		Name string `json:"name"`
+	}
+
+	t.Log("Given the need to hedge read-only queries against a stalled host.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the first request stalls.", testID)
+		{
+			var calls int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.Copy(ioutil.Discard, r.Body)
+				if atomic.AddInt32(&calls, 1) == 1 {
+					select {
+					case <-r.Context().Done():
+					case <-time.After(5 * time.Second):
+					}
+					return
+				}
+				io.WriteString(w, `{"data": {"name": "hedged"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithHedging(10*time.Millisecond, 2))
+
+			var got response
+			if err := gql.Execute(context.Background(), `query { name }`, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			if got.Name != "hedged" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the hedged result: %q", failed, testID, got.Name)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the hedged result.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen executing a mutation.", testID)
+		{
+			var calls int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(50 * time.Millisecond)
+				io.WriteString(w, `{"data": {"name": "mutated"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithHedging(time.Millisecond, 2))
+
+			var got response
+			if err := gql.Execute(context.Background(), `mutation { name }`, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the mutation: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the mutation.", success, testID)
+
+			if n := atomic.LoadInt32(&calls); n != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould send the mutation only once: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the mutation only once.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen executing a mutation that can't be parsed.", testID)
+		{
+			var calls int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(50 * time.Millisecond)
+				io.WriteString(w, `{"data": {"name": "mutated"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithHedging(time.Millisecond, 2), graphql.WithCache(graphql.NewMemoryCache(), time.Minute))
+			mutation := `mutation { name(note: "unterminated) }`
+
+			if op := graphql.ParseOperation(mutation); op.Type != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould not be able to parse the mutation: %+v", failed, testID, op)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not be able to parse the mutation.", success, testID)
+
+			for i := 0; i < 2; i++ {
+				var got response
+				if err := gql.Execute(context.Background(), mutation, &got); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the mutation: %v", failed, testID, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the mutation twice.", success, testID)
+
+			if n := atomic.LoadInt32(&calls); n != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould send the mutation once per call without caching: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the mutation once per call without caching.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// hostCooldown is the amount of time a host that failed is skipped before
+// it becomes a candidate again.
+const hostCooldown = 10 * time.Second
+
+// HostPolicy determines how requests are distributed across multiple hosts.
+type HostPolicy int
+
+// Set of policies for distributing requests across multiple hosts.
+const (
+	// RoundRobin distributes requests evenly across all healthy hosts.
+	RoundRobin HostPolicy = iota
+
+	// PriorityFailover sends requests to the first healthy host in the order
+	// the hosts were provided.
+	PriorityFailover
+)
+
+// HostStatus represents the health of a configured host.
+type HostStatus struct {
+	URL       string
+	Healthy   bool
+	Failures  int
+	LastError string
+}
+
+// WithHosts configures additional hosts to the url provided to New so a
+// client survives the outage of a single node. Requests are distributed
+// using the specified policy. When a host can't be reached, or responds with
+// a 502, 503, or 504 status code, it's marked unhealthy and the request is
+// sent to the next candidate host.
+func WithHosts(policy HostPolicy, urls ...string) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		p := hostPool{
+			policy: policy,
+			hosts:  []*host{{url: gql.url}},
+		}
+		for _, url := range urls {
+			p.hosts = append(p.hosts, &host{url: normalizeURL(url)})
+		}
+		gql.hosts = &p
+	}
+}
+
+// Hosts returns the current health of the configured hosts.
+func (g *GraphQL) Hosts() []HostStatus {
+	if g.hosts == nil {
+		return []HostStatus{{URL: g.url, Healthy: true}}
+	}
+
+	now := time.Now()
+	status := make([]HostStatus, len(g.hosts.hosts))
+	for i, h := range g.hosts.hosts {
+		status[i] = h.status(now)
+	}
+
+	return status
+}
+
+// =============================================================================
+
+// host represents a single host and tracks its health.
+type host struct {
+	url       string
+	mu        sync.Mutex
+	failures  int
+	downUntil time.Time
+	lastErr   string
+}
+
+// healthy reports if the host is a candidate for receiving requests.
+func (h *host) healthy(now time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return !now.Before(h.downUntil)
+}
+
+// markUp resets the health of the host after a successful request.
+func (h *host) markUp() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.failures = 0
+	h.downUntil = time.Time{}
+	h.lastErr = ""
+}
+
+// markDown records a failure and removes the host as a candidate until the
+// cooldown expires.
+func (h *host) markDown(reason string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.failures++
+	h.downUntil = time.Now().Add(hostCooldown)
+	h.lastErr = reason
+}
+
+// status returns a snapshot of the health of the host.
+func (h *host) status(now time.Time) HostStatus {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return HostStatus{
+		URL:       h.url,
+		Healthy:   !now.Before(h.downUntil),
+		Failures:  h.failures,
+		LastError: h.lastErr,
+	}
+}
+
+// =============================================================================
+
+// hostPool manages the set of hosts requests can be sent to.
+type hostPool struct {
+	policy HostPolicy
+	hosts  []*host
+	next   uint32
+}
+
+// candidates returns the hosts in the order they should be tried. Healthy
+// hosts are ordered by the policy and unhealthy hosts are tried last.
+func (p *hostPool) candidates() []*host {
+	start := 0
+	if p.policy == RoundRobin {
+		start = int(atomic.AddUint32(&p.next, 1)-1) % len(p.hosts)
+	}
+
+	now := time.Now()
+	healthy := make([]*host, 0, len(p.hosts))
+	var unhealthy []*host
+	for i := range p.hosts {
+		h := p.hosts[(start+i)%len(p.hosts)]
+		if h.healthy(now) {
+			healthy = append(healthy, h)
+			continue
+		}
+		unhealthy = append(unhealthy, h)
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+// send executes the request against the candidate hosts until a host
+// responds. The response from the last candidate is always returned.
+func (p *hostPool) send(ctx context.Context, g *GraphQL, endpoint string, r io.Reader) (*Response, error) {
+
+	// The request body must be buffered so it can be sent to more than
+	// one host.
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("graphql read request error: %w", err)
+	}
+
+	hosts := p.candidates()
+	for i, h := range hosts {
+		resp, err := g.sendTo(ctx, h.url, endpoint, bytes.NewReader(body))
+		last := i == len(hosts)-1
+
+		switch {
+		case err != nil:
+
+			// Only a host that couldn't be reached is marked down. Errors
+			// preparing the request, like a failed login or header
+			// provider, say nothing about the host.
+			if ctx.Err() != nil || !IsTransportError(err) {
+				return nil, err
+			}
+			h.markDown(err.Error())
+			if last {
+				return nil, err
+			}
+
+		case resp.StatusCode == http.StatusBadGateway ||
+			resp.StatusCode == http.StatusServiceUnavailable ||
+			resp.StatusCode == http.StatusGatewayTimeout:
+			h.markDown(resp.Status)
+			if last {
+				return resp, nil
+			}
+			resp.Body.Close()
+
+		default:
+			h.markUp()
+			return resp, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,116 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestHosts(t *testing.T) {
+	type response struct {
+		Name string `json:"name"`
+	}
+
+	t.Log("Given the need to execute queries against multiple hosts.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the primary host is down.", testID)
+		{
+			down := httptest.NewServer(http.NotFoundHandler())
+			down.Close()
+
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"data": {"name": "secondary"}}`)
+			}
+			up := httptest.NewServer(http.HandlerFunc(f))
+			defer up.Close()
+
+			gql := graphql.New(down.URL, graphql.WithHosts(graphql.PriorityFailover, up.URL))
+
+			var got response
+			if err := gql.Execute(context.Background(), `query { name }`, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			if got.Name != "secondary" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the result from the secondary host: %q", failed, testID, got.Name)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the result from the secondary host.", success, testID)
+
+			status := gql.Hosts()
+			if status[0].Healthy || status[0].Failures != 1 || !status[1].Healthy {
+				t.Fatalf("\t%s\tTest %d:\tShould track the health of each host: %+v", failed, testID, status)
+			}
+			t.Logf("\t%s\tTest %d:\tShould track the health of each host.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen distributing requests round robin.", testID)
+		{
+			counts := make([]int, 2)
+			handler := func(i int) http.HandlerFunc {
+				return func(w http.ResponseWriter, r *http.Request) {
+					counts[i]++
+					io.WriteString(w, `{"data": {"name": "ok"}}`)
+				}
+			}
+
+			s1 := httptest.NewServer(handler(0))
+			defer s1.Close()
+			s2 := httptest.NewServer(handler(1))
+			defer s2.Close()
+
+			gql := graphql.New(s1.URL, graphql.WithHosts(graphql.RoundRobin, s2.URL))
+
+			for i := 0; i < 4; i++ {
+				var got response
+				if err := gql.Execute(context.Background(), `query { name }`, &got); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the queries.", success, testID)
+
+			if counts[0] != 2 || counts[1] != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould distribute the requests evenly: %v", failed, testID, counts)
+			}
+			t.Logf("\t%s\tTest %d:\tShould distribute the requests evenly.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the request can't be prepared.", testID)
+		{
+			var calls int
+			f := func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				io.WriteString(w, `{"data": {"name": "ok"}}`)
+			}
+
+			s1 := httptest.NewServer(http.HandlerFunc(f))
+			defer s1.Close()
+			s2 := httptest.NewServer(http.HandlerFunc(f))
+			defer s2.Close()
+
+			provider := tokenProvider{err: errors.New("secrets manager unavailable")}
+			gql := graphql.New(s1.URL, graphql.WithHosts(graphql.PriorityFailover, s2.URL), graphql.WithHeaderProvider(&provider))
+
+			var got response
+			if err := gql.Execute(context.Background(), `query { name }`, &got); err == nil || calls != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould fail without sending the request: %d %v", failed, testID, calls, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould fail without sending the request.", success, testID)
+
+			for _, status := range gql.Hosts() {
+				if !status.Healthy || status.Failures != 0 {
+					t.Fatalf("\t%s\tTest %d:\tShould not mark the hosts down: %+v", failed, testID, gql.Hosts())
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould not mark the hosts down.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// introspectionQuery is the standard introspection query for retrieving the
+// full schema from a host.
+const introspectionQuery = `query IntrospectionQuery {
+	__schema {
+		queryType { name }
+		mutationType { name }
+		subscriptionType { name }
+		types { ...FullType }
+		directives {
+			name
+			description
+			locations
+			args { ...InputValue }
+		}
+	}
+}
+
+fragment FullType on __Type {
+	kind
+	name
+	description
+	fields(includeDeprecated: true) {
+		name
+		description
+		args { ...InputValue }
+		type { ...TypeRef }
+		isDeprecated
+		deprecationReason
+	}
+	inputFields { ...InputValue }
+	interfaces { ...TypeRef }
+	enumValues(includeDeprecated: true) {
+		name
+		description
+		isDeprecated
+		deprecationReason
+	}
+	possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+	name
+	description
+	type { ...TypeRef }
+	defaultValue
+}
+
+fragment TypeRef on __Type {
+	kind
+	name
+	ofType {
+		kind
+		name
+		ofType {
+			kind
+			name
+			ofType {
+				kind
+				name
+				ofType {
+					kind
+					name
+					ofType {
+						kind
+						name
+						ofType {
+							kind
+							name
+							ofType {
+								kind
+								name
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
+// IntrospectionSchema represents the schema of a host as returned by the
+// introspection query.
+type IntrospectionSchema struct {
+	QueryType        *IntrospectionTypeRef    `json:"queryType"`
+	MutationType     *IntrospectionTypeRef    `json:"mutationType"`
+	SubscriptionType *IntrospectionTypeRef    `json:"subscriptionType"`
+	Types            []IntrospectionType      `json:"types"`
+	Directives       []IntrospectionDirective `json:"directives"`
+}
+
+// IntrospectionType represents a named type in the schema.
+type IntrospectionType struct {
+	Kind          string                    `json:"kind"`
+	Name          string                    `json:"name"`
+	Description   string                    `json:"description"`
+	Fields        []IntrospectionField      `json:"fields"`
+	InputFields   []IntrospectionInputValue `json:"inputFields"`
+	Interfaces    []IntrospectionTypeRef    `json:"interfaces"`
+	EnumValues    []IntrospectionEnumValue  `json:"enumValues"`
+	PossibleTypes []IntrospectionTypeRef    `json:"possibleTypes"`
+}
+
+// IntrospectionField represents a field of an object or interface type.
+type IntrospectionField struct {
+	Name              string                    `json:"name"`
+	Description       string                    `json:"description"`
+	Args              []IntrospectionInputValue `json:"args"`
+	Type              IntrospectionTypeRef      `json:"type"`
+	IsDeprecated      bool                      `json:"isDeprecated"`
+	DeprecationReason string                    `json:"deprecationReason"`
+}
+
+// IntrospectionInputValue represents an argument or input field.
+type IntrospectionInputValue struct {
+	Name         string               `json:"name"`
+	Description  string               `json:"description"`
+	Type         IntrospectionTypeRef `json:"type"`
+	DefaultValue *string              `json:"defaultValue"`
+}
+
+// IntrospectionEnumValue represents a value of an enum type.
+type IntrospectionEnumValue struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	IsDeprecated      bool   `json:"isDeprecated"`
+	DeprecationReason string `json:"deprecationReason"`
+}
+
+// IntrospectionDirective represents a directive the schema supports.
+type IntrospectionDirective struct {
+	Name        string                    `json:"name"`
+	Description string                    `json:"description"`
+	Locations   []string                  `json:"locations"`
+	Args        []IntrospectionInputValue `json:"args"`
+}
+
+// IntrospectionTypeRef represents a reference to a type, possibly wrapped
+// in list and non-null modifiers.
+type IntrospectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *IntrospectionTypeRef `json:"ofType"`
+}
+
+// String returns the type reference in SDL notation, like [String!]!.
+func (r IntrospectionTypeRef) String() string {
+	switch r.Kind {
+	case "NON_NULL":
+		if r.OfType != nil {
+			return r.OfType.String() + "!"
+		}
+	case "LIST":
+		if r.OfType != nil {
+			return "[" + r.OfType.String() + "]"
+		}
+	}
+	return r.Name
+}
+
+// NamedType returns the name of the type with the list and non-null
+// modifiers removed.
+func (r IntrospectionTypeRef) NamedType() string {
+	if r.OfType != nil && (r.Kind == "NON_NULL" || r.Kind == "LIST") {
+		return r.OfType.NamedType()
+	}
+	return r.Name
+}
+
+// Type returns the named type from the schema.
+func (s *IntrospectionSchema) Type(name string) (IntrospectionType, bool) {
+	for _, t := range s.Types {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return IntrospectionType{}, false
+}
+
+// Field returns the named field of the type.
+func (t IntrospectionType) Field(name string) (IntrospectionField, bool) {
+	for _, f := range t.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return IntrospectionField{}, false
+}
+
+// Deprecation represents a field or enum value marked as deprecated.
+type Deprecation struct {
+	Type   string
+	Name   string
+	Reason string
+}
+
+// Deprecations returns every deprecated field and enum value in the schema.
+func (s *IntrospectionSchema) Deprecations() []Deprecation {
+	var deps []Deprecation
+	for _, t := range s.Types {
+		for _, f := range t.Fields {
+			if f.IsDeprecated {
+				deps = append(deps, Deprecation{Type: t.Name, Name: f.Name, Reason: f.DeprecationReason})
+			}
+		}
+		for _, v := range t.EnumValues {
+			if v.IsDeprecated {
+				deps = append(deps, Deprecation{Type: t.Name, Name: v.Name, Reason: v.DeprecationReason})
+			}
+		}
+	}
+	return deps
+}
+
+// Introspect retrieves the schema from the host using introspection.
+func (g *GraphQL) Introspect(ctx context.Context) (*IntrospectionSchema, error) {
+	return g.introspect(ctx)
+}
+
+// FetchSDL retrieves the schema from the host using introspection and
+// renders it in the schema definition language.
+func (g *GraphQL) FetchSDL(ctx context.Context) (string, error) {
+	schema, err := g.introspect(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return schema.SDL(), nil
+}
+
+// introspect executes the introspection query against the url/graphql
+// endpoint. The request is sent directly so it isn't subject to schema
+// validation.
+func (g *GraphQL) introspect(ctx context.Context) (*IntrospectionSchema, error) {
+	b, err := encodeQuery(introspectionQuery, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Schema IntrospectionSchema `json:"__schema"`
+	}
+	if err := g.RawRequest(ctx, g.graphqlPath, bytes.NewReader(b), &response); err != nil {
+		return nil, err
+	}
+
+	return &response.Schema, nil
+}
+
+// builtinTypes are provided by every schema and are excluded from the SDL.
+var builtinTypes = map[string]bool{
+	"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true,
+}
+
+// builtinDirectives are provided by every schema and are excluded from the
+// SDL.
+var builtinDirectives = map[string]bool{
+	"skip": true, "include": true, "deprecated": true, "specifiedBy": true, "defer": true,
+}
+
+// SDL renders the schema in the schema definition language. Built-in
+// scalars, directives, and introspection types are excluded.
+func (s *IntrospectionSchema) SDL() string {
+	var b strings.Builder
+
+	if s.QueryType != nil {
+		b.WriteString("schema {\n")
+		fmt.Fprintf(&b, "\tquery: %s\n", s.QueryType.Name)
+		if s.MutationType != nil {
+			fmt.Fprintf(&b, "\tmutation: %s\n", s.MutationType.Name)
+		}
+		if s.SubscriptionType != nil {
+			fmt.Fprintf(&b, "\tsubscription: %s\n", s.SubscriptionType.Name)
+		}
+		b.WriteString("}\n")
+	}
+
+	directives := append([]IntrospectionDirective(nil), s.Directives...)
+	sort.Slice(directives, func(i, j int) bool { return directives[i].Name < directives[j].Name })
+	for _, d := range directives {
+		if builtinDirectives[d.Name] {
+			continue
+		}
+		b.WriteString("\n")
+		writeDescription(&b, "", d.Description)
+		fmt.Fprintf(&b, "directive @%s%s on %s\n", d.Name, sdlArgs(d.Args), strings.Join(d.Locations, " | "))
+	}
+
+	types := append([]IntrospectionType(nil), s.Types...)
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+	for _, t := range types {
+		if builtinTypes[t.Name] || strings.HasPrefix(t.Name, "__") {
+			continue
+		}
+
+		b.WriteString("\n")
+		writeDescription(&b, "", t.Description)
+
+		switch t.Kind {
+		case "SCALAR":
+			fmt.Fprintf(&b, "scalar %s\n", t.Name)
+
+		case "OBJECT", "INTERFACE":
+			keyword := "type"
+			if t.Kind == "INTERFACE" {
+				keyword = "interface"
+			}
+			fmt.Fprintf(&b, "%s %s", keyword, t.Name)
+			if len(t.Interfaces) > 0 {
+				names := make([]string, len(t.Interfaces))
+				for i, intf := range t.Interfaces {
+					names[i] = intf.Name
+				}
+				fmt.Fprintf(&b, " implements %s", strings.Join(names, " & "))
+			}
+			b.WriteString(" {\n")
+			for _, f := range t.Fields {
+				writeDescription(&b, "\t", f.Description)
+				fmt.Fprintf(&b, "\t%s%s: %s%s\n", f.Name, sdlArgs(f.Args), f.Type, sdlDeprecated(f.IsDeprecated, f.DeprecationReason))
+			}
+			b.WriteString("}\n")
+
+		case "UNION":
+			names := make([]string, len(t.PossibleTypes))
+			for i, pt := range t.PossibleTypes {
+				names[i] = pt.Name
+			}
+			fmt.Fprintf(&b, "union %s = %s\n", t.Name, strings.Join(names, " | "))
+
+		case "ENUM":
+			fmt.Fprintf(&b, "enum %s {\n", t.Name)
+			for _, v := range t.EnumValues {
+				writeDescription(&b, "\t", v.Description)
+				fmt.Fprintf(&b, "\t%s%s\n", v.Name, sdlDeprecated(v.IsDeprecated, v.DeprecationReason))
+			}
+			b.WriteString("}\n")
+
+		case "INPUT_OBJECT":
+			fmt.Fprintf(&b, "input %s {\n", t.Name)
+			for _, f := range t.InputFields {
+				writeDescription(&b, "\t", f.Description)
+				fmt.Fprintf(&b, "\t%s\n", sdlInput(f))
+			}
+			b.WriteString("}\n")
+		}
+	}
+
+	return b.String()
+}
+
+// sdlArgs renders a list of arguments in SDL notation.
+func sdlArgs(args []IntrospectionInputValue) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	list := make([]string, len(args))
+	for i, arg := range args {
+		list[i] = sdlInput(arg)
+	}
+	return "(" + strings.Join(list, ", ") + ")"
+}
+
+// sdlInput renders an argument or input field in SDL notation.
+func sdlInput(in IntrospectionInputValue) string {
+	s := in.Name + ": " + in.Type.String()
+	if in.DefaultValue != nil {
+		s += " = " + *in.DefaultValue
+	}
+	return s
+}
+
+// sdlDeprecated renders the deprecated directive if required.
+func sdlDeprecated(deprecated bool, reason string) string {
+	if !deprecated {
+		return ""
+	}
+	if reason == "" {
+		return " @deprecated"
+	}
+	return " @deprecated(reason: " + quote(reason) + ")"
+}
+
+// writeDescription writes the description as a graphql string.
+func writeDescription(b *strings.Builder, indent string, description string) {
+	if description == "" {
+		return
+	}
+	fmt.Fprintf(b, "%s%s\n", indent, quote(description))
+}
+
+// quote produces a graphql string literal for the value.
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
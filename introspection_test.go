@@ -0,0 +1,85 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestIntrospection(t *testing.T) {
+	t.Log("Given the need to inspect the schema of a host.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen introspecting the schema.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, introspectionResponse)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			schema, err := gql.Introspect(context.Background())
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to introspect the schema: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to introspect the schema.", success, testID)
+
+			city, ok := schema.Type("City")
+			if !ok {
+				t.Fatalf("\t%s\tTest %d:\tShould find the City type.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould find the City type.", success, testID)
+
+			id, ok := city.Field("id")
+			if !ok || id.Type.String() != "ID!" || id.Type.NamedType() != "ID" {
+				t.Fatalf("\t%s\tTest %d:\tShould describe the id field: %+v", failed, testID, id)
+			}
+			t.Logf("\t%s\tTest %d:\tShould describe the id field.", success, testID)
+
+			deps := schema.Deprecations()
+			if len(deps) != 1 || deps[0].Name != "population" || deps[0].Reason != "use census" {
+				t.Fatalf("\t%s\tTest %d:\tShould find the deprecated field: %+v", failed, testID, deps)
+			}
+			t.Logf("\t%s\tTest %d:\tShould find the deprecated field.", success, testID)
+
+			sdl, err := gql.FetchSDL(context.Background())
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to fetch the SDL: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to fetch the SDL.", success, testID)
+
+			exp := `schema {
+	query: Query
+}
+
+directive @cascade(fields: [String]) on FIELD
+
+type City {
+	id: ID!
+	name: String
+	population: Int @deprecated(reason: "use census")
+}
+
+type Query {
+	getCity(id: ID!): City
+}
+`
+			if sdl != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould get the expected SDL:\n%s", failed, testID, sdl)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the expected SDL.", success, testID)
+
+			if _, err := graphql.LoadSchema(sdl); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to load the SDL: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to load the SDL.", success, testID)
+		}
+	}
+}
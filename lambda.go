@@ -0,0 +1,118 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// lambdaEndpoint is the endpoint of a Dgraph Lambda server that Dgraph
+// sends resolver requests to.
+const lambdaEndpoint = "graphql-worker"
+
+// LambdaAuthHeader represents the auth header Dgraph forwards to a lambda
+// resolver.
+type LambdaAuthHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// LambdaRequest represents the request Dgraph sends to a Lambda server to
+// resolve a @lambda field. Resolver is the field being resolved, like
+// Query.topCities or City.summary. Parents is set for fields on types and
+// Event is set for lambda webhooks.
+type LambdaRequest struct {
+	Resolver   string                 `json:"resolver"`
+	Args       map[string]interface{} `json:"args,omitempty"`
+	Parents    []interface{}          `json:"parents,omitempty"`
+	AuthHeader *LambdaAuthHeader      `json:"authHeader,omitempty"`
+	Event      json.RawMessage        `json:"event,omitempty"`
+}
+
+// InvokeLambda sends the request to the Lambda server at the configured url
+// the same way Dgraph does, and decodes the result into the response. This
+// allows resolvers to be tested without a Dgraph cluster. Construct the
+// client with the url of the Lambda server to use it.
+func (g *GraphQL) InvokeLambda(ctx context.Context, req LambdaRequest, response interface{}) error {
+	if g.closers.isClosed() {
+		return ErrClosed
+	}
+
+	b, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("graphql encoding error: %w", err)
+	}
+
+	resp, err := g.send(ctx, lambdaEndpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	buf := getBuffer()
+	defer putBuffer(buf)
+
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return fmt.Errorf("graphql copy error: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return g.classify(newHTTPError(resp))
+	}
+
+	if log := g.logger(ctx); log != nil {
+		if g.logLevelFor(ctx) == LogDebug {
+			log(fmt.Sprintf("lambda:[%s] data:[%s]", req.Resolver, excerpt(buf.Bytes(), g.errorBodyLimit)))
+		} else {
+			log(fmt.Sprintf("lambda:[%s] data:[%d bytes]", req.Resolver, buf.Len()))
+		}
+	}
+
+	return decodeData(buf.Bytes(), response, g.decodeHooksFor(ctx), g.errorBodyLimit)
+}
+
+// LambdaScript returns the lambda script stored in the cluster using the
+// url/admin endpoint.
+func (g *GraphQL) LambdaScript(ctx context.Context) (string, error) {
+	var response struct {
+		GetLambdaScript *struct {
+			Script string `json:"script"`
+		} `json:"getLambdaScript"`
+	}
+
+	const query = `query { getLambdaScript { script } }`
+	if err := g.ExecuteOnEndpoint(ctx, "admin", query, &response); err != nil {
+		return "", err
+	}
+
+	if response.GetLambdaScript == nil {
+		return "", nil
+	}
+
+	script, err := base64.StdEncoding.DecodeString(response.GetLambdaScript.Script)
+	if err != nil {
+		return "", fmt.Errorf("graphql decoding error: lambda script: %w", err)
+	}
+
+	return string(script), nil
+}
+
+// UpdateLambdaScript replaces the lambda script stored in the cluster using
+// the url/admin endpoint. The script is the JavaScript source that Dgraph
+// runs for @lambda fields.
+func (g *GraphQL) UpdateLambdaScript(ctx context.Context, script string) error {
+	var response struct {
+		UpdateLambdaScript struct {
+			LambdaScript struct {
+				Script string `json:"script"`
+			} `json:"lambdaScript"`
+		} `json:"updateLambdaScript"`
+	}
+
+	const mutation = `mutation($script: String!) { updateLambdaScript(input: {set: {script: $script}}) { lambdaScript { script } } }`
+	encoded := base64.StdEncoding.EncodeToString([]byte(script))
+	return g.ExecuteOnEndpoint(ctx, "admin", mutation, &response, WithVariable("script", encoded))
+}
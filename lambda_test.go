@@ -0,0 +1,100 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestLambda(t *testing.T) {
+	t.Log("Given the need to manage and test Dgraph Lambda resolvers.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen invoking a resolver on a Lambda server.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				var req graphql.LambdaRequest
+				json.NewDecoder(r.Body).Decode(&req)
+
+				if r.URL.Path != "/graphql-worker" || req.Resolver != "City.summary" || len(req.Parents) != 2 {
+					t.Fatalf("\t%s\tTest %d:\tShould send the resolver request: %s %+v", failed, testID, r.URL.Path, req)
+				}
+				t.Logf("\t%s\tTest %d:\tShould send the resolver request.", success, testID)
+
+				io.WriteString(w, `["Miami, FL", "Tampa, FL"]`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			req := graphql.LambdaRequest{
+				Resolver: "City.summary",
+				Parents: []interface{}{
+					map[string]interface{}{"name": "Miami"},
+					map[string]interface{}{"name": "Tampa"},
+				},
+			}
+
+			var got []string
+			if err := gql.InvokeLambda(context.Background(), req, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to invoke the resolver: %v", failed, testID, err)
+			}
+			if len(got) != 2 || got[1] != "Tampa, FL" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the result: %v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the result.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen updating and reading the lambda script.", testID)
+		{
+			const script = `addGraphQLResolvers({"Query.hello": () => "world"})`
+
+			var stored string
+			f := func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Variables map[string]string `json:"variables"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+
+				if r.URL.Path != "/admin" {
+					t.Fatalf("\t%s\tTest %d:\tShould use the admin endpoint: %s", failed, testID, r.URL.Path)
+				}
+
+				if s, ok := req.Variables["script"]; ok {
+					stored = s
+					io.WriteString(w, `{"data": {"updateLambdaScript": {"lambdaScript": {"script": "`+s+`"}}}}`)
+					return
+				}
+				io.WriteString(w, `{"data": {"getLambdaScript": {"script": "`+stored+`"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			if err := gql.UpdateLambdaScript(context.Background(), script); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to update the script: %v", failed, testID, err)
+			}
+			if stored != base64.StdEncoding.EncodeToString([]byte(script)) {
+				t.Fatalf("\t%s\tTest %d:\tShould send the script base64 encoded: %s", failed, testID, stored)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the script base64 encoded.", success, testID)
+
+			got, err := gql.LambdaScript(context.Background())
+			if err != nil || got != script {
+				t.Fatalf("\t%s\tTest %d:\tShould get the script back: %v %q", failed, testID, err, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the script back.", success, testID)
+		}
+	}
+}
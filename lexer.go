@@ -0,0 +1,197 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// tokenKind represents the kind of a lexical token in a graphql document.
+type tokenKind int
+
+// Set of token kinds produced by the lexer.
+const (
+	tokPunct tokenKind = iota + 1
+	tokName
+	tokNumber
+	tokString
+)
+
+// token represents a lexical token and its position in the source.
+type token struct {
+	kind  tokenKind
+	value string
+	start int
+	end   int
+}
+
+// lex splits the graphql document into tokens. Whitespace, commas, and
+// comments are ignored. This is a lightweight scanner and doesn't validate
+// the document is well formed beyond its lexical structure.
+func lex(src string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+
+		case c == '#':
+			for i < len(src) && src[i] != '\n' && src[i] != '\r' {
+				i++
+			}
+
+		case c == '.':
+			if !strings.HasPrefix(src[i:], "...") {
+				return nil, fmt.Errorf("graphql syntax error: unexpected '.' at offset %d", i)
+			}
+			tokens = append(tokens, token{kind: tokPunct, value: "...", start: i, end: i + 3})
+			i += 3
+
+		case strings.IndexByte("!$&()|:=@[]{}", c) >= 0:
+			tokens = append(tokens, token{kind: tokPunct, value: src[i : i+1], start: i, end: i + 1})
+			i++
+
+		case c == '_' || isLetter(c):
+			start := i
+			for i < len(src) && (src[i] == '_' || isLetter(src[i]) || isDigit(src[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokName, value: src[start:i], start: start, end: i})
+
+		case c == '-' || isDigit(c):
+			start := i
+			i++
+			for i < len(src) && (isDigit(src[i]) || isLetter(src[i]) || src[i] == '.' || src[i] == '+' || src[i] == '-') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokNumber, value: src[start:i], start: start, end: i})
+
+		case c == '"':
+			start := i
+			end, err := scanString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{kind: tokString, value: src[start:end], start: start, end: end})
+
+		default:
+			return nil, fmt.Errorf("graphql syntax error: unexpected character %q at offset %d", c, i)
+		}
+	}
+
+	return tokens, nil
+}
+
+// scanString returns the offset just past the string or block string that
+// starts at the specified offset.
+func scanString(src string, start int) (int, error) {
+	if strings.HasPrefix(src[start:], `"""`) {
+		for i := start + 3; i < len(src); i++ {
+			switch {
+			case strings.HasPrefix(src[i:], `\"""`):
+				i += 3
+			case strings.HasPrefix(src[i:], `"""`):
+				return i + 3, nil
+			}
+		}
+		return 0, fmt.Errorf("graphql syntax error: unterminated block string at offset %d", start)
+	}
+
+	for i := start + 1; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		case '\n', '\r':
+			return 0, fmt.Errorf("graphql syntax error: unterminated string at offset %d", start)
+		}
+	}
+	return 0, fmt.Errorf("graphql syntax error: unterminated string at offset %d", start)
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// =============================================================================
+
+// definition represents a top-level operation or fragment definition in a
+// graphql document.
+type definition struct {
+	kind    string
+	name    string
+	source  string
+	spreads []string
+}
+
+// parseDefinitions splits a graphql document into its top-level operation
+// and fragment definitions.
+func parseDefinitions(src string) ([]definition, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []definition
+	for i := 0; i < len(tokens); {
+		def := definition{kind: "query"}
+		start := tokens[i].start
+
+		switch t := tokens[i]; {
+		case t.kind == tokName && (t.value == "query" || t.value == "mutation" || t.value == "subscription" || t.value == "fragment"):
+			def.kind = t.value
+			if i+1 < len(tokens) && tokens[i+1].kind == tokName {
+				def.name = tokens[i+1].value
+			}
+
+		case t.kind == tokPunct && t.value == "{":
+
+		default:
+			return nil, fmt.Errorf("graphql syntax error: unexpected %q at offset %d", t.value, t.start)
+		}
+
+		var braces, parens int
+		end := -1
+		for ; i < len(tokens); i++ {
+			t := tokens[i]
+			if t.kind == tokPunct {
+				switch t.value {
+				case "{":
+					braces++
+				case "}":
+					braces--
+				case "(":
+					parens++
+				case ")":
+					parens--
+				case "...":
+					if i+1 < len(tokens) && tokens[i+1].kind == tokName && tokens[i+1].value != "on" {
+						def.spreads = append(def.spreads, tokens[i+1].value)
+					}
+				}
+			}
+			if t.value == "}" && braces == 0 && parens == 0 {
+				end = t.end
+				i++
+				break
+			}
+		}
+
+		if end < 0 {
+			return nil, fmt.Errorf("graphql syntax error: unterminated definition at offset %d", start)
+		}
+
+		def.source = src[start:end]
+		defs = append(defs, def)
+	}
+
+	return defs, nil
+}
@@ -0,0 +1,59 @@
+package graphql
+
+import (
+	"fmt"
+)
+
+// QueryLimitError is returned when a document exceeds the limits set with
+// WithQueryLimits. Limit is depth or fields.
+type QueryLimitError struct {
+	Limit string
+	Value int
+	Max   int
+}
+
+// Error implements the error interface.
+func (e *QueryLimitError) Error() string {
+	return fmt.Sprintf("graphql limit error: %s %d exceeds the limit of %d", e.Limit, e.Value, e.Max)
+}
+
+// queryLimits represents the limits on the shape of a document.
+type queryLimits struct {
+	maxDepth  int
+	maxFields int
+}
+
+// WithQueryLimits rejects documents executed against the url/graphql
+// endpoint that nest fields deeper than maxDepth or select more than
+// maxFields fields in total, counting the fields of fragments, with a
+// *QueryLimitError. This is a safety net for documents built from user
+// input. A limit of zero isn't enforced.
+func WithQueryLimits(maxDepth int, maxFields int) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.limits = &queryLimits{
+			maxDepth:  maxDepth,
+			maxFields: maxFields,
+		}
+	}
+}
+
+// checkLimits enforces the query limits on the document if they are set.
+func (g *GraphQL) checkLimits(endpoint string, graphql string) error {
+	if g.limits == nil || endpoint != g.graphqlPath {
+		return nil
+	}
+
+	qc, err := EstimateCost(graphql, CostConfig{})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case g.limits.maxDepth > 0 && qc.Depth > g.limits.maxDepth:
+		return &QueryLimitError{Limit: "depth", Value: qc.Depth, Max: g.limits.maxDepth}
+	case g.limits.maxFields > 0 && qc.Fields > g.limits.maxFields:
+		return &QueryLimitError{Limit: "fields", Value: qc.Fields, Max: g.limits.maxFields}
+	}
+
+	return nil
+}
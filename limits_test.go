@@ -0,0 +1,105 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestQueryLimits(t *testing.T) {
+	t.Log("Given the need to limit the shape of documents built from user input.")
+	{
+		tt := []struct {
+			name     string
+			document string
+			limit    string
+		}{
+			{"too deep", `{ a { b { c { d } } } }`, "depth"},
+			{"too many fields", `{ a b c ...F } fragment F on Q { d e }`, "fields"},
+			{"within the limits", `{ a { b { c } } d }`, ""},
+		}
+
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen the document is %s.", testID, test.name)
+			{
+				gql := graphql.New("http://127.0.0.1:0", graphql.WithQueryLimits(3, 4))
+
+				err := gql.Execute(context.Background(), test.document, nil)
+
+				var limitErr *graphql.QueryLimitError
+				switch {
+				case test.limit == "" && errors.As(err, &limitErr):
+					t.Fatalf("\t%s\tTest %d:\tShould not be limited: %v", failed, testID, err)
+				case test.limit != "" && (!errors.As(err, &limitErr) || limitErr.Limit != test.limit):
+					t.Fatalf("\t%s\tTest %d:\tShould be limited by %s: %v", failed, testID, test.limit, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould enforce the limits.", success, testID)
+			}
+		}
+	}
+}
+
+func TestQueryLimitsNestedFragments(t *testing.T) {
+	const fragments = 40
+
+	// nested builds a document where every fragment spreads the next one
+	// twice, with the spreads produced by the spread function.
+	nested := func(spread func(next string) string) string {
+		var b strings.Builder
+		b.WriteString("{ ...F0 }")
+		for i := 0; i < fragments; i++ {
+			next := "x"
+			if i < fragments-1 {
+				next = fmt.Sprintf("...F%d", i+1)
+			}
+			fmt.Fprintf(&b, " fragment F%d on Q { f%d %s }", i, i, spread(next))
+		}
+		return b.String()
+	}
+
+	tt := []struct {
+		name     string
+		document string
+		limit    string
+	}{
+		{"spreading fragments twice in the same selection set", nested(func(next string) string { return next + " " + next }), ""},
+		{"spreading fragments under two fields", nested(func(next string) string { return "a { " + next + " } b { " + next + " }" }), "fields"},
+	}
+
+	t.Log("Given the need to limit documents with nested fragments quickly.")
+	{
+		for testID, test := range tt {
+			t.Logf("\tTest %d:\tWhen %s.", testID, test.name)
+			{
+				gql := graphql.New("http://127.0.0.1:0", graphql.WithQueryLimits(100, 1000))
+
+				done := make(chan error, 1)
+				go func() {
+					done <- gql.Execute(context.Background(), test.document, nil)
+				}()
+
+				var err error
+				select {
+				case err = <-done:
+				case <-time.After(5 * time.Second):
+					t.Fatalf("\t%s\tTest %d:\tShould check the limits quickly.", failed, testID)
+				}
+				t.Logf("\t%s\tTest %d:\tShould check the limits quickly.", success, testID)
+
+				var limitErr *graphql.QueryLimitError
+				switch {
+				case test.limit == "" && errors.As(err, &limitErr):
+					t.Fatalf("\t%s\tTest %d:\tShould not be limited: %v", failed, testID, err)
+				case test.limit != "" && (!errors.As(err, &limitErr) || limitErr.Limit != test.limit):
+					t.Fatalf("\t%s\tTest %d:\tShould be limited by %s: %v", failed, testID, test.limit, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould enforce the limits.", success, testID)
+			}
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LoaderConfig holds the settings for a loader.
+type LoaderConfig struct {
+	wait     time.Duration
+	maxBatch int
+	variable string
+}
+
+// WithLoaderWait sets how long a loader collects keys before it executes
+// the query. The default is 2ms.
+func WithLoaderWait(wait time.Duration) func(lc *LoaderConfig) {
+	return func(lc *LoaderConfig) {
+		if wait > 0 {
+			lc.wait = wait
+		}
+	}
+}
+
+// WithLoaderMaxBatch sets the number of keys that executes the query
+// without waiting for more. The default is 100.
+func WithLoaderMaxBatch(size int) func(lc *LoaderConfig) {
+	return func(lc *LoaderConfig) {
+		if size > 0 {
+			lc.maxBatch = size
+		}
+	}
+}
+
+// WithLoaderVariable sets the name of the variable the query uses for the
+// list of keys. The default is keys.
+func WithLoaderVariable(name string) func(lc *LoaderConfig) {
+	return func(lc *LoaderConfig) {
+		if name != "" {
+			lc.variable = name
+		}
+	}
+}
+
+// Loader collects the keys of individual Load calls made within a short
+// window and executes a single query against the url/graphql endpoint for
+// all of them, which is a common need when the client backs a resolver
+// layer. The query receives the distinct keys through the configured
+// variable and must select a single field holding the list of values. The
+// values are matched to the keys with the key function.
+type Loader[K comparable, V any] struct {
+	gql     *GraphQL
+	graphql string
+	keyOf   func(value V) K
+	cfg     LoaderConfig
+
+	mu    sync.Mutex
+	batch *loaderBatch[K, V]
+}
+
+// NewLoader constructs a loader that executes the query for each batch of
+// keys.
+func NewLoader[K comparable, V any](gql *GraphQL, graphql string, keyOf func(value V) K, options ...func(lc *LoaderConfig)) *Loader[K, V] {
+	lc := LoaderConfig{
+		wait:     2 * time.Millisecond,
+		maxBatch: 100,
+		variable: "keys",
+	}
+	for _, option := range options {
+		option(&lc)
+	}
+
+	return &Loader[K, V]{
+		gql:     gql,
+		graphql: graphql,
+		keyOf:   keyOf,
+		cfg:     lc,
+	}
+}
+
+// Load returns the value for the key once the batch it belongs to is
+// executed. The batch is executed with the values of the context of the
+// first call in it, like per-call options, but not its deadline or
+// cancellation, so a call that gives up doesn't fail the others. A call
+// whose context ends returns without waiting for the batch, which is
+// bounded by the timeout set with WithTimeout. If the query fails every
+// call in the batch gets the error, and a key without a value gets an
+// error that matches ErrNotFound.
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+
+	b := l.batch
+	if b == nil {
+		b = &loaderBatch[K, V]{
+			ctx:   detachedContext{ctx},
+			index: make(map[K]struct{}),
+			done:  make(chan struct{}),
+		}
+		l.batch = b
+		b.timer = time.AfterFunc(l.cfg.wait, func() { l.dispatch(b) })
+	}
+
+	if _, ok := b.index[key]; !ok {
+		b.index[key] = struct{}{}
+		b.keys = append(b.keys, key)
+	}
+
+	if len(b.keys) >= l.cfg.maxBatch {
+		l.batch = nil
+		if b.timer.Stop() {
+			go l.dispatch(b)
+		}
+	}
+
+	l.mu.Unlock()
+
+	select {
+	case <-b.done:
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+
+	if b.err != nil {
+		var zero V
+		return zero, b.err
+	}
+
+	value, ok := b.values[key]
+	if !ok {
+		var zero V
+		return zero, fmt.Errorf("graphql loader error: %w: no value for key %v", ErrNotFound, key)
+	}
+	return value, nil
+}
+
+// dispatch executes the query for the batch and delivers the results.
+func (l *Loader[K, V]) dispatch(b *loaderBatch[K, V]) {
+	l.mu.Lock()
+	if l.batch == b {
+		l.batch = nil
+	}
+	l.mu.Unlock()
+
+	defer close(b.done)
+
+	var response map[string][]V
+	if err := l.gql.Execute(b.ctx, l.graphql, &response, WithVariable(l.cfg.variable, b.keys)); err != nil {
+		b.err = err
+		return
+	}
+
+	if len(response) != 1 {
+		b.err = fmt.Errorf("graphql loader error: the query must select a single field, got %d", len(response))
+		return
+	}
+
+	b.values = make(map[K]V, len(b.keys))
+	for _, list := range response {
+		for _, value := range list {
+			b.values[l.keyOf(value)] = value
+		}
+	}
+}
+
+// =============================================================================
+
+// loaderBatch represents the keys collected for a single query and its
+// results, which are set before done is closed.
+type loaderBatch[K comparable, V any] struct {
+	ctx   context.Context
+	keys  []K
+	index map[K]struct{}
+	timer *time.Timer
+	done  chan struct{}
+
+	values map[K]V
+	err    error
+}
+
+// detachedContext carries the values of a context without its deadline or
+// cancellation.
+type detachedContext struct {
+	context.Context
+}
+
+// Deadline implements the context.Context interface.
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Done implements the context.Context interface.
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+// Err implements the context.Context interface.
+func (detachedContext) Err() error {
+	return nil
+}
@@ -0,0 +1,208 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoader(t *testing.T) {
+	t.Log("Given the need to batch individual loads into a single query.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen loads are made concurrently.", testID)
+		{
+			var mu sync.Mutex
+			var batches [][]string
+			f := func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Variables struct {
+						IDs []string `json:"ids"`
+					} `json:"variables"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+
+				mu.Lock()
+				batches = append(batches, req.Variables.IDs)
+				mu.Unlock()
+
+				var cities []string
+				for _, id := range req.Variables.IDs {
+					if id != "0x9" {
+						cities = append(cities, fmt.Sprintf(`{"id": %q, "name": "city-%s"}`, id, id))
+					}
+				}
+				io.WriteString(w, `{"data": {"queryCity": [`+strings.Join(cities, ",")+`]}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			type city struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			}
+			loader := graphql.NewLoader(gql, `query($ids: [ID!]) { queryCity(filter: {id: $ids}) { id name } }`,
+				func(c city) string { return c.ID },
+				graphql.WithLoaderVariable("ids"),
+				graphql.WithLoaderWait(50*time.Millisecond),
+				graphql.WithLoaderMaxBatch(10),
+			)
+
+			keys := []string{"0x1", "0x2", "0x1", "0x3", "0x9"}
+			got := make([]city, len(keys))
+			errs := make([]error, len(keys))
+
+			var wg sync.WaitGroup
+			wg.Add(len(keys))
+			for i, key := range keys {
+				go func(i int, key string) {
+					defer wg.Done()
+					got[i], errs[i] = loader.Load(context.Background(), key)
+				}(i, key)
+			}
+			wg.Wait()
+
+			for i, key := range keys[:4] {
+				if errs[i] != nil || got[i].Name != "city-"+key {
+					t.Fatalf("\t%s\tTest %d:\tShould load the value for %s: %+v %v", failed, testID, key, got[i], errs[i])
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould load the value for each key.", success, testID)
+
+			if !errors.Is(errs[4], graphql.ErrNotFound) {
+				t.Fatalf("\t%s\tTest %d:\tShould report a key without a value: %v", failed, testID, errs[4])
+			}
+			t.Logf("\t%s\tTest %d:\tShould report a key without a value.", success, testID)
+
+			if len(batches) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould execute a single query: %v", failed, testID, batches)
+			}
+			sort.Strings(batches[0])
+			if diff := cmp.Diff(batches[0], []string{"0x1", "0x2", "0x3", "0x9"}); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould query each distinct key once. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould query each distinct key once.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a batch is full.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"data": {"queryCity": [{"ID": "0x1"}, {"ID": "0x2"}]}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			loader := graphql.NewLoader(gql, `query($keys: [ID!]) { queryCity(filter: {id: $keys}) { ID: id } }`,
+				func(c struct{ ID string }) string { return c.ID },
+				graphql.WithLoaderWait(time.Hour),
+				graphql.WithLoaderMaxBatch(2),
+			)
+
+			errs := make(chan error, 2)
+			for _, key := range []string{"0x1", "0x2"} {
+				go func(key string) {
+					_, err := loader.Load(context.Background(), key)
+					errs <- err
+				}(key)
+			}
+
+			for i := 0; i < 2; i++ {
+				select {
+				case err := <-errs:
+					if err != nil {
+						t.Fatalf("\t%s\tTest %d:\tShould load the values: %v", failed, testID, err)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("\t%s\tTest %d:\tShould execute the query without waiting.", failed, testID)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould execute the query without waiting.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the first load of a batch is canceled.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"data": {"queryCity": [{"ID": "0x1"}, {"ID": "0x2"}]}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			loader := graphql.NewLoader(gql, `query($keys: [ID!]) { queryCity(filter: {id: $keys}) { ID: id } }`,
+				func(c struct{ ID string }) string { return c.ID },
+				graphql.WithLoaderWait(50*time.Millisecond),
+			)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			canceled := make(chan error, 1)
+			go func() {
+				_, err := loader.Load(ctx, "0x1")
+				canceled <- err
+			}()
+
+			time.Sleep(10 * time.Millisecond)
+			loaded := make(chan error, 1)
+			go func() {
+				_, err := loader.Load(context.Background(), "0x2")
+				loaded <- err
+			}()
+
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+
+			if err := <-canceled; !errors.Is(err, context.Canceled) {
+				t.Fatalf("\t%s\tTest %d:\tShould return when the context is canceled: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould return when the context is canceled.", success, testID)
+
+			if err := <-loaded; err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould load the values of the other calls: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould load the values of the other calls.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the query fails.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"errors": [{"message": "boom"}]}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			loader := graphql.NewLoader(gql, `query($keys: [ID!]) { queryCity(filter: {id: $keys}) { id } }`,
+				func(c struct{ ID string }) string { return c.ID },
+			)
+
+			if _, err := loader.Load(context.Background(), "0x1"); !graphql.IsGraphQLError(err) {
+				t.Fatalf("\t%s\tTest %d:\tShould get the error of the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the error of the query.", success, testID)
+		}
+	}
+}
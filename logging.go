@@ -0,0 +1,59 @@
+package graphql
+
+import "context"
+
+// Set of log levels.
+const (
+	LogDebug LogLevel = iota + 1
+	LogInfo
+)
+
+// LogLevel represents how much detail is logged. At LogDebug the request and
+// response bodies are logged, at LogInfo only their metadata is.
+type LogLevel int
+
+// WithLogLevel sets the level of the messages logged by the client. The
+// default is LogDebug.
+func WithLogLevel(level LogLevel) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.logLevel = level
+	}
+}
+
+// WithContextLogger returns a copy of the context that logs the messages of
+// the call made with it to the specified function instead of the function
+// provided with WithLogging, so a single request can be troubleshot.
+func WithContextLogger(ctx context.Context, logFunc func(s string)) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.logFunc = logFunc
+	})
+}
+
+// WithContextLogLevel returns a copy of the context that overrides the log
+// level for the call made with it, like enabling LogDebug to see the bodies
+// of a single request.
+func WithContextLogLevel(ctx context.Context, level LogLevel) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.logLevel = level
+	})
+}
+
+// logger returns the function that logs the messages of the call, or nil
+// if the messages aren't logged.
+func (g *GraphQL) logger(ctx context.Context) func(s string) {
+	if logFunc := callOpts(ctx).logFunc; logFunc != nil {
+		return logFunc
+	}
+	return g.logFunc
+}
+
+// logLevelFor returns the log level of the call.
+func (g *GraphQL) logLevelFor(ctx context.Context) LogLevel {
+	if level := callOpts(ctx).logLevel; level != 0 {
+		return level
+	}
+	if g.logLevel != 0 {
+		return g.logLevel
+	}
+	return LogDebug
+}
@@ -0,0 +1,66 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestLogging(t *testing.T) {
+	f := func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"data": {"name": "secret"}}`)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(f))
+	defer server.Close()
+
+	t.Log("Given the need to control what is logged per request.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen logging at the info level.", testID)
+		{
+			var logged []string
+			gql := graphql.New(server.URL,
+				graphql.WithLogging(func(s string) { logged = append(logged, s) }),
+				graphql.WithLogLevel(graphql.LogInfo),
+			)
+
+			if err := gql.Execute(context.Background(), `query { name }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if len(logged) != 1 || strings.Contains(logged[0], "secret") || !strings.Contains(logged[0], "data:[28 bytes]") {
+				t.Fatalf("\t%s\tTest %d:\tShould only log the metadata: %v", failed, testID, logged)
+			}
+			t.Logf("\t%s\tTest %d:\tShould only log the metadata.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a request is logged to its own logger.", testID)
+		{
+			var clientLogged, ctxLogged []string
+			gql := graphql.New(server.URL,
+				graphql.WithLogging(func(s string) { clientLogged = append(clientLogged, s) }),
+				graphql.WithLogLevel(graphql.LogInfo),
+			)
+
+			ctx := graphql.WithContextLogger(context.Background(), func(s string) { ctxLogged = append(ctxLogged, s) })
+			ctx = graphql.WithContextLogLevel(ctx, graphql.LogDebug)
+
+			if err := gql.Execute(ctx, `query { name }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if len(clientLogged) != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould not log to the client logger: %v", failed, testID, clientLogged)
+			}
+			if len(ctxLogged) != 1 || !strings.Contains(ctxLogged[0], `"name": "secret"`) {
+				t.Fatalf("\t%s\tTest %d:\tShould log the bodies to the context logger: %v", failed, testID, ctxLogged)
+			}
+			t.Logf("\t%s\tTest %d:\tShould log the bodies to the context logger.", success, testID)
+		}
+	}
+}
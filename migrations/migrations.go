@@ -0,0 +1,255 @@
+// Package migrations applies versioned GraphQL schema files to a Dgraph
+// host. Each file holds the complete schema for its version and is named
+// <version>_<name>.graphql, for example 0002_add_posts.graphql. Applied
+// versions are tracked as SchemaMigration nodes, one per version, so
+// migrations are only applied once and in order.
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+// ErrOutOfOrder is returned when a migration that hasn't been applied has
+// a lower version than a migration that has.
+var ErrOutOfOrder = errors.New("graphql migration error: migrations out of order")
+
+// Migration represents a single versioned schema file.
+type Migration struct {
+	Version int
+	Name    string
+	Schema  string
+}
+
+// Plan describes the migrations that will be applied. Changes and Diff
+// compare the deployed schema with the schema of the last pending
+// migration, Diff being a line diff of the two documents.
+type Plan struct {
+	Applied []int
+	Pending []Migration
+	Changes graphql.SchemaChanges
+	Diff    string
+}
+
+// Migrator applies migrations to a Dgraph host.
+type Migrator struct {
+	gql        *graphql.GraphQL
+	migrations []Migration
+}
+
+// New constructs a Migrator for the migration files found at the root of
+// the file system. The GraphQL value must be configured for the Dgraph
+// host so the /admin, /query and /mutate endpoints are available.
+func New(gql *graphql.GraphQL, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	m := Migrator{
+		gql:        gql,
+		migrations: migrations,
+	}
+
+	return &m, nil
+}
+
+// Load reads the migration files found at the root of the file system and
+// returns them sorted by version.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("graphql migration error: %w", err)
+	}
+
+	var migrations []Migration
+	versions := make(map[int]string)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".graphql" {
+			continue
+		}
+
+		base := strings.TrimSuffix(entry.Name(), ".graphql")
+		parts := strings.SplitN(base, "_", 2)
+		version, err := strconv.Atoi(parts[0])
+		if err != nil || version <= 0 {
+			return nil, fmt.Errorf("graphql migration error: invalid version in file name: %s", entry.Name())
+		}
+
+		if other, exists := versions[version]; exists {
+			return nil, fmt.Errorf("graphql migration error: duplicate version %d: %s, %s", version, other, entry.Name())
+		}
+		versions[version] = entry.Name()
+
+		schema, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("graphql migration error: %w", err)
+		}
+
+		var name string
+		if len(parts) == 2 {
+			name = parts[1]
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    name,
+			Schema:  string(schema),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Applied returns the versions that have been applied to the host.
+func (m *Migrator) Applied(ctx context.Context) ([]int, error) {
+	var response struct {
+		Migrations []struct {
+			Version int `json:"version"`
+		} `json:"migrations"`
+	}
+
+	query := `{ migrations(func: eq(dgraph.type, "SchemaMigration")) { version: migration.version } }`
+	if err := m.gql.QueryDQL(ctx, query, &response); err != nil {
+		return nil, err
+	}
+
+	var applied []int
+	for _, node := range response.Migrations {
+		applied = append(applied, node.Version)
+	}
+	sort.Ints(applied)
+
+	return applied, nil
+}
+
+// Plan determines the migrations that need to be applied without applying
+// them. This can be used as a dry run to review the schema changes.
+func (m *Migrator) Plan(ctx context.Context) (Plan, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	pending, err := m.pending(applied)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	plan := Plan{
+		Applied: applied,
+		Pending: pending,
+	}
+
+	if len(pending) > 0 {
+		deployed, err := m.gql.FetchDgraphSchema(ctx)
+		if err != nil {
+			return Plan{}, err
+		}
+
+		target := pending[len(pending)-1].Schema
+		changes, err := graphql.DiffSchemas(deployed, target)
+		if err != nil {
+			return Plan{}, err
+		}
+
+		plan.Changes = changes
+		plan.Diff = cmp.Diff(deployed, target)
+	}
+
+	return plan, nil
+}
+
+// Up applies the pending migrations in version order, recording each
+// version once its schema is accepted by the host. It returns the
+// migrations that were applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := m.pending(applied)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, migration := range pending {
+		if err := m.apply(ctx, migration); err != nil {
+			return pending[:i], fmt.Errorf("graphql migration error: version %d: %w", migration.Version, err)
+		}
+	}
+
+	return pending, nil
+}
+
+// pending returns the migrations that haven't been applied. Migrations
+// can't be applied out of order, so a pending migration with a lower
+// version than an applied one is an error. Applied versions without a
+// migration file mean the files are behind the host.
+func (m *Migrator) pending(applied []int) ([]Migration, error) {
+	known := make(map[int]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+
+	done := make(map[int]bool, len(applied))
+	var latest int
+	for _, version := range applied {
+		if !known[version] {
+			return nil, fmt.Errorf("graphql migration error: applied version %d has no migration file", version)
+		}
+		done[version] = true
+		if version > latest {
+			latest = version
+		}
+	}
+
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if done[migration.Version] {
+			continue
+		}
+		if migration.Version < latest {
+			return nil, fmt.Errorf("%w: version %d is lower than applied version %d", ErrOutOfOrder, migration.Version, latest)
+		}
+		pending = append(pending, migration)
+	}
+
+	return pending, nil
+}
+
+// apply updates the schema on the host and records the version. Every
+// version is recorded as a node of its own, since a predicate without a
+// list type in the DQL schema holds a single value and each version would
+// overwrite the last one.
+func (m *Migrator) apply(ctx context.Context, migration Migration) error {
+	const mutation = `mutation($schema: String!) { updateGQLSchema(input: {set: {schema: $schema}}) { gqlSchema { id } } }`
+	if err := m.gql.ExecuteOnEndpoint(ctx, "admin", mutation, nil, graphql.WithVariable("schema", migration.Schema)); err != nil {
+		return err
+	}
+
+	dm := graphql.DQLMutation{
+		SetNQuads: fmt.Sprintf("_:m <dgraph.type> \"SchemaMigration\" .\n_:m <migration.version> \"%d\"^^<xs:int> .", migration.Version),
+		CommitNow: true,
+	}
+
+	if _, err := m.gql.MutateDQL(ctx, dm); err != nil {
+		return err
+	}
+
+	return nil
+}
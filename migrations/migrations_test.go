@@ -0,0 +1,188 @@
+package migrations_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/ardanlabs/graphql/migrations"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+// dgraph is a fake Dgraph host that tracks the deployed schema and the
+// applied migration versions, one per SchemaMigration node. Like Dgraph,
+// a scalar predicate set on an existing node replaces its value.
+type dgraph struct {
+	mu       sync.Mutex
+	schema   string
+	versions []int
+}
+
+var versionRE = regexp.MustCompile(`(\S+) <migration.version> "(\d+)"`)
+
+func (d *dgraph) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	b, _ := ioutil.ReadAll(r.Body)
+
+	switch r.URL.Path {
+	case "/query":
+		nodes := make([]map[string]int, len(d.versions))
+		for i, version := range d.versions {
+			nodes[i] = map[string]int{"version": version}
+		}
+		data, _ := json.Marshal(nodes)
+		fmt.Fprintf(w, `{"data": {"migrations": %s}}`, data)
+
+	case "/admin":
+		var req struct {
+			Query     string
+			Variables map[string]string
+		}
+		json.Unmarshal(b, &req)
+
+		if strings.Contains(req.Query, "updateGQLSchema") {
+			d.schema = req.Variables["schema"]
+			fmt.Fprint(w, `{"data": {"updateGQLSchema": {"gqlSchema": {"id": "0x1"}}}}`)
+			return
+		}
+		data, _ := json.Marshal(d.schema)
+		fmt.Fprintf(w, `{"data": {"getGQLSchema": {"schema": %s}}}`, data)
+
+	case "/mutate":
+		m := versionRE.FindSubmatch(b)
+		version, _ := strconv.Atoi(string(m[2]))
+		if strings.HasPrefix(string(m[1]), "_:") || len(d.versions) == 0 {
+			d.versions = append(d.versions, version)
+		} else {
+			for i := range d.versions {
+				d.versions[i] = version
+			}
+		}
+		fmt.Fprint(w, `{"data": {"code": "Success"}}`)
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0001_init.graphql":      {Data: []byte("type User {\n\tname: String!\n}\n")},
+		"0002_add_email.graphql": {Data: []byte("type User {\n\tname: String!\n\temail: String\n}\n")},
+		"README.md":              {Data: []byte("ignored")},
+	}
+
+	t.Log("Given the need to migrate a Dgraph schema.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen applying the pending migrations.", testID)
+		{
+			host := dgraph{schema: "type User {\n\tname: String!\n}\n", versions: []int{1}}
+			server := httptest.NewServer(&host)
+			defer server.Close()
+
+			m, err := migrations.New(graphql.New(server.URL), fsys)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to load the migrations: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to load the migrations.", success, testID)
+
+			plan, err := m.Plan(context.Background())
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to plan the migrations: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to plan the migrations.", success, testID)
+
+			if len(plan.Pending) != 1 || plan.Pending[0].Version != 2 || plan.Pending[0].Name != "add_email" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the pending migration: %+v", failed, testID, plan.Pending)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the pending migration.", success, testID)
+
+			if !strings.Contains(plan.Diff, "email: String") {
+				t.Fatalf("\t%s\tTest %d:\tShould get the schema diff:\n%s", failed, testID, plan.Diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the schema diff.", success, testID)
+
+			if len(plan.Changes) != 1 || plan.Changes[0].Path != "User.email" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the schema changes: %v", failed, testID, plan.Changes)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the schema changes.", success, testID)
+
+			applied, err := m.Up(context.Background())
+			if err != nil || len(applied) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to apply the migrations: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to apply the migrations.", success, testID)
+
+			if host.schema != string(fsys["0002_add_email.graphql"].Data) || len(host.versions) != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould update the host: %v", failed, testID, host.versions)
+			}
+			t.Logf("\t%s\tTest %d:\tShould update the host.", success, testID)
+
+			versions, err := m.Applied(context.Background())
+			if err != nil || len(versions) != 2 || versions[0] != 1 || versions[1] != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould keep every applied version: %v, %v", failed, testID, versions, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep every applied version.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen applying several migrations to a new host.", testID)
+		{
+			var host dgraph
+			server := httptest.NewServer(&host)
+			defer server.Close()
+
+			m, err := migrations.New(graphql.New(server.URL), fsys)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to load the migrations: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to load the migrations.", success, testID)
+
+			if applied, err := m.Up(context.Background()); err != nil || len(applied) != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to apply the migrations: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to apply the migrations.", success, testID)
+
+			plan, err := m.Plan(context.Background())
+			if err != nil || len(plan.Applied) != 2 || len(plan.Pending) != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould have no pending migrations: %+v, %v", failed, testID, plan, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould have no pending migrations.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a migration is out of order.", testID)
+		{
+			host := dgraph{versions: []int{2}}
+			server := httptest.NewServer(&host)
+			defer server.Close()
+
+			m, err := migrations.New(graphql.New(server.URL), fsys)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to load the migrations: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to load the migrations.", success, testID)
+
+			if _, err := m.Up(context.Background()); !errors.Is(err, migrations.ErrOutOfOrder) {
+				t.Fatalf("\t%s\tTest %d:\tShould refuse to apply the migrations: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould refuse to apply the migrations.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package graphql
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithNamespace scopes the client to the specified Dgraph namespace. The
+// namespace is applied by logging in to it, so this must be used together
+// with WithLogin.
+func WithNamespace(namespace uint64) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		if gql.login == nil {
+			gql.login = &dgraphLogin{}
+		}
+		gql.login.namespace = namespace
+	}
+}
+
+// WithLogin logs in to a Dgraph host with ACLs enabled using the specified
+// credentials. The login happens on first use and the access token is sent
+// in the X-Dgraph-AccessToken header on every request. The login is
+// repeated when the access token expires.
+func WithLogin(userID string, password string) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		if gql.login == nil {
+			gql.login = &dgraphLogin{}
+		}
+		gql.login.userID = userID
+		gql.login.password = password
+	}
+}
+
+// Login performs the Dgraph login configured with WithLogin and
+// WithNamespace immediately, instead of waiting for the first request.
+func (g *GraphQL) Login(ctx context.Context) error {
+	if g.login == nil || g.login.userID == "" {
+		return fmt.Errorf("graphql login error: no credentials configured")
+	}
+
+	_, err := g.login.token(ctx, g, true)
+	return err
+}
+
+// AddNamespace creates a new namespace on a Dgraph host using the
+// url/admin endpoint and returns its id. The password is set for the
+// groot user of the namespace. The client must be logged in to the
+// galaxy namespace as a guardian.
+func (g *GraphQL) AddNamespace(ctx context.Context, password string) (uint64, error) {
+	var response struct {
+		AddNamespace struct {
+			NamespaceID uint64 `json:"namespaceId"`
+		} `json:"addNamespace"`
+	}
+
+	const mutation = `mutation($password: String) { addNamespace(input: {password: $password}) { namespaceId message } }`
+	if err := g.ExecuteOnEndpoint(ctx, "admin", mutation, &response, WithVariable("password", password)); err != nil {
+		return 0, err
+	}
+
+	return response.AddNamespace.NamespaceID, nil
+}
+
+// DeleteNamespace deletes the namespace and all of its data from a Dgraph
+// host using the url/admin endpoint. The client must be logged in to the
+// galaxy namespace as a guardian.
+func (g *GraphQL) DeleteNamespace(ctx context.Context, namespace uint64) error {
+	var response struct {
+		DeleteNamespace struct {
+			NamespaceID uint64 `json:"namespaceId"`
+		} `json:"deleteNamespace"`
+	}
+
+	const mutation = `mutation($namespace: Int!) { deleteNamespace(input: {namespaceId: $namespace}) { namespaceId message } }`
+	return g.ExecuteOnEndpoint(ctx, "admin", mutation, &response, WithVariable("namespace", namespace))
+}
+
+// =============================================================================
+
+// dgraphLogin holds the credentials and the access token for a Dgraph
+// login.
+type dgraphLogin struct {
+	namespace uint64
+	userID    string
+	password  string
+
+	mu        sync.Mutex
+	accessJWT string
+	expires   time.Time
+}
+
+// token returns the access token, logging in if there is no token, the
+// token has expired, or a login is forced.
+func (l *dgraphLogin) token(ctx context.Context, g *GraphQL, force bool) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !force && l.accessJWT != "" && (l.expires.IsZero() || time.Now().Before(l.expires.Add(-time.Minute))) {
+		return l.accessJWT, nil
+	}
+
+	var response struct {
+		Login struct {
+			Response struct {
+				AccessJWT  string `json:"accessJWT"`
+				RefreshJWT string `json:"refreshJWT"`
+			} `json:"response"`
+		} `json:"login"`
+	}
+
+	const mutation = `mutation($userId: String, $password: String, $namespace: Int) { login(userId: $userId, password: $password, namespace: $namespace) { response { accessJWT refreshJWT } } }`
+
+	ctx = withCallOption(ctx, func(opts *callOptions) {
+		opts.noLogin = true
+	})
+
+	err := g.ExecuteOnEndpoint(ctx, "admin", mutation, &response,
+		WithVariable("userId", l.userID),
+		WithVariable("password", l.password),
+		WithVariable("namespace", l.namespace),
+	)
+	if err != nil {
+		return "", fmt.Errorf("graphql login error: %w", err)
+	}
+
+	l.accessJWT = response.Login.Response.AccessJWT
+	l.expires = jwtExpiry(l.accessJWT)
+
+	return l.accessJWT, nil
+}
+
+// jwtExpiry returns the expiry of the JWT from its exp claim, or the zero
+// time if it can't be determined.
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(claims.Exp, 0)
+}
@@ -0,0 +1,88 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestNamespace(t *testing.T) {
+	t.Log("Given the need to work with a Dgraph namespace.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen logging in to a namespace.", testID)
+		{
+			var logins int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Variables map[string]interface{}
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+
+				if r.URL.Path == "/admin" {
+					atomic.AddInt32(&logins, 1)
+					if req.Variables["namespace"] != float64(2) || req.Variables["userId"] != "groot" {
+						t.Fatalf("\t%s\tTest %d:\tShould log in to the namespace: %v", failed, testID, req.Variables)
+					}
+					io.WriteString(w, `{"data": {"login": {"response": {"accessJWT": "token", "refreshJWT": "refresh"}}}}`)
+					return
+				}
+
+				if r.Header.Get("X-Dgraph-AccessToken") != "token" {
+					t.Fatalf("\t%s\tTest %d:\tShould send the access token.", failed, testID)
+				}
+				io.WriteString(w, `{"data": {"getUser": {"name": "Bill"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL,
+				graphql.WithNamespace(2),
+				graphql.WithLogin("groot", "password"),
+			)
+
+			for i := 0; i < 2; i++ {
+				var response struct {
+					GetUser struct {
+						Name string `json:"name"`
+					} `json:"getUser"`
+				}
+				if err := gql.Execute(context.Background(), `query { getUser(id: "0x1") { name } }`, &response); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the queries with the access token.", success, testID)
+
+			if n := atomic.LoadInt32(&logins); n != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould log in once: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould log in once.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen creating a namespace.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"data": {"addNamespace": {"namespaceId": 5, "message": "Created namespace successfully"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			id, err := gql.AddNamespace(context.Background(), "secret")
+			if err != nil || id != 5 {
+				t.Fatalf("\t%s\tTest %d:\tShould get the namespace id: %d %v", failed, testID, id, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the namespace id.", success, testID)
+		}
+	}
+}
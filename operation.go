@@ -0,0 +1,44 @@
+package graphql
+
+// Operation identifies the operation of a graphql document by its type and
+// name. Name is empty for anonymous operations.
+type Operation struct {
+	Type string
+	Name string
+}
+
+// String returns the operation in a form suitable for log messages and
+// metric labels, like "query GetCity".
+func (op Operation) String() string {
+	if op.Name == "" {
+		return op.Type
+	}
+	return op.Type + " " + op.Name
+}
+
+// readOnly reports whether the operation is a query that is safe to
+// execute more than once. A document that can't be scanned has no type and
+// might be a mutation, so it isn't read-only.
+func (op Operation) readOnly() bool {
+	return op.Type == "query"
+}
+
+// ParseOperation returns the type and name of the first operation in the
+// document. It's a cheap scan of the document rather than a full parse, so
+// the document isn't validated. A document that starts with a selection set
+// is an anonymous query, and the zero value is returned when no operation
+// is found or the document can't be scanned.
+func ParseOperation(document string) Operation {
+	defs, err := parseDefinitions(document)
+	if err != nil {
+		return Operation{}
+	}
+
+	for _, def := range defs {
+		if def.kind != "fragment" {
+			return Operation{Type: def.kind, Name: def.name}
+		}
+	}
+
+	return Operation{}
+}
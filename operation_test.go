@@ -0,0 +1,74 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestParseOperation(t *testing.T) {
+	tt := []struct {
+		name     string
+		document string
+		exp      graphql.Operation
+	}{
+		{"shorthand", `{ city { name } }`, graphql.Operation{Type: "query"}},
+		{"named", `query GetCity($id: ID!) { getCity(id: $id) { name } }`, graphql.Operation{Type: "query", Name: "GetCity"}},
+		{"anonymous", `mutation ($input: [AddCityInput!]!) { addCity(input: $input) { numUids } }`, graphql.Operation{Type: "mutation"}},
+		{"comments", "# query Wrong\n  subscription # comment\n OnCity { city { name } }", graphql.Operation{Type: "subscription", Name: "OnCity"}},
+		{"fragment", `fragment F on City @include(if: true) { name } query Cities { queryCity { ...F } }`, graphql.Operation{Type: "query", Name: "Cities"}},
+		{"strings", `query Right { x(a: "mutation Wrong {", b: """}""") }`, graphql.Operation{Type: "query", Name: "Right"}},
+		{"empty", ``, graphql.Operation{}},
+	}
+
+	t.Log("Given the need to identify the operation of a document.")
+	{
+		for testID, test := range tt {
+			tf := func(t *testing.T) {
+				t.Logf("\tTest %d:\tWhen parsing the %s document.", testID, test.name)
+				{
+					if got := graphql.ParseOperation(test.document); got != test.exp {
+						t.Fatalf("\t%s\tTest %d:\tShould get the operation %+v: %+v", failed, testID, test.exp, got)
+					}
+					t.Logf("\t%s\tTest %d:\tShould get the operation.", success, testID)
+				}
+			}
+			t.Run(test.name, tf)
+		}
+	}
+}
+
+func TestOperationLabels(t *testing.T) {
+	t.Log("Given the need to group log messages and errors by operation.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a named mutation fails.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"errors": [{"message": "invalid input"}]}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			var logs []string
+			gql := graphql.New(server.URL, graphql.WithLogging(func(s string) { logs = append(logs, s) }))
+
+			err := gql.Execute(context.Background(), `mutation AddCity { addCity(input: []) { numUids } }`, nil)
+			if err == nil || !strings.Contains(err.Error(), "operation:[mutation AddCity]") {
+				t.Fatalf("\t%s\tTest %d:\tShould label the error: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould label the error.", success, testID)
+
+			if len(logs) != 1 || !strings.HasPrefix(logs[0], "operation:[mutation AddCity] ") {
+				t.Fatalf("\t%s\tTest %d:\tShould label the log message: %v", failed, testID, logs)
+			}
+			t.Logf("\t%s\tTest %d:\tShould label the log message.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package graphql
+
+import (
+	"context"
+)
+
+// Pager determines the variables used to request each page of results.
+type Pager[T any] interface {
+
+	// Start sets the variables for the first page.
+	Start(vars map[string]interface{})
+
+	// Next inspects the page that was just retrieved and sets the variables
+	// for the next page. It returns false when there are no more pages.
+	Next(page *T, vars map[string]interface{}) bool
+}
+
+// Paginate repeatedly executes the query against the url/graphql endpoint,
+// using the pager to set the variables for each page, and calls the
+// function with each page until the pages are exhausted or the function
+// returns an error.
+func Paginate[T any](ctx context.Context, gql *GraphQL, graphql string, pager Pager[T], fn func(page *T) error, variables ...func(m map[string]interface{})) error {
+	queryVars := make(map[string]interface{})
+	for _, variable := range variables {
+		variable(queryVars)
+	}
+	pager.Start(queryVars)
+
+	for {
+		var page T
+		if err := gql.query(ctx, gql.graphqlPath, graphql, queryVars, &page); err != nil {
+			return err
+		}
+
+		if err := fn(&page); err != nil {
+			return err
+		}
+
+		if !pager.Next(&page, queryVars) {
+			return nil
+		}
+	}
+}
+
+// =============================================================================
+
+// OffsetPager pages through results using first and offset variables, the
+// pattern Dgraph uses. Count returns the number of items in a page and
+// paging stops when a page has fewer items than the page size.
+type OffsetPager[T any] struct {
+	PageSize  int
+	Count     func(page *T) int
+	FirstVar  string
+	OffsetVar string
+}
+
+// Start implements the Pager interface.
+func (p OffsetPager[T]) Start(vars map[string]interface{}) {
+	vars[p.firstVar()] = p.PageSize
+	vars[p.offsetVar()] = 0
+}
+
+// Next implements the Pager interface.
+func (p OffsetPager[T]) Next(page *T, vars map[string]interface{}) bool {
+	n := p.Count(page)
+	if n < p.PageSize || n == 0 {
+		return false
+	}
+
+	offset, _ := vars[p.offsetVar()].(int)
+	vars[p.offsetVar()] = offset + n
+	return true
+}
+
+func (p OffsetPager[T]) firstVar() string {
+	if p.FirstVar == "" {
+		return "first"
+	}
+	return p.FirstVar
+}
+
+func (p OffsetPager[T]) offsetVar() string {
+	if p.OffsetVar == "" {
+		return "offset"
+	}
+	return p.OffsetVar
+}
+
+// =============================================================================
+
+// CursorPager pages through results using a cursor variable, the pattern
+// Relay style connections use. Cursor returns the cursor to continue from
+// and whether there is a next page.
+type CursorPager[T any] struct {
+	PageSize  int
+	Cursor    func(page *T) (cursor string, hasNext bool)
+	FirstVar  string
+	CursorVar string
+}
+
+// Start implements the Pager interface.
+func (p CursorPager[T]) Start(vars map[string]interface{}) {
+	if p.PageSize > 0 {
+		vars[p.firstVar()] = p.PageSize
+	}
+	vars[p.cursorVar()] = nil
+}
+
+// Next implements the Pager interface.
+func (p CursorPager[T]) Next(page *T, vars map[string]interface{}) bool {
+	cursor, hasNext := p.Cursor(page)
+	if !hasNext || cursor == "" {
+		return false
+	}
+
+	vars[p.cursorVar()] = cursor
+	return true
+}
+
+func (p CursorPager[T]) firstVar() string {
+	if p.FirstVar == "" {
+		return "first"
+	}
+	return p.FirstVar
+}
+
+func (p CursorPager[T]) cursorVar() string {
+	if p.CursorVar == "" {
+		return "after"
+	}
+	return p.CursorVar
+}
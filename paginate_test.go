@@ -0,0 +1,77 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestPaginate(t *testing.T) {
+	type page struct {
+		Cities []string `json:"cities"`
+	}
+
+	all := []string{"a", "b", "c", "d", "e"}
+
+	f := func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		var req struct {
+			Variables struct {
+				First  int `json:"first"`
+				Offset int `json:"offset"`
+			} `json:"variables"`
+		}
+		json.Unmarshal(b, &req)
+
+		start, end := req.Variables.Offset, req.Variables.Offset+req.Variables.First
+		if start > len(all) {
+			start = len(all)
+		}
+		if end > len(all) {
+			end = len(all)
+		}
+
+		data, _ := json.Marshal(page{Cities: all[start:end]})
+		fmt.Fprintf(w, `{"data": %s}`, data)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(f))
+	defer server.Close()
+
+	t.Log("Given the need to page through results.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen paging with first and offset.", testID)
+		{
+			gql := graphql.New(server.URL)
+
+			pager := graphql.OffsetPager[page]{
+				PageSize: 2,
+				Count:    func(p *page) int { return len(p.Cities) },
+			}
+
+			var got []string
+			var pages int
+			err := graphql.Paginate[page](context.Background(), gql, `query ($first: Int, $offset: Int) { cities(first: $first, offset: $offset) }`, pager, func(p *page) error {
+				pages++
+				got = append(got, p.Cities...)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to page through the results: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to page through the results.", success, testID)
+
+			if pages != 3 || fmt.Sprint(got) != fmt.Sprint(all) {
+				t.Fatalf("\t%s\tTest %d:\tShould get every result in 3 pages: %d %v", failed, testID, pages, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get every result in 3 pages.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package graphql_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestPassthrough(t *testing.T) {
+	t.Log("Given the need to forward the data of a response verbatim.")
+	{
+		const data = `{"city": {"name": "Miami",  "lat": 25.7617000}}`
+
+		f := func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, `{"data": `+data+`}`)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(f))
+		defer server.Close()
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the response is a json.RawMessage.", testID)
+		{
+			gql := graphql.New(server.URL)
+
+			var raw json.RawMessage
+			if err := gql.Execute(context.Background(), `query { city { name lat } }`, &raw); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if string(raw) != data {
+				t.Fatalf("\t%s\tTest %d:\tShould get the data verbatim: %s", failed, testID, raw)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the data verbatim.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the response is an io.Writer.", testID)
+		{
+			gql := graphql.New(server.URL)
+
+			var b bytes.Buffer
+			if err := gql.Execute(context.Background(), `query { city { name lat } }`, &b); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if b.String() != data {
+				t.Fatalf("\t%s\tTest %d:\tShould get the data verbatim: %s", failed, testID, b.String())
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the data verbatim.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the response is an io.Writer and served from the cache.", testID)
+		{
+			gql := graphql.New(server.URL, graphql.WithCache(graphql.NewMemoryCache(), time.Minute))
+
+			for i := 0; i < 2; i++ {
+				var b bytes.Buffer
+				if err := gql.Execute(context.Background(), `query { city { name lat } }`, &b); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+				if b.String() != data {
+					t.Fatalf("\t%s\tTest %d:\tShould get the data verbatim: %s", failed, testID, b.String())
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the data verbatim.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package graphql
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Names of the manifest formats produced by ApolloJSON and RelayJSON, for
+// tooling that lets the format be chosen.
+const (
+	ManifestApollo = "apollo"
+	ManifestRelay  = "relay"
+)
+
+// apolloManifestFormat identifies an Apollo persisted query manifest.
+const apolloManifestFormat = "apollo-persisted-query-manifest"
+
+// PersistedOperation represents an operation in a persisted query manifest.
+// ID is the sha256 hash of the body.
+type PersistedOperation struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Body string `json:"body"`
+}
+
+// Manifest represents the operations a persisted query server allows,
+// indexed by the hash of their document.
+type Manifest struct {
+	Operations []PersistedOperation
+}
+
+// QueryHash returns the hash that identifies the document to a persisted
+// query server.
+func QueryHash(document string) string {
+	sum := sha256.Sum256([]byte(document))
+	return hex.EncodeToString(sum[:])
+}
+
+// Manifest produces a persisted query manifest for every operation in the
+// registry. The body of each operation includes the fragments it uses, so
+// it matches the document ExecuteNamed sends.
+func (r *Registry) Manifest() (*Manifest, error) {
+	var m Manifest
+	for _, name := range r.Names() {
+		document, err := r.Lookup(name)
+		if err != nil {
+			return nil, err
+		}
+
+		m.Operations = append(m.Operations, PersistedOperation{
+			ID:   QueryHash(document),
+			Name: name,
+			Type: ParseOperation(document).Type,
+			Body: document,
+		})
+	}
+
+	return &m, nil
+}
+
+// ApolloJSON encodes the manifest in the Apollo persisted query manifest
+// format.
+func (m *Manifest) ApolloJSON() ([]byte, error) {
+	doc := struct {
+		Format     string               `json:"format"`
+		Version    int                  `json:"version"`
+		Operations []PersistedOperation `json:"operations"`
+	}{
+		Format:     apolloManifestFormat,
+		Version:    1,
+		Operations: m.Operations,
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("graphql encoding error: %w", err)
+	}
+	return b, nil
+}
+
+// RelayJSON encodes the manifest in the Relay format, an object mapping the
+// hash of every operation to its body.
+func (m *Manifest) RelayJSON() ([]byte, error) {
+	doc := make(map[string]string, len(m.Operations))
+	for _, op := range m.Operations {
+		doc[op.ID] = op.Body
+	}
+
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("graphql encoding error: %w", err)
+	}
+	return b, nil
+}
+
+// ParseManifest decodes a manifest in either the Apollo or the Relay format.
+func ParseManifest(data []byte) (*Manifest, error) {
+	var apollo struct {
+		Format     string               `json:"format"`
+		Operations []PersistedOperation `json:"operations"`
+	}
+	if err := json.Unmarshal(data, &apollo); err == nil && apollo.Format == apolloManifestFormat {
+		return &Manifest{Operations: apollo.Operations}, nil
+	}
+
+	var relay map[string]string
+	if err := json.Unmarshal(data, &relay); err != nil {
+		return nil, fmt.Errorf("graphql manifest error: unknown manifest format: %w", err)
+	}
+
+	m := Manifest{
+		Operations: make([]PersistedOperation, 0, len(relay)),
+	}
+	for id, body := range relay {
+		op := ParseOperation(body)
+		m.Operations = append(m.Operations, PersistedOperation{ID: id, Name: op.Name, Type: op.Type, Body: body})
+	}
+	sort.Slice(m.Operations, func(i, j int) bool { return m.Operations[i].Name < m.Operations[j].Name })
+
+	return &m, nil
+}
+
+// WithPersistedQueries sends only the hash of each document, using the
+// Apollo persisted query extension, instead of the document itself.
+// Documents that aren't in the manifest are rejected without being sent.
+// Only requests to the url/graphql endpoint are persisted, so requests to
+// other endpoints, like the url/admin login of WithLogin, are unaffected.
+func WithPersistedQueries(m *Manifest) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.persisted = make(map[string]bool, len(m.Operations))
+		for _, op := range m.Operations {
+			gql.persisted[op.ID] = true
+		}
+	}
+}
+
+// encodePersisted applies the graphql request document around the hash of
+// the persisted query and the variables.
+func (g *GraphQL) encodePersisted(graphql string, queryVars map[string]interface{}) ([]byte, error) {
+	hash := QueryHash(graphql)
+	if !g.persisted[hash] {
+		return nil, fmt.Errorf("graphql persisted query error: %s is not in the manifest", describeOperation(graphql))
+	}
+
+	type persistedQuery struct {
+		Version    int    `json:"version"`
+		SHA256Hash string `json:"sha256Hash"`
+	}
+
+	request := struct {
+		OperationName string                 `json:"operationName,omitempty"`
+		Variables     map[string]interface{} `json:"variables"`
+		Extensions    struct {
+			PersistedQuery persistedQuery `json:"persistedQuery"`
+		} `json:"extensions"`
+	}{
+		OperationName: ParseOperation(graphql).Name,
+		Variables:     queryVars,
+	}
+	request.Extensions.PersistedQuery = persistedQuery{Version: 1, SHA256Hash: hash}
+
+	b, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("graphql encoding error: %w", err)
+	}
+	return append(b, '\n'), nil
+}
+
+// describeOperation names the operation of the document for errors.
+func describeOperation(graphql string) string {
+	if op := ParseOperation(graphql); op.Name != "" {
+		return "operation " + op.String()
+	}
+	return "anonymous operation"
+}
@@ -0,0 +1,156 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestPersistedQueries(t *testing.T) {
+	t.Log("Given the need to send only registered operations as hashes.")
+	{
+		registry := graphql.NewRegistry()
+		if err := registry.Add(`query GetCity($id: ID!) { getCity(id: $id) { ...CityFields } }
+fragment CityFields on City { name }`); err != nil {
+			t.Fatalf("\t%s\tShould be able to register the operations: %v", failed, err)
+		}
+
+		manifest, err := registry.Manifest()
+		if err != nil {
+			t.Fatalf("\t%s\tShould be able to produce the manifest: %v", failed, err)
+		}
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen encoding the manifest.", testID)
+		{
+			document, _ := registry.Lookup("GetCity")
+			if len(manifest.Operations) != 1 || manifest.Operations[0].ID != graphql.QueryHash(document) || manifest.Operations[0].Type != "query" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the operations: %+v", failed, testID, manifest.Operations)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the operations.", success, testID)
+
+			for _, encode := range []func() ([]byte, error){manifest.ApolloJSON, manifest.RelayJSON} {
+				data, err := encode()
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to encode the manifest: %v", failed, testID, err)
+				}
+
+				got, err := graphql.ParseManifest(data)
+				if err != nil || len(got.Operations) != 1 || got.Operations[0] != manifest.Operations[0] {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to parse the manifest: %v %+v", failed, testID, err, got)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to parse the encoded manifests.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen executing operations with persisted queries.", testID)
+		{
+			var calls int
+			f := func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				b, _ := ioutil.ReadAll(r.Body)
+
+				var req struct {
+					Query      *string `json:"query"`
+					Extensions struct {
+						PersistedQuery struct {
+							SHA256Hash string `json:"sha256Hash"`
+						} `json:"persistedQuery"`
+					} `json:"extensions"`
+				}
+				json.Unmarshal(b, &req)
+
+				if req.Query != nil || req.Extensions.PersistedQuery.SHA256Hash != manifest.Operations[0].ID {
+					t.Fatalf("\t%s\tTest %d:\tShould send only the hash: %s", failed, testID, b)
+				}
+				t.Logf("\t%s\tTest %d:\tShould send only the hash.", success, testID)
+
+				io.WriteString(w, `{"data": {"getCity": {"name": "Miami"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL,
+				graphql.WithRegistry(registry),
+				graphql.WithPersistedQueries(manifest),
+			)
+
+			if err := gql.ExecuteNamed(context.Background(), "GetCity", nil, graphql.WithVariable("id", "0x1")); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the operation: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the operation.", success, testID)
+
+			err := gql.Execute(context.Background(), `query Other { queryCity { name } }`, nil)
+			if err == nil || !strings.Contains(err.Error(), "query Other is not in the manifest") || calls != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould reject unregistered operations: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject unregistered operations.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen executing persisted queries with a login.", testID)
+		{
+			var mu sync.Mutex
+			var loginQuery, token string
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+
+				var req struct {
+					Query *string `json:"query"`
+				}
+				json.Unmarshal(b, &req)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				if r.URL.Path == "/admin" {
+					if req.Query != nil {
+						loginQuery = *req.Query
+					}
+					io.WriteString(w, `{"data": {"login": {"response": {"accessJWT": "token", "refreshJWT": "refresh"}}}}`)
+					return
+				}
+
+				token = r.Header.Get("X-Dgraph-AccessToken")
+				io.WriteString(w, `{"data": {"getCity": {"name": "Miami"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL,
+				graphql.WithRegistry(registry),
+				graphql.WithPersistedQueries(manifest),
+				graphql.WithLogin("groot", "password"),
+			)
+
+			if err := gql.ExecuteNamed(context.Background(), "GetCity", nil, graphql.WithVariable("id", "0x1")); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the operation: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the operation.", success, testID)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if !strings.Contains(loginQuery, "login(") {
+				t.Fatalf("\t%s\tTest %d:\tShould send the login document in full: %q", failed, testID, loginQuery)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the login document in full.", success, testID)
+
+			if token != "token" {
+				t.Fatalf("\t%s\tTest %d:\tShould send the access token: %q", failed, testID, token)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the access token.", success, testID)
+		}
+	}
+}
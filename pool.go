@@ -0,0 +1,35 @@
+package graphql
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBuffer is the largest buffer that is returned to the pool. Larger
+// buffers are left for the garbage collector so an occasional large request
+// doesn't pin that memory.
+const maxPooledBuffer = 1 << 20
+
+// bufferPool provides reusable buffers for encoding requests and reading
+// responses.
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// getBuffer returns an empty buffer from the pool.
+func getBuffer() *bytes.Buffer {
+	b := bufferPool.Get().(*bytes.Buffer)
+	b.Reset()
+	return b
+}
+
+// putBuffer returns the buffer to the pool. The buffer must not be used
+// after it's returned.
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBuffer {
+		return
+	}
+	bufferPool.Put(b)
+}
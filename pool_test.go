@@ -0,0 +1,76 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func BenchmarkExecute(b *testing.B) {
+	payload := `{"data": {"queryCity": [` + strings.TrimSuffix(strings.Repeat(`{"id": "0x01", "name": "Miami", "lat": 25.7617, "lng": -80.1918},`, 100), ",") + `]}}`
+
+	f := func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(ioutil.Discard, r.Body)
+		io.WriteString(w, payload)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(f))
+	defer server.Close()
+
+	gql := graphql.New(server.URL)
+
+	var response struct {
+		QueryCity []struct {
+			ID   string  `json:"id"`
+			Name string  `json:"name"`
+			Lat  float64 `json:"lat"`
+			Lng  float64 `json:"lng"`
+		} `json:"queryCity"`
+	}
+
+	query := `query($name: String!) { queryCity(filter: {name: {eq: $name}}) { id name lat lng } }`
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := gql.Execute(context.Background(), query, &response, graphql.WithVariable("name", "Miami")); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExecuteParallel(b *testing.B) {
+	f := func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(ioutil.Discard, r.Body)
+		io.WriteString(w, `{"data": {"getCity": {"id": "0x01", "name": "Miami"}}}`)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(f))
+	defer server.Close()
+
+	gql := graphql.New(server.URL)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		var response struct {
+			GetCity struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"getCity"`
+		}
+		for pb.Next() {
+			if err := gql.Execute(context.Background(), `query { getCity(id: "0x01") { id name } }`, &response); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,45 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+)
+
+// PrefetchOp represents a registered query executed by Prefetch and the
+// variables to execute it with.
+type PrefetchOp struct {
+	Name      string
+	Variables []func(m map[string]interface{})
+}
+
+// Prefetch executes the named queries from the configured registry against
+// the url/graphql endpoint at the same time and discards the results. It's
+// meant to be called at startup, so the responses are in the cache and the
+// connections and TLS sessions are established before the first request
+// after a deploy arrives. Only queries can be prefetched. If any queries
+// fail, a *MultiError is returned where each failure is indexed by the
+// position of the operation.
+func (g *GraphQL) Prefetch(ctx context.Context, ops ...PrefetchOp) error {
+	if g.registry == nil {
+		return fmt.Errorf("graphql registry error: no registry configured")
+	}
+
+	calls := make([]Call, len(ops))
+	for i, op := range ops {
+		document, err := g.registry.Lookup(op.Name)
+		if err != nil {
+			return err
+		}
+
+		if !ParseOperation(document).readOnly() {
+			return fmt.Errorf("graphql prefetch error: operation %q is not a query", op.Name)
+		}
+
+		calls[i] = Call{
+			Query:     document,
+			Variables: op.Variables,
+		}
+	}
+
+	return g.ExecuteAll(ctx, calls, len(calls))
+}
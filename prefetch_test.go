@@ -0,0 +1,77 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestPrefetch(t *testing.T) {
+	t.Log("Given the need to warm the cache at startup.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen prefetching registered queries.", testID)
+		{
+			registry := graphql.NewRegistry()
+			err := registry.Add(`
+query GetCity($id: ID!) { getCity(id: $id) { name } }
+query ListCities { queryCity { name } }
+mutation AddCity($name: String!) { addCity(name: $name) { name } }`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to register the operations: %v", failed, testID, err)
+			}
+
+			var calls int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				io.WriteString(w, `{"data": {"getCity": {"name": "Miami"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL,
+				graphql.WithRegistry(registry),
+				graphql.WithCache(graphql.NewMemoryCache(), time.Minute),
+			)
+			ctx := context.Background()
+			vars := graphql.WithVariable("id", "0x01")
+
+			err = gql.Prefetch(ctx,
+				graphql.PrefetchOp{Name: "GetCity", Variables: []func(m map[string]interface{}){vars}},
+				graphql.PrefetchOp{Name: "ListCities"},
+			)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to prefetch the queries: %v", failed, testID, err)
+			}
+			if n := atomic.LoadInt32(&calls); n != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould execute every query: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to prefetch the queries.", success, testID)
+
+			var got struct {
+				GetCity struct {
+					Name string `json:"name"`
+				} `json:"getCity"`
+			}
+			if err := gql.ExecuteNamed(ctx, "GetCity", &got, vars); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if n := atomic.LoadInt32(&calls); n != 2 || got.GetCity.Name != "Miami" {
+				t.Fatalf("\t%s\tTest %d:\tShould answer from the warmed cache: %d %q", failed, testID, n, got.GetCity.Name)
+			}
+			t.Logf("\t%s\tTest %d:\tShould answer from the warmed cache.", success, testID)
+
+			if err := gql.Prefetch(ctx, graphql.PrefetchOp{Name: "AddCity"}); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould not prefetch a mutation.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not prefetch a mutation.", success, testID)
+		}
+	}
+}
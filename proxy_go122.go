@@ -0,0 +1,7 @@
+//go:build go1.22
+
+package graphql
+
+// socks5hSupported reports whether net/http accepts proxies with the socks5h
+// scheme, which it does starting with Go 1.22.
+const socks5hSupported = true
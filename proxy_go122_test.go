@@ -0,0 +1,46 @@
+//go:build go1.22
+
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestProxySOCKS5H(t *testing.T) {
+	t.Log("Given the need to resolve host names on a SOCKS5 proxy.")
+	{
+		f := func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, `{"data": {"__typename": "Query"}}`)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(f))
+		defer server.Close()
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen using a socks5h proxy url.", testID)
+		{
+			addr, connects := socks5Proxy(t)
+
+			gql, err := graphql.NewE(server.URL, graphql.WithProxy("socks5h://"+addr))
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to construct the client: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to construct the client.", success, testID)
+
+			var resp struct{}
+			if err := gql.Execute(context.Background(), `query { __typename }`, &resp); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if got := <-connects; got != server.Listener.Addr().String() {
+				t.Fatalf("\t%s\tTest %d:\tShould connect through the proxy: %s", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould connect through the proxy.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !go1.22
+
+package graphql
+
+// socks5hSupported reports whether net/http accepts proxies with the socks5h
+// scheme. Before Go 1.22 it only accepts socks5.
+const socks5hSupported = false
@@ -0,0 +1,25 @@
+//go:build !go1.22
+
+package graphql_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestProxySOCKS5H(t *testing.T) {
+	t.Log("Given the need to resolve host names on a SOCKS5 proxy.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen using a socks5h proxy url before Go 1.22.", testID)
+		{
+			_, err := graphql.NewE("http://localhost:8080", graphql.WithProxy("socks5h://localhost:1080"))
+			if err == nil || !strings.Contains(err.Error(), "requires Go 1.22") {
+				t.Fatalf("\t%s\tTest %d:\tShould get an error constructing the client: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an error constructing the client.", success, testID)
+		}
+	}
+}
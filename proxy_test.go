@@ -0,0 +1,134 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestProxy(t *testing.T) {
+	t.Log("Given the need to route requests through a proxy.")
+	{
+		f := func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, `{"data": {"__typename": "Query"}}`)
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(f))
+		defer server.Close()
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen using an HTTP proxy.", testID)
+		{
+			var target string
+			p := func(w http.ResponseWriter, r *http.Request) {
+				target = r.URL.String()
+				io.WriteString(w, `{"data": {"__typename": "Query"}}`)
+			}
+
+			proxy := httptest.NewServer(http.HandlerFunc(p))
+			defer proxy.Close()
+
+			gql := graphql.New(server.URL, graphql.WithProxy(proxy.URL))
+
+			var resp struct{}
+			if err := gql.Execute(context.Background(), `query { __typename }`, &resp); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if target != server.URL+"/graphql" {
+				t.Fatalf("\t%s\tTest %d:\tShould send the request through the proxy: %s", failed, testID, target)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the request through the proxy.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen using a SOCKS5 proxy.", testID)
+		{
+			addr, connects := socks5Proxy(t)
+
+			gql := graphql.New(server.URL, graphql.WithProxy("socks5://"+addr))
+
+			var resp struct{}
+			if err := gql.Execute(context.Background(), `query { __typename }`, &resp); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if got := <-connects; got != server.Listener.Addr().String() {
+				t.Fatalf("\t%s\tTest %d:\tShould connect through the proxy: %s", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould connect through the proxy.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the proxy scheme isn't supported.", testID)
+		{
+			if _, err := graphql.NewE(server.URL, graphql.WithProxy("ftp://localhost:21")); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get an error constructing the client.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an error constructing the client.", success, testID)
+		}
+	}
+}
+
+// socks5Proxy starts a minimal SOCKS5 proxy that supports unauthenticated
+// CONNECT requests to IPv4 addresses. The address of every connection made
+// is sent on the returned channel.
+func socks5Proxy(t *testing.T) (string, <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	connects := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				// Greeting: version, method count, methods.
+				buf := make([]byte, 262)
+				if _, err := io.ReadFull(conn, buf[:2]); err != nil {
+					return
+				}
+				if _, err := io.ReadFull(conn, buf[:buf[1]]); err != nil {
+					return
+				}
+				conn.Write([]byte{5, 0})
+
+				// Request: version, command, reserved, IPv4 address, port.
+				if _, err := io.ReadFull(conn, buf[:10]); err != nil || buf[3] != 1 {
+					return
+				}
+				ip := net.IP(buf[4:8])
+				port := binary.BigEndian.Uint16(buf[8:10])
+				addr := net.JoinHostPort(ip.String(), strconv.Itoa(int(port)))
+
+				target, err := net.Dial("tcp", addr)
+				if err != nil {
+					conn.Write([]byte{5, 1, 0, 1, 0, 0, 0, 0, 0, 0})
+					return
+				}
+				defer target.Close()
+				connects <- addr
+
+				conn.Write([]byte{5, 0, 0, 1, 0, 0, 0, 0, 0, 0})
+
+				go io.Copy(target, conn)
+				io.Copy(conn, target)
+			}()
+		}
+	}()
+
+	return l.Addr().String(), connects
+}
@@ -0,0 +1,404 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQueued is matched by the error MutationQueue.Mutate returns when the
+// mutation couldn't be delivered and was stored to be retried later.
+var ErrQueued = errors.New("graphql mutation queued")
+
+// QueuedMutation represents a mutation waiting in a MutationQueue. The ID
+// is assigned by the store and orders the mutations.
+type QueuedMutation struct {
+	ID        string                 `json:"id"`
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+	QueuedAt  time.Time              `json:"queued_at"`
+}
+
+// QueueStore persists the mutations waiting in a MutationQueue.
+// Implementations must be safe for concurrent use.
+type QueueStore interface {
+
+	// Push stores the mutation, assigning it an ID that sorts after the ID
+	// of every mutation already stored.
+	Push(m QueuedMutation) (QueuedMutation, error)
+
+	// List returns the stored mutations in the order they were pushed.
+	List() ([]QueuedMutation, error)
+
+	// Remove deletes the mutation with the specified ID.
+	Remove(id string) error
+}
+
+// =============================================================================
+
+// MutationQueue delivers mutations in the order they are made, storing the
+// ones that can't be delivered because the host is unreachable and
+// retrying them in the background. This supports edge deployments with
+// unreliable links to the host. Responses of mutations delivered from the
+// store are discarded.
+type MutationQueue struct {
+	gql      *GraphQL
+	store    QueueStore
+	interval time.Duration
+	onDrop   func(m QueuedMutation, err error)
+
+	flushMu sync.Mutex
+
+	once       sync.Once
+	shutdown   chan struct{}
+	wg         sync.WaitGroup
+	unregister func()
+}
+
+// NewMutationQueue constructs a MutationQueue that delivers mutations with
+// the client and stores undelivered ones in the store. Stored mutations
+// are retried on the specified interval once Start is called.
+func NewMutationQueue(gql *GraphQL, store QueueStore, interval time.Duration, options ...func(mq *MutationQueue)) *MutationQueue {
+	mq := MutationQueue{
+		gql:      gql,
+		store:    store,
+		interval: interval,
+		shutdown: make(chan struct{}),
+	}
+
+	for _, option := range options {
+		option(&mq)
+	}
+
+	return &mq
+}
+
+// WithDropHandler adds a function that is called with a stored mutation
+// the host rejected for a reason that isn't retryable, like a validation
+// error, or whose delivery is unknown because the connection failed after
+// it was sent. The mutation is removed from the store after the call.
+func WithDropHandler(onDrop func(m QueuedMutation, err error)) func(mq *MutationQueue) {
+	return func(mq *MutationQueue) {
+		mq.onDrop = onDrop
+	}
+}
+
+// Mutate executes the mutation against the url/graphql endpoint. When
+// mutations are already waiting, or the mutation fails before the host
+// could apply it, the mutation is stored and an error matching ErrQueued is
+// returned. That is the case when the connection can't be established, the
+// request is shed, the host responds with a 429 or 503 status code, or the
+// transaction is aborted. Other errors, including a connection that fails
+// after the request is sent, are returned as is and the mutation isn't
+// stored, since it may have been applied and sending it again could apply
+// it twice.
+func (mq *MutationQueue) Mutate(ctx context.Context, graphql string, response interface{}, variables ...func(m map[string]interface{})) error {
+	var queryVars map[string]interface{}
+	if len(variables) > 0 {
+		queryVars = make(map[string]interface{})
+		for _, variable := range variables {
+			variable(queryVars)
+		}
+	}
+
+	mq.flushMu.Lock()
+	defer mq.flushMu.Unlock()
+
+	// Mutations are delivered in order, so a mutation can't be sent ahead
+	// of the ones that are waiting.
+	pending, err := mq.store.List()
+	if err != nil {
+		return fmt.Errorf("graphql queue error: %w", err)
+	}
+
+	if len(pending) == 0 {
+		err := mq.gql.query(ctx, mq.gql.graphqlPath, graphql, queryVars, response)
+		if err == nil || !undelivered(err) {
+			return err
+		}
+	}
+
+	m := QueuedMutation{
+		Query:     graphql,
+		Variables: queryVars,
+		QueuedAt:  time.Now().UTC(),
+	}
+	if m, err = mq.store.Push(m); err != nil {
+		return fmt.Errorf("graphql queue error: %w", err)
+	}
+
+	return fmt.Errorf("%w: id[%s]", ErrQueued, m.ID)
+}
+
+// Flush delivers the stored mutations in order, stopping at the first one
+// that fails before the host could apply it and returning that error.
+// Mutations that fail for other reasons, including a connection that fails
+// after the request is sent, are passed to the drop handler and removed so
+// they're never applied twice.
+func (mq *MutationQueue) Flush(ctx context.Context) error {
+	mq.flushMu.Lock()
+	defer mq.flushMu.Unlock()
+
+	pending, err := mq.store.List()
+	if err != nil {
+		return fmt.Errorf("graphql queue error: %w", err)
+	}
+
+	for _, m := range pending {
+		err := mq.gql.query(ctx, mq.gql.graphqlPath, m.Query, m.Variables, nil)
+		if err != nil && undelivered(err) {
+			return err
+		}
+
+		if err != nil && mq.onDrop != nil {
+			mq.onDrop(m, err)
+		}
+
+		if err := mq.store.Remove(m.ID); err != nil {
+			return fmt.Errorf("graphql queue error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// undelivered reports whether the mutation failed before the host could
+// apply it, so it's safe to send again.
+func undelivered(err error) bool {
+	if errors.Is(err, ErrShed) || ErrorCode(err) == CodeAborted {
+		return true
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return true
+		}
+		return false
+	}
+
+	var opErr *net.OpError
+	return IsTransportError(err) && errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// Len returns the number of mutations waiting to be delivered.
+func (mq *MutationQueue) Len() (int, error) {
+	pending, err := mq.store.List()
+	if err != nil {
+		return 0, fmt.Errorf("graphql queue error: %w", err)
+	}
+	return len(pending), nil
+}
+
+// Start flushes the stored mutations on the configured interval until
+// Stop is called or the client is closed.
+func (mq *MutationQueue) Start() {
+	mq.unregister = mq.gql.closers.add(mq.stop)
+
+	mq.wg.Add(1)
+	go func() {
+		defer mq.wg.Done()
+
+		ticker := time.NewTicker(mq.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				mq.Flush(context.Background())
+			case <-mq.shutdown:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background flushing and waits for any flush in progress
+// to complete. Stored mutations remain in the store. It's safe to call Stop
+// more than once.
+func (mq *MutationQueue) Stop() {
+	if mq.unregister != nil {
+		mq.unregister()
+	}
+	mq.stop()
+}
+
+// stop signals the background flushing to end and waits for it.
+func (mq *MutationQueue) stop() {
+	mq.once.Do(func() {
+		close(mq.shutdown)
+	})
+	mq.wg.Wait()
+}
+
+// =============================================================================
+
+// MemoryQueueStore is an in-memory implementation of the QueueStore
+// interface. Mutations are lost when the process exits.
+type MemoryQueueStore struct {
+	mu        sync.Mutex
+	next      int
+	mutations []QueuedMutation
+}
+
+// NewMemoryQueueStore constructs an empty in-memory queue store.
+func NewMemoryQueueStore() *MemoryQueueStore {
+	return &MemoryQueueStore{}
+}
+
+// Push implements the QueueStore interface.
+func (s *MemoryQueueStore) Push(m QueuedMutation) (QueuedMutation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next++
+	m.ID = queueID(s.next)
+	s.mutations = append(s.mutations, m)
+
+	return m, nil
+}
+
+// List implements the QueueStore interface.
+func (s *MemoryQueueStore) List() ([]QueuedMutation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]QueuedMutation(nil), s.mutations...), nil
+}
+
+// Remove implements the QueueStore interface.
+func (s *MemoryQueueStore) Remove(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, m := range s.mutations {
+		if m.ID == id {
+			s.mutations = append(s.mutations[:i], s.mutations[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// FileQueueStore is an implementation of the QueueStore interface that
+// keeps each mutation in a JSON file in a directory, so mutations survive
+// restarts. A directory must only be used by one store at a time.
+type FileQueueStore struct {
+	dir  string
+	mu   sync.Mutex
+	next int
+}
+
+// queueExt is the extension of the files a FileQueueStore writes.
+const queueExt = ".json"
+
+// NewFileQueueStore constructs a store that keeps mutations in the
+// specified directory, creating it if required. Mutations already in the
+// directory are kept.
+func NewFileQueueStore(dir string) (*FileQueueStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("graphql queue error: %w", err)
+	}
+
+	s := FileQueueStore{
+		dir: dir,
+	}
+
+	pending, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 {
+		fmt.Sscanf(pending[len(pending)-1].ID, "%d", &s.next)
+	}
+
+	return &s, nil
+}
+
+// Push implements the QueueStore interface. The file is written to a
+// temporary name first so a crash never leaves a partial mutation.
+func (s *FileQueueStore) Push(m QueuedMutation) (QueuedMutation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.next++
+	m.ID = queueID(s.next)
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		return QueuedMutation{}, fmt.Errorf("graphql queue error: %w", err)
+	}
+
+	path := filepath.Join(s.dir, m.ID+queueExt)
+	if err := ioutil.WriteFile(path+".tmp", b, 0o600); err != nil {
+		return QueuedMutation{}, fmt.Errorf("graphql queue error: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return QueuedMutation{}, fmt.Errorf("graphql queue error: %w", err)
+	}
+
+	return m, nil
+}
+
+// List implements the QueueStore interface.
+func (s *FileQueueStore) List() ([]QueuedMutation, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("graphql queue error: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), queueExt) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	mutations := make([]QueuedMutation, 0, len(names))
+	for _, name := range names {
+		b, err := ioutil.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("graphql queue error: %w", err)
+		}
+
+		var m QueuedMutation
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, fmt.Errorf("graphql queue error: %s: %w", name, err)
+		}
+		mutations = append(mutations, m)
+	}
+
+	return mutations, nil
+}
+
+// Remove implements the QueueStore interface.
+func (s *FileQueueStore) Remove(id string) error {
+	err := os.Remove(filepath.Join(s.dir, id+queueExt))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("graphql queue error: %w", err)
+	}
+	return nil
+}
+
+// queueID formats the sequence number as an ID that sorts in order.
+func queueID(seq int) string {
+	return fmt.Sprintf("%020d", seq)
+}
@@ -0,0 +1,161 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMutationQueue(t *testing.T) {
+	t.Log("Given the need to deliver mutations over an unreliable link.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the host is unavailable and comes back.", testID)
+		{
+			var mu sync.Mutex
+			down := true
+			var got []string
+
+			f := func(w http.ResponseWriter, r *http.Request) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if down {
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+
+				var req struct {
+					Variables map[string]string `json:"variables"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+
+				if req.Variables["name"] == "Bad" {
+					io.WriteString(w, `{"errors": [{"message": "Cannot query field \"x\""}]}`)
+					return
+				}
+
+				got = append(got, req.Variables["name"])
+				io.WriteString(w, `{"data": {"addCity": {"numUids": 1}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			dir := t.TempDir()
+			store, err := graphql.NewFileQueueStore(dir)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to construct the store: %v", failed, testID, err)
+			}
+
+			var dropped []string
+			onDrop := func(m graphql.QueuedMutation, err error) {
+				dropped = append(dropped, m.Variables["name"].(string))
+			}
+
+			gql := graphql.New(server.URL)
+			mq := graphql.NewMutationQueue(gql, store, time.Hour, graphql.WithDropHandler(onDrop))
+
+			const mutation = `mutation($name: String!) { addCity(input: [{name: $name}]) { numUids } }`
+
+			err = mq.Mutate(context.Background(), mutation, nil, graphql.WithVariable("name", "Miami"))
+			if !errors.Is(err, graphql.ErrQueued) {
+				t.Fatalf("\t%s\tTest %d:\tShould queue the mutation while the host is down: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould queue the mutation while the host is down.", success, testID)
+
+			mu.Lock()
+			down = false
+			mu.Unlock()
+
+			for _, name := range []string{"Bad", "Denver"} {
+				err = mq.Mutate(context.Background(), mutation, nil, graphql.WithVariable("name", name))
+				if !errors.Is(err, graphql.ErrQueued) {
+					t.Fatalf("\t%s\tTest %d:\tShould queue behind the waiting mutations: %v", failed, testID, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould queue behind the waiting mutations.", success, testID)
+
+			reopened, err := graphql.NewFileQueueStore(dir)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to reopen the store: %v", failed, testID, err)
+			}
+			mq = graphql.NewMutationQueue(gql, reopened, time.Hour, graphql.WithDropHandler(onDrop))
+
+			if n, _ := mq.Len(); n != 3 {
+				t.Fatalf("\t%s\tTest %d:\tShould keep the mutations across restarts: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep the mutations across restarts.", success, testID)
+
+			if err := mq.Flush(context.Background()); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to flush the queue: %v", failed, testID, err)
+			}
+
+			if diff := cmp.Diff(got, []string{"Miami", "Denver"}); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould deliver the mutations in order. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould deliver the mutations in order.", success, testID)
+
+			if diff := cmp.Diff(dropped, []string{"Bad"}); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould drop the rejected mutation. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould drop the rejected mutation.", success, testID)
+
+			if n, _ := mq.Len(); n != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould empty the queue: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould empty the queue.", success, testID)
+
+			if err := mq.Mutate(context.Background(), mutation, nil, graphql.WithVariable("name", "Austin")); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould deliver directly once the queue is empty: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould deliver directly once the queue is empty.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen it's unknown whether the host applied the mutation.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.ReadAll(r.Body)
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err == nil {
+					conn.Close()
+				}
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+			mq := graphql.NewMutationQueue(gql, graphql.NewMemoryQueueStore(), time.Hour)
+
+			const mutation = `mutation { addCity(input: [{name: "Miami"}]) { numUids } }`
+
+			err := mq.Mutate(context.Background(), mutation, nil)
+			if !graphql.IsTransportError(err) || errors.Is(err, graphql.ErrQueued) {
+				t.Fatalf("\t%s\tTest %d:\tShould return the error without queuing the mutation: %v", failed, testID, err)
+			}
+			if n, _ := mq.Len(); n != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould return the error without queuing the mutation: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould return the error without queuing the mutation.", success, testID)
+
+			server.Close()
+
+			err = mq.Mutate(context.Background(), mutation, nil)
+			if !errors.Is(err, graphql.ErrQueued) {
+				t.Fatalf("\t%s\tTest %d:\tShould queue the mutation when the host can't be reached: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould queue the mutation when the host can't be reached.", success, testID)
+		}
+	}
+}
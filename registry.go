@@ -0,0 +1,176 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Registry indexes graphql operations by name so they can be kept in
+// .graphql files instead of Go string literals. Fragments referenced by an
+// operation are included automatically when the operation is looked up.
+type Registry struct {
+	mu    sync.RWMutex
+	ops   map[string]definition
+	frags map[string]definition
+}
+
+// NewRegistry constructs an empty operation registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		ops:   make(map[string]definition),
+		frags: make(map[string]definition),
+	}
+}
+
+// LoadFS walks the file system, which can be an embed.FS, and adds every
+// file with a .graphql or .gql extension to the registry.
+func (r *Registry) LoadFS(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		switch path.Ext(name) {
+		case ".graphql", ".gql":
+		default:
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("graphql registry error: %w", err)
+		}
+
+		if err := r.Add(string(data)); err != nil {
+			return fmt.Errorf("graphql registry error: %s: %w", name, err)
+		}
+
+		return nil
+	})
+}
+
+// Add parses the document and adds its named operations and fragments to
+// the registry. Anonymous operations can't be registered.
+func (r *Registry) Add(document string) error {
+	defs, err := parseDefinitions(document)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, def := range defs {
+		if def.name == "" {
+			return fmt.Errorf("anonymous %s can't be registered", def.kind)
+		}
+
+		index := r.ops
+		if def.kind == "fragment" {
+			index = r.frags
+		}
+
+		if _, exists := index[def.name]; exists {
+			return fmt.Errorf("%s %q is already registered", def.kind, def.name)
+		}
+		index[def.name] = def
+	}
+
+	return nil
+}
+
+// Lookup returns the document for the named operation, including every
+// fragment the operation references.
+func (r *Registry) Lookup(name string) (string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	op, exists := r.ops[name]
+	if !exists {
+		return "", fmt.Errorf("graphql registry error: operation %q is not registered", name)
+	}
+
+	seen := make(map[string]bool)
+	var frags []string
+	var walk func(spreads []string) error
+	walk = func(spreads []string) error {
+		for _, spread := range spreads {
+			if seen[spread] {
+				continue
+			}
+			seen[spread] = true
+
+			frag, exists := r.frags[spread]
+			if !exists {
+				return fmt.Errorf("graphql registry error: fragment %q used by %q is not registered", spread, name)
+			}
+
+			frags = append(frags, spread)
+			if err := walk(frag.spreads); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := walk(op.spreads); err != nil {
+		return "", err
+	}
+
+	sort.Strings(frags)
+
+	var b strings.Builder
+	b.WriteString(op.source)
+	for _, frag := range frags {
+		b.WriteString("\n\n")
+		b.WriteString(r.frags[frag].source)
+	}
+
+	return b.String(), nil
+}
+
+// Names returns the sorted names of the registered operations.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.ops))
+	for name := range r.ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// WithRegistry adds a registry of named operations that can be executed
+// with ExecuteNamed.
+func WithRegistry(registry *Registry) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.registry = registry
+	}
+}
+
+// ExecuteNamed performs the named operation from the configured registry
+// against the configured host on the url/graphql endpoint.
+func (g *GraphQL) ExecuteNamed(ctx context.Context, name string, response interface{}, variables ...func(m map[string]interface{})) error {
+	if g.registry == nil {
+		return fmt.Errorf("graphql registry error: no registry configured")
+	}
+
+	document, err := g.registry.Lookup(name)
+	if err != nil {
+		return err
+	}
+
+	return g.Execute(ctx, document, response, variables...)
+}
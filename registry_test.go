@@ -0,0 +1,85 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestRegistry(t *testing.T) {
+	fsys := fstest.MapFS{
+		"queries/city.graphql": {Data: []byte(`
+# Looks up a city by id.
+query GetCity($id: ID!) {
+	getCity(id: $id) { ...CityFields }
+}
+
+mutation AddCity($input: [AddCityInput!]! = {name: "}"}) {
+	addCity(input: $input) { city { id } }
+}
+`)},
+		"queries/fragments.gql": {Data: []byte(`fragment CityFields on City { id name }`)},
+		"README.md":             {Data: []byte(`not a query`)},
+	}
+
+	t.Log("Given the need to execute operations loaded from files.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen loading operations from a file system.", testID)
+		{
+			registry := graphql.NewRegistry()
+			if err := registry.LoadFS(fsys); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to load the operations: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to load the operations.", success, testID)
+
+			if names := registry.Names(); len(names) != 2 || names[0] != "AddCity" || names[1] != "GetCity" {
+				t.Fatalf("\t%s\tTest %d:\tShould index the operations by name: %v", failed, testID, names)
+			}
+			t.Logf("\t%s\tTest %d:\tShould index the operations by name.", success, testID)
+
+			var got string
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				var req struct {
+					Query string `json:"query"`
+				}
+				json.Unmarshal(b, &req)
+				got = req.Query
+				w.Write([]byte(`{"data": {"getCity": {"id": "0x01", "name": "Miami"}}}`))
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithRegistry(registry))
+
+			var resp struct {
+				GetCity struct {
+					Name string `json:"name"`
+				} `json:"getCity"`
+			}
+			if err := gql.ExecuteNamed(context.Background(), "GetCity", &resp, graphql.WithVariable("id", "0x01")); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the named operation: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the named operation.", success, testID)
+
+			exp := "query GetCity($id: ID!) {\n\tgetCity(id: $id) { ...CityFields }\n}\n\nfragment CityFields on City { id name }"
+			if got != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould send the operation with its fragments:\n%s", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the operation with its fragments.", success, testID)
+
+			if err := gql.ExecuteNamed(context.Background(), "Missing", &resp); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould fail for an unknown operation.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould fail for an unknown operation.", success, testID)
+		}
+	}
+}
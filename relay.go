@@ -0,0 +1,50 @@
+package graphql
+
+// PageInfo represents the pagination information of a Relay style
+// connection.
+type PageInfo struct {
+	HasNextPage     bool   `json:"hasNextPage"`
+	HasPreviousPage bool   `json:"hasPreviousPage"`
+	StartCursor     string `json:"startCursor"`
+	EndCursor       string `json:"endCursor"`
+}
+
+// Edge represents an edge of a Relay style connection.
+type Edge[T any] struct {
+	Cursor string `json:"cursor"`
+	Node   T      `json:"node"`
+}
+
+// Connection represents a Relay style connection. Some servers, like
+// GitHub, allow the nodes to be selected directly instead of through the
+// edges so both are supported.
+type Connection[T any] struct {
+	Edges      []Edge[T] `json:"edges"`
+	Nodes      []T       `json:"nodes"`
+	PageInfo   PageInfo  `json:"pageInfo"`
+	TotalCount int       `json:"totalCount"`
+}
+
+// All returns the nodes of the connection, flattening the edges if the
+// nodes were selected through the edges.
+func (c Connection[T]) All() []T {
+	if len(c.Edges) == 0 {
+		return c.Nodes
+	}
+	return Nodes(c.Edges)
+}
+
+// Cursor returns the cursor to continue paging forward from and whether
+// there is a next page. This can be used to implement a CursorPager.
+func (c Connection[T]) Cursor() (string, bool) {
+	return c.PageInfo.EndCursor, c.PageInfo.HasNextPage
+}
+
+// Nodes flattens the edges into the list of nodes.
+func Nodes[T any](edges []Edge[T]) []T {
+	nodes := make([]T, len(edges))
+	for i, edge := range edges {
+		nodes[i] = edge.Node
+	}
+	return nodes
+}
@@ -0,0 +1,68 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConnection(t *testing.T) {
+	type issue struct {
+		Title string `json:"title"`
+	}
+
+	type response struct {
+		Issues graphql.Connection[issue] `json:"issues"`
+	}
+
+	t.Log("Given the need to decode Relay style connections.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the nodes are selected through the edges.", testID)
+		{
+			data := `{"issues": {
+				"edges": [
+					{"cursor": "c1", "node": {"title": "first"}},
+					{"cursor": "c2", "node": {"title": "second"}}
+				],
+				"pageInfo": {"hasNextPage": true, "endCursor": "c2"},
+				"totalCount": 10
+			}}`
+
+			var got response
+			if err := json.Unmarshal([]byte(data), &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to decode the connection: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to decode the connection.", success, testID)
+
+			exp := []issue{{Title: "first"}, {Title: "second"}}
+			if diff := cmp.Diff(got.Issues.All(), exp); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould flatten the edges. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould flatten the edges.", success, testID)
+
+			if cursor, next := got.Issues.Cursor(); cursor != "c2" || !next {
+				t.Fatalf("\t%s\tTest %d:\tShould get the cursor for the next page: %s %v", failed, testID, cursor, next)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the cursor for the next page.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the nodes are selected directly.", testID)
+		{
+			data := `{"issues": {"nodes": [{"title": "only"}], "pageInfo": {"hasNextPage": false}}}`
+
+			var got response
+			if err := json.Unmarshal([]byte(data), &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to decode the connection: %v", failed, testID, err)
+			}
+
+			if diff := cmp.Diff(got.Issues.All(), []issue{{Title: "only"}}); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould return the nodes. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould return the nodes.", success, testID)
+		}
+	}
+}
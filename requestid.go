@@ -0,0 +1,32 @@
+package graphql
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// WithRequestID generates a unique id for every request and sends it in the
+// specified header, or X-Request-Id if the header is empty. The id is
+// included in log messages and errors and is available to callers through
+// CaptureResponse, so client failures can be correlated with host logs.
+func WithRequestID(header string) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		if header == "" {
+			header = "X-Request-Id"
+		}
+		gql.requestIDHeader = header
+	}
+}
+
+// newRequestID generates a random version 4 UUID.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,62 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestRequestID(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	t.Log("Given the need to correlate requests with the host's logs.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the host returns an error.", testID)
+		{
+			var sent string
+			f := func(w http.ResponseWriter, r *http.Request) {
+				sent = r.Header.Get("X-Correlation-Id")
+				io.WriteString(w, `{"errors": [{"message": "forced"}]}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			var logged string
+			gql := graphql.New(server.URL,
+				graphql.WithRequestID("X-Correlation-Id"),
+				graphql.WithLogging(func(s string) { logged = s }),
+			)
+
+			var info graphql.ResponseInfo
+			ctx := graphql.CaptureResponse(context.Background(), &info)
+			err := gql.Execute(ctx, `query { __typename }`, nil)
+			if err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get an error.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an error.", success, testID)
+
+			if !uuid.MatchString(sent) {
+				t.Fatalf("\t%s\tTest %d:\tShould send a UUID: %q", failed, testID, sent)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send a UUID.", success, testID)
+
+			if info.RequestID != sent {
+				t.Fatalf("\t%s\tTest %d:\tShould expose the request id: %q", failed, testID, info.RequestID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould expose the request id.", success, testID)
+
+			if !strings.Contains(err.Error(), "request_id:["+sent+"]") || !strings.Contains(logged, "request_id:["+sent+"]") {
+				t.Fatalf("\t%s\tTest %d:\tShould include the request id in the error and log: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould include the request id in the error and log.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// ResponseInfo holds the metadata of the http response received for a
+// call, like the request id, rate limit and Dgraph latency headers, and
+// the extensions of the graphql response. RequestID is set when request
+// ids are enabled with WithRequestID.
+type ResponseInfo struct {
+	StatusCode int
+	Header     http.Header
+	Extensions json.RawMessage
+	RequestID  string
+}
+
+// CaptureResponse returns a copy of the context that captures the metadata
+// of the response received for the call made with it into the specified
+// value. The value is left untouched if no response is received, which is
+// the case for a response served from the cache.
+func CaptureResponse(ctx context.Context, info *ResponseInfo) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.responseInfo = info
+	})
+}
@@ -0,0 +1,73 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestCaptureResponse(t *testing.T) {
+	t.Log("Given the need to access the metadata of a response.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen executing a query.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-Request-Id", "abc123")
+				io.WriteString(w, `{"data": {"__typename": "Query"}, "extensions": {"touched_uids": 4}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			var info graphql.ResponseInfo
+			ctx := graphql.CaptureResponse(context.Background(), &info)
+			if err := gql.Execute(ctx, `query { __typename }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			if info.StatusCode != http.StatusOK || info.Header.Get("X-Request-Id") != "abc123" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the response headers: %+v", failed, testID, info)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the response headers.", success, testID)
+
+			if string(info.Extensions) != `{"touched_uids": 4}` {
+				t.Fatalf("\t%s\tTest %d:\tShould get the extensions: %s", failed, testID, info.Extensions)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the extensions.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the host rejects the request.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("X-RateLimit-Remaining", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			var info graphql.ResponseInfo
+			ctx := graphql.CaptureResponse(context.Background(), &info)
+			if err := gql.Execute(ctx, `query { __typename }`, nil); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get an error.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an error.", success, testID)
+
+			if info.StatusCode != http.StatusTooManyRequests || info.Header.Get("X-RateLimit-Remaining") != "0" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the response headers: %+v", failed, testID, info)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the response headers.", success, testID)
+		}
+	}
+}
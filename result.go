@@ -0,0 +1,54 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Result holds the data of a response in generic form alongside the value
+// it was decoded into, so fields not modeled in the struct are still
+// available for auditing and forwarding.
+type Result struct {
+	Data       map[string]interface{}
+	Raw        json.RawMessage
+	Extensions json.RawMessage
+}
+
+// ExecuteInto performs a graphql request against the configured host on the
+// url/graphql endpoint. The data of the response is decoded into the
+// response value, which may be nil, and into the returned Result.
+func (g *GraphQL) ExecuteInto(ctx context.Context, graphql string, response interface{}, variables ...func(m map[string]interface{})) (*Result, error) {
+	var queryVars map[string]interface{}
+	if len(variables) > 0 {
+		queryVars = make(map[string]interface{})
+		for _, variable := range variables {
+			variable(queryVars)
+		}
+	}
+
+	var result Result
+	ctx = withCallOption(ctx, func(opts *callOptions) {
+		opts.extensions = &result.Extensions
+	})
+
+	if err := g.query(ctx, g.graphqlPath, graphql, queryVars, &result.Raw); err != nil {
+		return nil, err
+	}
+
+	if len(result.Raw) == 0 {
+		return &result, nil
+	}
+
+	if err := json.Unmarshal(result.Raw, &result.Data); err != nil {
+		return nil, fmt.Errorf("graphql decoding error: %w response: %s", err, excerpt(result.Raw, g.errorBodyLimit))
+	}
+
+	if response != nil {
+		if err := decodeData(result.Raw, response, g.decodeHooksFor(ctx), g.errorBodyLimit); err != nil {
+			return nil, err
+		}
+	}
+
+	return &result, nil
+}
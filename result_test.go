@@ -0,0 +1,56 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestExecuteInto(t *testing.T) {
+	t.Log("Given the need to keep the fields a struct doesn't model.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the response has more fields than the struct.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"data": {"city": {"name": "Miami", "lat": 25.76}}, "extensions": {"cost": 3}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			var resp struct {
+				City struct {
+					Name string `json:"name"`
+				} `json:"city"`
+			}
+			result, err := gql.ExecuteInto(context.Background(), `query { city { name lat } }`, &resp)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			if resp.City.Name != "Miami" {
+				t.Fatalf("\t%s\tTest %d:\tShould decode into the struct: %+v", failed, testID, resp)
+			}
+			t.Logf("\t%s\tTest %d:\tShould decode into the struct.", success, testID)
+
+			city, _ := result.Data["city"].(map[string]interface{})
+			if city["lat"] != 25.76 {
+				t.Fatalf("\t%s\tTest %d:\tShould decode into the map: %+v", failed, testID, result.Data)
+			}
+			t.Logf("\t%s\tTest %d:\tShould decode into the map.", success, testID)
+
+			if string(result.Raw) != `{"city": {"name": "Miami", "lat": 25.76}}` || string(result.Extensions) != `{"cost": 3}` {
+				t.Fatalf("\t%s\tTest %d:\tShould keep the raw data and extensions: %s %s", failed, testID, result.Raw, result.Extensions)
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep the raw data and extensions.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WithRetries enables retrying requests the host rejects with a 429 or 503
+// status code. If the host provides a Retry-After header that delay is
+// respected, else the delay starts at the backoff duration and doubles on
+// every attempt. A Retry-After delay longer than the delay of the last
+// attempt isn't waited for, and the request fails with an *HTTPError that
+// holds the delay in RetryAfter, so a host can't stall the client.
+func WithRetries(maxRetries int, backoff time.Duration) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.maxRetries = maxRetries
+		gql.backoff = backoff
+	}
+}
+
+// sendWithRetry executes the http request, retrying the request when the
+// host asks the client to slow down or is temporarily unavailable.
+func (g *GraphQL) sendWithRetry(ctx context.Context, endpoint string, r io.Reader) (*Response, error) {
+
+	// The request body must be buffered so it can be sent more than once.
+	body, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("graphql read request error: %w", err)
+	}
+
+	backoff := g.backoff
+	for attempt := 0; ; attempt++ {
+		resp, err := g.sendOnce(ctx, endpoint, bytes.NewReader(body))
+		if err != nil || attempt == g.maxRetries || !retryableStatus(resp.StatusCode) {
+			return resp, err
+		}
+
+		delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		switch {
+		case !ok:
+			delay = backoff
+			backoff *= 2
+		case delay > g.maxBackoff():
+			return resp, nil
+		}
+
+		resp.Body.Close()
+
+		if err := sleep(ctx, delay); err != nil {
+			return nil, fmt.Errorf("graphql request error: %w", err)
+		}
+	}
+}
+
+// maxBackoff returns the delay before the last attempt when the backoff
+// doubles on every attempt.
+func (g *GraphQL) maxBackoff() time.Duration {
+	max := g.backoff
+	for i := 1; i < g.maxRetries && max < math.MaxInt64/2; i++ {
+		max *= 2
+	}
+	return max
+}
+
+// retryableStatus reports whether the status code indicates the request
+// can be sent again.
+func retryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+}
+
+// parseRetryAfter parses the value of a Retry-After header which can be
+// provided in seconds or as an http date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+	return 0, true
+}
+
+// sleep waits for the specified duration or until the context is canceled.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
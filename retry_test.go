@@ -0,0 +1,117 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestRetryAfter(t *testing.T) {
+	type response struct {
+		Name string `json:"name"`
+	}
+
+	t.Log("Given the need to respect a host asking the client to slow down.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen retries are enabled.", testID)
+		{
+			var calls int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&calls, 1) == 1 {
+					w.Header().Set("Retry-After", "0")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				io.WriteString(w, `{"data": {"name": "retried"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithRetries(2, time.Hour))
+
+			var got response
+			if err := gql.Execute(context.Background(), `query { name }`, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			if got.Name != "retried" || atomic.LoadInt32(&calls) != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould get the result after one retry: %q", failed, testID, got.Name)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the result after one retry.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen retries are not enabled.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "2")
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			var got response
+			err := gql.Execute(context.Background(), `query { name }`, &got)
+
+			var httpErr *graphql.HTTPError
+			if !errors.As(err, &httpErr) {
+				t.Fatalf("\t%s\tTest %d:\tShould get an HTTPError: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an HTTPError.", success, testID)
+
+			if httpErr.StatusCode != http.StatusServiceUnavailable || httpErr.RetryAfter != 2*time.Second {
+				t.Fatalf("\t%s\tTest %d:\tShould get the parsed Retry-After: %+v", failed, testID, httpErr)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the parsed Retry-After.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the Retry-After is longer than the maximum backoff.", testID)
+		{
+			var calls int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&calls, 1)
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusTooManyRequests)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithRetries(3, 10*time.Millisecond))
+
+			done := make(chan error, 1)
+			go func() {
+				var got response
+				done <- gql.Execute(context.Background(), `query { name }`, &got)
+			}()
+
+			var err error
+			select {
+			case err = <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("\t%s\tTest %d:\tShould not wait for the Retry-After.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not wait for the Retry-After.", success, testID)
+
+			var httpErr *graphql.HTTPError
+			if !errors.As(err, &httpErr) || httpErr.RetryAfter != time.Hour || atomic.LoadInt32(&calls) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould get an HTTPError with the parsed Retry-After: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an HTTPError with the parsed Retry-After.", success, testID)
+		}
+	}
+}
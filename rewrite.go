@@ -0,0 +1,33 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+)
+
+// QueryRewriter receives the document and variables of a graphql request
+// before it's validated and sent, and returns the versions to use instead.
+// This allows tenant filters or directives like @cascade to be applied in
+// one place rather than at every call site. The variables map belongs to
+// the caller, so return a modified copy rather than changing it.
+type QueryRewriter func(ctx context.Context, graphql string, variables map[string]interface{}) (string, map[string]interface{}, error)
+
+// WithQueryRewriter adds rewriters that are applied to every graphql
+// request in the order they are provided. Caching, fingerprints, and
+// operation labels are based on the rewritten document.
+func WithQueryRewriter(rewriters ...QueryRewriter) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.rewriters = append(gql.rewriters, rewriters...)
+	}
+}
+
+// rewrite applies the configured rewriters to the document and variables.
+func (g *GraphQL) rewrite(ctx context.Context, graphql string, queryVars map[string]interface{}) (string, map[string]interface{}, error) {
+	for _, rewriter := range g.rewriters {
+		var err error
+		if graphql, queryVars, err = rewriter(ctx, graphql, queryVars); err != nil {
+			return "", nil, fmt.Errorf("graphql rewrite error: %w", err)
+		}
+	}
+	return graphql, queryVars, nil
+}
@@ -0,0 +1,77 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestQueryRewriter(t *testing.T) {
+	t.Log("Given the need to rewrite queries before they are sent.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a rewriter injects a tenant variable and directive.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query     string                 `json:"query"`
+					Variables map[string]interface{} `json:"variables"`
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+
+				if !strings.Contains(req.Query, "@cascade") || req.Variables["tenant"] != "acme" || req.Variables["name"] != "Miami" {
+					t.Fatalf("\t%s\tTest %d:\tShould send the rewritten request: %+v", failed, testID, req)
+				}
+				t.Logf("\t%s\tTest %d:\tShould send the rewritten request.", success, testID)
+
+				io.WriteString(w, `{"data": {"queryCity": []}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			tenant := func(ctx context.Context, graphql string, vars map[string]interface{}) (string, map[string]interface{}, error) {
+				out := map[string]interface{}{"tenant": "acme"}
+				for k, v := range vars {
+					out[k] = v
+				}
+				return graphql, out, nil
+			}
+			cascade := func(ctx context.Context, graphql string, vars map[string]interface{}) (string, map[string]interface{}, error) {
+				return strings.Replace(graphql, "queryCity", "queryCity @cascade", 1), vars, nil
+			}
+
+			gql := graphql.New(server.URL, graphql.WithQueryRewriter(tenant, cascade))
+
+			err := gql.Execute(context.Background(), `query($name: String) { queryCity { name } }`, nil, graphql.WithVariable("name", "Miami"))
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a rewriter rejects the request.", testID)
+		{
+			errNoTenant := errors.New("no tenant")
+			reject := func(ctx context.Context, graphql string, vars map[string]interface{}) (string, map[string]interface{}, error) {
+				return "", nil, errNoTenant
+			}
+
+			gql := graphql.New("http://127.0.0.1:0", graphql.WithQueryRewriter(reject))
+
+			err := gql.Execute(context.Background(), `query { queryCity { name } }`, nil)
+			if !errors.Is(err, errNoTenant) {
+				t.Fatalf("\t%s\tTest %d:\tShould return the rewriter error: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould return the rewriter error.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ID represents a value of the graphql ID scalar. It's sent as a string,
+// but accepts both string and numeric JSON when decoded since servers
+// differ in how they encode IDs.
+type ID string
+
+// IntID constructs an ID from an integer.
+func IntID(id int64) ID {
+	return ID(strconv.FormatInt(id, 10))
+}
+
+// String returns the ID as a string.
+func (id ID) String() string {
+	return string(id)
+}
+
+// Int64 returns the ID as an integer for servers that use numeric IDs.
+func (id ID) Int64() (int64, error) {
+	n, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("graphql id error: %q is not an integer", string(id))
+	}
+	return n, nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (id *ID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*id = ID(s)
+		return nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("graphql id error: %s is not a string or number", string(data))
+	}
+	*id = ID(n)
+	return nil
+}
+
+// =============================================================================
+
+// Int64 represents a value of a 64-bit integer scalar, like Dgraph's Int64,
+// which loses precision when decoded as a float64. It decodes from a JSON
+// number or string and by default encodes as a number. Use Quoted for
+// servers that require a string. A decoded value encodes the way it was
+// received.
+type Int64 struct {
+	value  int64
+	quoted bool
+}
+
+// NewInt64 constructs an Int64 that encodes as a number.
+func NewInt64(n int64) Int64 {
+	return Int64{value: n}
+}
+
+// Int64 returns the value.
+func (i Int64) Int64() int64 {
+	return i.value
+}
+
+// Quoted returns a copy that encodes as a string.
+func (i Int64) Quoted() Int64 {
+	i.quoted = true
+	return i
+}
+
+// String returns the value in base 10.
+func (i Int64) String() string {
+	return strconv.FormatInt(i.value, 10)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (i Int64) MarshalJSON() ([]byte, error) {
+	return marshalNumber(i.String(), i.quoted), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (i *Int64) UnmarshalJSON(data []byte) error {
+	s, quoted, ok, err := unmarshalNumber(data)
+	if err != nil || !ok {
+		return err
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("graphql number error: %q is not a 64-bit integer", s)
+	}
+
+	*i = Int64{value: n, quoted: quoted}
+	return nil
+}
+
+// =============================================================================
+
+// BigInt represents a value of an arbitrary precision integer scalar. It
+// decodes from a JSON number or string and by default encodes as a number.
+// Use Quoted for servers that require a string. A decoded value encodes the
+// way it was received. The zero value is 0.
+type BigInt struct {
+	value  *big.Int
+	quoted bool
+}
+
+// NewBigInt constructs a BigInt with a copy of the value that encodes as a
+// number.
+func NewBigInt(n *big.Int) BigInt {
+	return BigInt{value: new(big.Int).Set(n)}
+}
+
+// ParseBigInt constructs a BigInt from its base 10 form.
+func ParseBigInt(s string) (BigInt, error) {
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return BigInt{}, fmt.Errorf("graphql number error: %q is not an integer", s)
+	}
+	return BigInt{value: n}, nil
+}
+
+// Int returns a copy of the value.
+func (b BigInt) Int() *big.Int {
+	if b.value == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(b.value)
+}
+
+// Quoted returns a copy that encodes as a string.
+func (b BigInt) Quoted() BigInt {
+	b.quoted = true
+	return b
+}
+
+// String returns the value in base 10.
+func (b BigInt) String() string {
+	if b.value == nil {
+		return "0"
+	}
+	return b.value.String()
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	return marshalNumber(b.String(), b.quoted), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *BigInt) UnmarshalJSON(data []byte) error {
+	s, quoted, ok, err := unmarshalNumber(data)
+	if err != nil || !ok {
+		return err
+	}
+
+	n, err := ParseBigInt(s)
+	if err != nil {
+		return err
+	}
+
+	n.quoted = quoted
+	*b = n
+	return nil
+}
+
+// =============================================================================
+
+// Decimal represents a value of a decimal scalar, like BigDecimal, which
+// loses precision when decoded as a float64. The value is kept exactly as
+// written. It decodes from a JSON number or string and by default encodes
+// as a number. Use Quoted for servers that require a string. A decoded
+// value encodes the way it was received. The zero value is 0.
+type Decimal struct {
+	value  string
+	quoted bool
+}
+
+// ParseDecimal constructs a Decimal from its decimal form, like 12.50 or
+// 1.5e-8.
+func ParseDecimal(s string) (Decimal, error) {
+	if !json.Valid([]byte(s)) || len(s) == 0 || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) {
+		return Decimal{}, fmt.Errorf("graphql number error: %q is not a decimal", s)
+	}
+	return Decimal{value: s}, nil
+}
+
+// Rat returns the value as a rational number.
+func (d Decimal) Rat() *big.Rat {
+	r, ok := new(big.Rat).SetString(d.String())
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+// Float64 returns the nearest float64 to the value.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// Quoted returns a copy that encodes as a string.
+func (d Decimal) Quoted() Decimal {
+	d.quoted = true
+	return d
+}
+
+// String returns the value as it was written.
+func (d Decimal) String() string {
+	if d.value == "" {
+		return "0"
+	}
+	return d.value
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return marshalNumber(d.String(), d.quoted), nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s, quoted, ok, err := unmarshalNumber(data)
+	if err != nil || !ok {
+		return err
+	}
+
+	v, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+
+	v.quoted = quoted
+	*d = v
+	return nil
+}
+
+// =============================================================================
+
+// marshalNumber encodes the number as a JSON number or string.
+func marshalNumber(s string, quoted bool) []byte {
+	if quoted {
+		return []byte(strconv.Quote(s))
+	}
+	return []byte(s)
+}
+
+// unmarshalNumber returns the number in the JSON number or string and
+// whether it was a string. It returns false for null, which leaves the
+// value unchanged.
+func unmarshalNumber(data []byte) (string, bool, bool, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return "", false, false, nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return "", false, false, err
+		}
+		return strings.TrimSpace(s), true, true, nil
+	}
+
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return "", false, false, fmt.Errorf("graphql number error: %s is not a number", string(data))
+	}
+	return n.String(), false, true, nil
+}
@@ -0,0 +1,127 @@
+package graphql_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestID(t *testing.T) {
+	t.Log("Given the need to handle IDs encoded as strings or numbers.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen decoding string and numeric IDs.", testID)
+		{
+			var got struct {
+				A graphql.ID  `json:"a"`
+				B graphql.ID  `json:"b"`
+				C *graphql.ID `json:"c"`
+			}
+			if err := json.Unmarshal([]byte(`{"a": "0x1f", "b": 12345678901234567, "c": null}`), &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to decode the IDs: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to decode the IDs.", success, testID)
+
+			if got.A != "0x1f" || got.B != "12345678901234567" || got.C != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get the expected IDs: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the expected IDs.", success, testID)
+
+			if n, err := got.B.Int64(); err != nil || n != 12345678901234567 {
+				t.Fatalf("\t%s\tTest %d:\tShould convert the ID to an integer: %d %v", failed, testID, n, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould convert the ID to an integer.", success, testID)
+
+			if err := json.Unmarshal([]byte(`{"a": true}`), &got); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould reject other JSON values.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject other JSON values.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen encoding an ID.", testID)
+		{
+			b, err := json.Marshal(map[string]graphql.ID{"id": graphql.IntID(42)})
+			if err != nil || string(b) != `{"id":"42"}` {
+				t.Fatalf("\t%s\tTest %d:\tShould encode the ID as a string: %s %v", failed, testID, b, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould encode the ID as a string.", success, testID)
+		}
+	}
+}
+
+func TestNumbers(t *testing.T) {
+	t.Log("Given the need to handle numbers that overflow a float64.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen decoding numbers and strings.", testID)
+		{
+			var got struct {
+				A graphql.Int64   `json:"a"`
+				B graphql.Int64   `json:"b"`
+				C graphql.BigInt  `json:"c"`
+				D graphql.Decimal `json:"d"`
+				E graphql.Decimal `json:"e"`
+			}
+			data := `{"a": 9007199254740993, "b": "-9007199254740993", "c": 123456789012345678901234567890, "d": "0.10000000000000000001", "e": 1.50}`
+			if err := json.Unmarshal([]byte(data), &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to decode the numbers: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to decode the numbers.", success, testID)
+
+			if got.A.Int64() != 9007199254740993 || got.B.Int64() != -9007199254740993 {
+				t.Fatalf("\t%s\tTest %d:\tShould keep the precision of the integers: %v %v", failed, testID, got.A, got.B)
+			}
+			if got.C.String() != "123456789012345678901234567890" || got.D.String() != "0.10000000000000000001" {
+				t.Fatalf("\t%s\tTest %d:\tShould keep the precision of the big numbers: %v %v", failed, testID, got.C, got.D)
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep the precision of the numbers.", success, testID)
+
+			b, err := json.Marshal(got)
+			exp := `{"a":9007199254740993,"b":"-9007199254740993","c":123456789012345678901234567890,"d":"0.10000000000000000001","e":1.50}`
+			if diff := cmp.Diff(string(b), exp); err != nil || diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould encode the numbers the way they were received: %v Diff:\n%s", failed, testID, err, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould encode the numbers the way they were received.", success, testID)
+
+			if err := json.Unmarshal([]byte(`{"a": 1.5}`), &got); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould reject a fraction for an integer.", failed, testID)
+			}
+			if err := json.Unmarshal([]byte(`{"d": "abc"}`), &got); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould reject a string that isn't a number.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject invalid numbers.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen encoding numbers as strings.", testID)
+		{
+			n, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+			d, err := graphql.ParseDecimal("12.50")
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to parse the decimal: %v", failed, testID, err)
+			}
+
+			vars := map[string]interface{}{
+				"a": graphql.NewInt64(42).Quoted(),
+				"b": graphql.NewBigInt(n).Quoted(),
+				"c": d.Quoted(),
+				"d": d,
+			}
+			b, err := json.Marshal(vars)
+			exp := `{"a":"42","b":"123456789012345678901234567890","c":"12.50","d":12.50}`
+			if diff := cmp.Diff(string(b), exp); err != nil || diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould encode the quoted numbers as strings: %v Diff:\n%s", failed, testID, err, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould encode the quoted numbers as strings.", success, testID)
+
+			if d.Rat().Cmp(big.NewRat(25, 2)) != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould convert the decimal to a rational: %v", failed, testID, d.Rat())
+			}
+			t.Logf("\t%s\tTest %d:\tShould convert the decimal to a rational.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,352 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// SchemaChangeKind identifies the kind of change found between two schemas.
+type SchemaChangeKind int
+
+// Set of schema change kinds.
+const (
+	TypeAdded SchemaChangeKind = iota + 1
+	TypeRemoved
+	TypeKindChanged
+	FieldAdded
+	FieldRemoved
+	FieldTypeChanged
+	ArgumentAdded
+	ArgumentRemoved
+	ArgumentTypeChanged
+	EnumValueAdded
+	EnumValueRemoved
+	UnionMemberAdded
+	UnionMemberRemoved
+)
+
+// String implements the fmt.Stringer interface.
+func (k SchemaChangeKind) String() string {
+	switch k {
+	case TypeAdded:
+		return "type added"
+	case TypeRemoved:
+		return "type removed"
+	case TypeKindChanged:
+		return "type kind changed"
+	case FieldAdded:
+		return "field added"
+	case FieldRemoved:
+		return "field removed"
+	case FieldTypeChanged:
+		return "field type changed"
+	case ArgumentAdded:
+		return "argument added"
+	case ArgumentRemoved:
+		return "argument removed"
+	case ArgumentTypeChanged:
+		return "argument type changed"
+	case EnumValueAdded:
+		return "enum value added"
+	case EnumValueRemoved:
+		return "enum value removed"
+	case UnionMemberAdded:
+		return "union member added"
+	case UnionMemberRemoved:
+		return "union member removed"
+	}
+	return "unknown"
+}
+
+// SchemaChange represents a single difference between two schemas. Path
+// identifies the element that changed, like User.email or
+// Query.getUser(id). Breaking is set when the change can break existing
+// clients.
+type SchemaChange struct {
+	Kind     SchemaChangeKind
+	Path     string
+	Before   string
+	After    string
+	Breaking bool
+}
+
+// String implements the fmt.Stringer interface.
+func (c SchemaChange) String() string {
+	s := fmt.Sprintf("%s: %s", c.Kind, c.Path)
+	switch {
+	case c.Before != "" && c.After != "":
+		s += fmt.Sprintf(" (%s -> %s)", c.Before, c.After)
+	case c.Before != "":
+		s += fmt.Sprintf(" (%s)", c.Before)
+	case c.After != "":
+		s += fmt.Sprintf(" (%s)", c.After)
+	}
+	if c.Breaking {
+		s += " [breaking]"
+	}
+	return s
+}
+
+// SchemaChanges represents the set of differences between two schemas.
+type SchemaChanges []SchemaChange
+
+// Breaking returns the changes that can break existing clients.
+func (cs SchemaChanges) Breaking() SchemaChanges {
+	var breaking SchemaChanges
+	for _, c := range cs {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// FetchDgraphSchema retrieves the GraphQL schema deployed on a Dgraph host
+// from the url/admin endpoint. An empty string is returned if no schema
+// has been deployed.
+func (g *GraphQL) FetchDgraphSchema(ctx context.Context) (string, error) {
+	var response struct {
+		GetGQLSchema *struct {
+			Schema string `json:"schema"`
+		} `json:"getGQLSchema"`
+	}
+
+	if err := g.ExecuteOnEndpoint(ctx, "admin", `query { getGQLSchema { schema } }`, &response); err != nil {
+		return "", err
+	}
+
+	if response.GetGQLSchema == nil {
+		return "", nil
+	}
+
+	return response.GetGQLSchema.Schema, nil
+}
+
+// SchemaDiff compares the schema deployed on a Dgraph host against the
+// specified local SDL. The changes describe what deploying the local SDL
+// would do to the deployed schema.
+func (g *GraphQL) SchemaDiff(ctx context.Context, sdl string) (SchemaChanges, error) {
+	deployed, err := g.FetchDgraphSchema(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return DiffSchemas(deployed, sdl)
+}
+
+// DiffSchemas compares two SDL documents and reports the changes needed to
+// go from the before schema to the after schema. The documents are parsed
+// but not validated, so schemas that rely on host provided directives and
+// scalars, like Dgraph's @search and DateTime, can be compared.
+func DiffSchemas(before string, after string) (SchemaChanges, error) {
+	b, err := parseSDL(before)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := parseSDL(after)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes SchemaChanges
+	for _, name := range sortedKeys(b, a) {
+		bt, at := b[name], a[name]
+
+		switch {
+		case at == nil:
+			changes = append(changes, SchemaChange{Kind: TypeRemoved, Path: name, Breaking: true})
+
+		case bt == nil:
+			changes = append(changes, SchemaChange{Kind: TypeAdded, Path: name})
+
+		case bt.Kind != at.Kind:
+			changes = append(changes, SchemaChange{Kind: TypeKindChanged, Path: name, Before: string(bt.Kind), After: string(at.Kind), Breaking: true})
+
+		default:
+			changes = append(changes, diffDefinition(bt, at)...)
+		}
+	}
+
+	return changes, nil
+}
+
+// parseSDL parses the SDL document and merges type extensions into their
+// definitions, returning the definitions by name.
+func parseSDL(sdl string) (map[string]*ast.Definition, error) {
+	doc, err := parser.ParseSchema(&ast.Source{Input: sdl})
+	if err != nil {
+		return nil, fmt.Errorf("graphql schema error: %w", err)
+	}
+
+	defs := make(map[string]*ast.Definition)
+	for _, def := range doc.Definitions {
+		d := *def
+		defs[def.Name] = &d
+	}
+
+	for _, ext := range doc.Extensions {
+		def, exists := defs[ext.Name]
+		if !exists {
+			d := *ext
+			defs[ext.Name] = &d
+			continue
+		}
+		def.Fields = append(append(ast.FieldList{}, def.Fields...), ext.Fields...)
+		def.EnumValues = append(append(ast.EnumValueList{}, def.EnumValues...), ext.EnumValues...)
+		def.Types = append(append([]string{}, def.Types...), ext.Types...)
+	}
+
+	return defs, nil
+}
+
+// diffDefinition compares two definitions of the same kind.
+func diffDefinition(before *ast.Definition, after *ast.Definition) []SchemaChange {
+	var changes []SchemaChange
+
+	input := before.Kind == ast.InputObject
+
+	bf, af := fieldsByName(before.Fields), fieldsByName(after.Fields)
+	for _, name := range sortedKeys(bf, af) {
+		path := before.Name + "." + name
+		b, a := bf[name], af[name]
+
+		switch {
+		case a == nil:
+			changes = append(changes, SchemaChange{Kind: FieldRemoved, Path: path, Before: b.Type.String(), Breaking: true})
+
+		case b == nil:
+			breaking := input && a.Type.NonNull && a.DefaultValue == nil
+			changes = append(changes, SchemaChange{Kind: FieldAdded, Path: path, After: a.Type.String(), Breaking: breaking})
+
+		default:
+			if b.Type.String() != a.Type.String() {
+				changes = append(changes, SchemaChange{Kind: FieldTypeChanged, Path: path, Before: b.Type.String(), After: a.Type.String(), Breaking: !safeTypeChange(b.Type, a.Type, input)})
+			}
+			changes = append(changes, diffArguments(path, b.Arguments, a.Arguments)...)
+		}
+	}
+
+	be, ae := namesOf(before.EnumValues), namesOf(after.EnumValues)
+	for _, name := range sortedKeys(be, ae) {
+		path := before.Name + "." + name
+		switch {
+		case !ae[name]:
+			changes = append(changes, SchemaChange{Kind: EnumValueRemoved, Path: path, Breaking: true})
+		case !be[name]:
+			changes = append(changes, SchemaChange{Kind: EnumValueAdded, Path: path})
+		}
+	}
+
+	bu, au := setOf(before.Types), setOf(after.Types)
+	for _, name := range sortedKeys(bu, au) {
+		path := before.Name + "." + name
+		switch {
+		case !au[name]:
+			changes = append(changes, SchemaChange{Kind: UnionMemberRemoved, Path: path, Breaking: true})
+		case !bu[name]:
+			changes = append(changes, SchemaChange{Kind: UnionMemberAdded, Path: path})
+		}
+	}
+
+	return changes
+}
+
+// diffArguments compares the arguments of a field.
+func diffArguments(field string, before ast.ArgumentDefinitionList, after ast.ArgumentDefinitionList) []SchemaChange {
+	ba := make(map[string]*ast.ArgumentDefinition)
+	for _, arg := range before {
+		ba[arg.Name] = arg
+	}
+	aa := make(map[string]*ast.ArgumentDefinition)
+	for _, arg := range after {
+		aa[arg.Name] = arg
+	}
+
+	var changes []SchemaChange
+	for _, name := range sortedKeys(ba, aa) {
+		path := fmt.Sprintf("%s(%s)", field, name)
+		b, a := ba[name], aa[name]
+
+		switch {
+		case a == nil:
+			changes = append(changes, SchemaChange{Kind: ArgumentRemoved, Path: path, Before: b.Type.String(), Breaking: true})
+
+		case b == nil:
+			breaking := a.Type.NonNull && a.DefaultValue == nil
+			changes = append(changes, SchemaChange{Kind: ArgumentAdded, Path: path, After: a.Type.String(), Breaking: breaking})
+
+		case b.Type.String() != a.Type.String():
+			changes = append(changes, SchemaChange{Kind: ArgumentTypeChanged, Path: path, Before: b.Type.String(), After: a.Type.String(), Breaking: !safeTypeChange(b.Type, a.Type, true)})
+		}
+	}
+
+	return changes
+}
+
+// safeTypeChange reports whether changing a type is safe for clients.
+// Output types can become non-null and input types can become nullable.
+func safeTypeChange(before *ast.Type, after *ast.Type, input bool) bool {
+	if input {
+		before, after = after, before
+	}
+
+	for before != nil && after != nil {
+		if before.NonNull != after.NonNull && before.NonNull {
+			return false
+		}
+		if before.NamedType != after.NamedType {
+			return false
+		}
+		before, after = before.Elem, after.Elem
+	}
+
+	return before == nil && after == nil
+}
+
+// fieldsByName indexes the fields by name.
+func fieldsByName(fields ast.FieldList) map[string]*ast.FieldDefinition {
+	m := make(map[string]*ast.FieldDefinition, len(fields))
+	for _, f := range fields {
+		m[f.Name] = f
+	}
+	return m
+}
+
+// namesOf returns the set of enum value names.
+func namesOf(values ast.EnumValueList) map[string]bool {
+	m := make(map[string]bool, len(values))
+	for _, v := range values {
+		m[v.Name] = true
+	}
+	return m
+}
+
+// setOf returns the set of strings.
+func setOf(values []string) map[string]bool {
+	m := make(map[string]bool, len(values))
+	for _, v := range values {
+		m[v] = true
+	}
+	return m
+}
+
+// sortedKeys returns the union of the keys of both maps in sorted order.
+func sortedKeys[V any](a map[string]V, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var keys []string
+	for _, m := range []map[string]V{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,128 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSchemaDiff(t *testing.T) {
+	const before = `
+type User {
+	id: ID!
+	name: String
+	email: String!
+	age: Int
+}
+
+input UserFilter {
+	name: String!
+}
+
+enum Role {
+	ADMIN
+	USER
+}
+
+type Query {
+	getUser(id: ID!): User
+}
+
+type Legacy {
+	id: ID!
+}`
+
+	const after = `
+type User {
+	id: ID!
+	name: String! @search(by: [hash])
+	email: String
+	created: DateTime
+}
+
+input UserFilter {
+	name: String
+	role: Role!
+}
+
+enum Role {
+	ADMIN
+	GUEST
+}
+
+type Query {
+	getUser(id: ID!, deleted: Boolean): User
+}`
+
+	t.Log("Given the need to compare schemas.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen comparing two SDL documents.", testID)
+		{
+			changes, err := graphql.DiffSchemas(before, after)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to compare the schemas: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to compare the schemas.", success, testID)
+
+			var got []string
+			for _, c := range changes {
+				got = append(got, c.String())
+			}
+
+			exp := []string{
+				"type removed: Legacy [breaking]",
+				"argument added: Query.getUser(deleted) (Boolean)",
+				"enum value added: Role.GUEST",
+				"enum value removed: Role.USER [breaking]",
+				"field removed: User.age (Int) [breaking]",
+				"field added: User.created (DateTime)",
+				"field type changed: User.email (String! -> String) [breaking]",
+				"field type changed: User.name (String -> String!)",
+				"field type changed: UserFilter.name (String! -> String)",
+				"field added: UserFilter.role (Role!) [breaking]",
+			}
+			if diff := cmp.Diff(got, exp); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the expected changes. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the expected changes.", success, testID)
+
+			if len(changes.Breaking()) != 5 {
+				t.Fatalf("\t%s\tTest %d:\tShould get the breaking changes: %v", failed, testID, changes.Breaking())
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the breaking changes.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen comparing against the deployed schema.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/admin" {
+					t.Fatalf("\t%s\tTest %d:\tShould request the admin endpoint: %s", failed, testID, r.URL.Path)
+				}
+				io.WriteString(w, `{"data": {"getGQLSchema": {"schema": "type User { id: ID! }"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			changes, err := gql.SchemaDiff(context.Background(), `type User { id: ID! name: String }`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to compare the schemas: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to compare the schemas.", success, testID)
+
+			if len(changes) != 1 || changes[0].Kind != graphql.FieldAdded || changes[0].Breaking {
+				t.Fatalf("\t%s\tTest %d:\tShould get the added field: %v", failed, testID, changes)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the added field.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,272 @@
+// Package seed loads JSON and NDJSON fixture files into a host using the
+// add mutations Dgraph generates for each type. Fixture records are checked
+// against the schema's input types before anything is sent, which makes
+// the package useful for preparing test environments and demos.
+package seed
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ardanlabs/graphql"
+)
+
+// Fixture represents the records loaded from a single fixture file. The
+// type is taken from the file name, which can be prefixed with a number to
+// control the load order, for example 01_User.json.
+type Fixture struct {
+	File    string
+	Type    string
+	Records []map[string]interface{}
+}
+
+// Load reads the .json and .ndjson fixture files found at the root of the
+// file system, sorted by file name. A .json file holds an array of records
+// or a single record and a .ndjson file holds one record per line.
+func Load(fsys fs.FS) ([]Fixture, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("graphql seed error: %w", err)
+	}
+
+	var fixtures []Fixture
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".ndjson") {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("graphql seed error: %w", err)
+		}
+
+		var records []map[string]interface{}
+		switch ext {
+		case ".json":
+			records, err = decodeJSON(data)
+		case ".ndjson":
+			records, err = decodeNDJSON(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("graphql seed error: %s: %w", entry.Name(), err)
+		}
+
+		fixtures = append(fixtures, Fixture{
+			File:    entry.Name(),
+			Type:    typeName(entry.Name()),
+			Records: records,
+		})
+	}
+
+	sort.Slice(fixtures, func(i, j int) bool {
+		return fixtures[i].File < fixtures[j].File
+	})
+
+	return fixtures, nil
+}
+
+// typeName extracts the type from the fixture file name, removing the
+// extension and any numeric ordering prefix.
+func typeName(file string) string {
+	name := strings.TrimSuffix(file, path.Ext(file))
+	if parts := strings.SplitN(name, "_", 2); len(parts) == 2 {
+		if _, err := strconv.Atoi(parts[0]); err == nil {
+			return parts[1]
+		}
+	}
+	return name
+}
+
+// decodeJSON decodes an array of records or a single record.
+func decodeJSON(data []byte) ([]map[string]interface{}, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var record map[string]interface{}
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, err
+		}
+		return []map[string]interface{}{record}, nil
+	}
+
+	var records []map[string]interface{}
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// decodeNDJSON decodes one record per line, skipping blank lines.
+func decodeNDJSON(data []byte) ([]map[string]interface{}, error) {
+	var records []map[string]interface{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		var record map[string]interface{}
+		if err := json.Unmarshal(text, &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// =============================================================================
+
+// Seeder loads fixtures into a host.
+type Seeder struct {
+	gql       *graphql.GraphQL
+	schema    *graphql.IntrospectionSchema
+	chunkSize int
+}
+
+// New constructs a Seeder that checks fixtures against the specified
+// schema, which can be retrieved with the Introspect method. Records are
+// sent in chunks of the specified size, or 1000 if the size is zero.
+func New(gql *graphql.GraphQL, schema *graphql.IntrospectionSchema, chunkSize int) *Seeder {
+	if chunkSize <= 0 {
+		chunkSize = 1000
+	}
+
+	return &Seeder{
+		gql:       gql,
+		schema:    schema,
+		chunkSize: chunkSize,
+	}
+}
+
+// Mutation returns the add mutation for the fixture's type. The records
+// are passed in the input variable.
+func (s *Seeder) Mutation(fixture Fixture) (string, error) {
+	field, input, err := s.addMutation(fixture.Type)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("mutation Seed%s($input: %s) { %s(input: $input) { numUids } }", fixture.Type, input.Type, field.Name), nil
+}
+
+// Check validates every record of the fixture against the input type of
+// the add mutation. Unknown fields and missing required fields are
+// reported with the record index and field path.
+func (s *Seeder) Check(fixture Fixture) error {
+	_, input, err := s.addMutation(fixture.Type)
+	if err != nil {
+		return err
+	}
+
+	for i, record := range fixture.Records {
+		if err := s.check(record, input.Type.NamedType(), ""); err != nil {
+			return fmt.Errorf("graphql seed error: %s: record %d: %w", fixture.File, i, err)
+		}
+	}
+
+	return nil
+}
+
+// Seed checks every fixture and then executes the add mutations in the
+// order of the fixtures. Nothing is sent if any fixture fails the check.
+func (s *Seeder) Seed(ctx context.Context, fixtures ...Fixture) error {
+	for _, fixture := range fixtures {
+		if err := s.Check(fixture); err != nil {
+			return err
+		}
+	}
+
+	for _, fixture := range fixtures {
+		mutation, err := s.Mutation(fixture)
+		if err != nil {
+			return err
+		}
+
+		if err := graphql.BulkMutate(ctx, s.gql, mutation, fixture.Records, graphql.WithBulkChunkSize(s.chunkSize), graphql.WithBulkWorkers(1)); err != nil {
+			return fmt.Errorf("graphql seed error: %s: %w", fixture.File, err)
+		}
+	}
+
+	return nil
+}
+
+// addMutation finds the add mutation for the type and its input argument.
+func (s *Seeder) addMutation(typ string) (graphql.IntrospectionField, graphql.IntrospectionInputValue, error) {
+	if s.schema.MutationType == nil {
+		return graphql.IntrospectionField{}, graphql.IntrospectionInputValue{}, fmt.Errorf("graphql seed error: schema has no mutation type")
+	}
+
+	mutation, _ := s.schema.Type(s.schema.MutationType.Name)
+	field, exists := mutation.Field("add" + typ)
+	if !exists {
+		return graphql.IntrospectionField{}, graphql.IntrospectionInputValue{}, fmt.Errorf("graphql seed error: no add mutation for type %s", typ)
+	}
+
+	for _, arg := range field.Args {
+		if arg.Name == "input" {
+			return field, arg, nil
+		}
+	}
+
+	return graphql.IntrospectionField{}, graphql.IntrospectionInputValue{}, fmt.Errorf("graphql seed error: add%s has no input argument", typ)
+}
+
+// check validates the record against the named input type, descending
+// into nested input objects.
+func (s *Seeder) check(record map[string]interface{}, typ string, prefix string) error {
+	input, exists := s.schema.Type(typ)
+	if !exists || input.Kind != "INPUT_OBJECT" {
+		return fmt.Errorf("%s is not an input type", typ)
+	}
+
+	fields := make(map[string]graphql.IntrospectionInputValue, len(input.InputFields))
+	for _, f := range input.InputFields {
+		fields[f.Name] = f
+
+		if _, set := record[f.Name]; !set && f.Type.Kind == "NON_NULL" && f.DefaultValue == nil {
+			return fmt.Errorf("missing required field %s%s", prefix, f.Name)
+		}
+	}
+
+	for name, value := range record {
+		f, exists := fields[name]
+		if !exists {
+			return fmt.Errorf("unknown field %s%s for %s", prefix, name, typ)
+		}
+
+		nested, _ := s.schema.Type(f.Type.NamedType())
+		if nested.Kind != "INPUT_OBJECT" {
+			continue
+		}
+
+		values, ok := value.([]interface{})
+		if !ok {
+			values = []interface{}{value}
+		}
+		for _, v := range values {
+			if m, ok := v.(map[string]interface{}); ok {
+				if err := s.check(m, nested.Name, prefix+name+"."); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package seed_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/ardanlabs/graphql/seed"
+)
+
+// Success and failure markers.
+const (
+	success = "\u2713"
+	failed  = "\u2717"
+)
+
+const schema = `{
+	"mutationType": {"name": "Mutation"},
+	"types": [
+		{"kind": "OBJECT", "name": "Mutation", "fields": [
+			{"name": "addUser", "args": [
+				{"name": "input", "type": {"kind": "NON_NULL", "ofType": {"kind": "LIST", "ofType": {"kind": "NON_NULL", "ofType": {"kind": "INPUT_OBJECT", "name": "AddUserInput"}}}}}
+			]}
+		]},
+		{"kind": "INPUT_OBJECT", "name": "AddUserInput", "inputFields": [
+			{"name": "name", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "String"}}},
+			{"name": "city", "type": {"kind": "INPUT_OBJECT", "name": "CityRef"}}
+		]},
+		{"kind": "INPUT_OBJECT", "name": "CityRef", "inputFields": [
+			{"name": "id", "type": {"kind": "SCALAR", "name": "ID"}},
+			{"name": "name", "type": {"kind": "SCALAR", "name": "String"}}
+		]}
+	]
+}`
+
+func TestSeed(t *testing.T) {
+	var is graphql.IntrospectionSchema
+	if err := json.Unmarshal([]byte(schema), &is); err != nil {
+		t.Fatalf("unable to decode schema: %v", err)
+	}
+
+	t.Log("Given the need to seed a host with fixtures.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen loading valid fixtures.", testID)
+		{
+			fsys := fstest.MapFS{
+				"01_User.json":   {Data: []byte(`[{"name": "Bill", "city": {"name": "Miami"}}, {"name": "Jack"}]`)},
+				"02_User.ndjson": {Data: []byte("{\"name\": \"Ale\"}\n\n{\"name\": \"Eli\"}\n")},
+			}
+
+			var queries []string
+			f := func(w http.ResponseWriter, r *http.Request) {
+				var req struct {
+					Query string
+				}
+				json.NewDecoder(r.Body).Decode(&req)
+				queries = append(queries, req.Query)
+
+				io.WriteString(w, `{"data": {"addUser": {"numUids": 2}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			fixtures, err := seed.Load(fsys)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to load the fixtures: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to load the fixtures.", success, testID)
+
+			if len(fixtures) != 2 || fixtures[0].Type != "User" || len(fixtures[1].Records) != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould get the fixture records: %+v", failed, testID, fixtures)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the fixture records.", success, testID)
+
+			s := seed.New(graphql.New(server.URL), &is, 0)
+			if err := s.Seed(context.Background(), fixtures...); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to seed the fixtures: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to seed the fixtures.", success, testID)
+
+			exp := "mutation SeedUser($input: [AddUserInput!]!) { addUser(input: $input) { numUids } }"
+			if len(queries) != 2 || queries[0] != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould send the add mutations: %v", failed, testID, queries)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the add mutations.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a fixture doesn't match the input type.", testID)
+		{
+			fixture := seed.Fixture{
+				File:    "User.json",
+				Type:    "User",
+				Records: []map[string]interface{}{{"name": "Bill", "city": map[string]interface{}{"zip": "33101"}}},
+			}
+
+			s := seed.New(graphql.New("http://localhost"), &is, 0)
+			err := s.Check(fixture)
+			if err == nil || !strings.Contains(err.Error(), "unknown field city.zip") {
+				t.Fatalf("\t%s\tTest %d:\tShould report the unknown field: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the unknown field.", success, testID)
+		}
+	}
+}
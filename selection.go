@@ -0,0 +1,146 @@
+package graphql
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SelectionSet generates the graphql selection set for the specified struct
+// value or type by reflecting over its fields so the query and the response
+// type can't drift apart. The field name is taken from the `graphql` tag if
+// provided, else the `json` tag, else the field name with its first letter
+// lowercased. The `graphql` tag is used verbatim so it can include arguments,
+// aliases, and directives, like `graphql:"getCity(id: $id)"`. A `graphql`
+// tag that starts with "..." produces an inline fragment. Fields tagged with
+// `json:"-"` or `graphql:"-"` are skipped and embedded structs are flattened.
+func SelectionSet(v interface{}) (string, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return "", fmt.Errorf("graphql selection error: nil value")
+	}
+
+	var b strings.Builder
+	if err := writeSelectionSet(&b, t, nil); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// BuildQuery generates a complete document for the operation using the
+// selection set of the specified struct value or type. The operation is the
+// text that comes before the selection set, like "query" or
+// "query GetCity($id: ID!)".
+func BuildQuery(operation string, v interface{}) (string, error) {
+	set, err := SelectionSet(v)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(operation) + " " + set, nil
+}
+
+var (
+	jsonUnmarshaler = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshaler = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// writeSelectionSet writes the selection set for the struct type. The path
+// tracks the struct types being visited to detect recursive types.
+func writeSelectionSet(b *strings.Builder, t reflect.Type, path []reflect.Type) error {
+	t = elemType(t)
+	if t.Kind() != reflect.Struct || isScalar(t) {
+		return fmt.Errorf("graphql selection error: %s is not a struct", t)
+	}
+
+	for _, p := range path {
+		if p == t {
+			return fmt.Errorf("graphql selection error: %s is recursive", t)
+		}
+	}
+	path = append(path, t)
+
+	b.WriteString("{")
+	if err := writeFields(b, t, path); err != nil {
+		return err
+	}
+	b.WriteString(" }")
+
+	return nil
+}
+
+// writeFields writes the selection for each field of the struct type.
+func writeFields(b *strings.Builder, t reflect.Type, path []reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		gqlTag, hasGQL := field.Tag.Lookup("graphql")
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if gqlTag == "-" || (!hasGQL && jsonName == "-") {
+			continue
+		}
+
+		if field.Anonymous && !hasGQL && jsonName == "" {
+			ft := elemType(field.Type)
+			if ft.Kind() == reflect.Struct && !isScalar(ft) {
+				if err := writeFields(b, ft, path); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		var name string
+		switch {
+		case hasGQL:
+			name = gqlTag
+		case jsonName != "":
+			name = jsonName
+		default:
+			r, n := utf8.DecodeRuneInString(field.Name)
+			name = string(unicode.ToLower(r)) + field.Name[n:]
+		}
+
+		b.WriteString(" ")
+		b.WriteString(name)
+
+		ft := elemType(field.Type)
+		if ft.Kind() == reflect.Struct && !isScalar(ft) {
+			b.WriteString(" ")
+			if err := writeSelectionSet(b, ft, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// elemType returns the underlying element type for pointers, slices,
+// arrays, and maps.
+func elemType(t reflect.Type) reflect.Type {
+	for {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			return t
+		}
+	}
+}
+
+// isScalar reports whether the type decodes itself and should be treated
+// as a leaf field, like time.Time.
+func isScalar(t reflect.Type) bool {
+	pt := reflect.PtrTo(t)
+	return pt.Implements(jsonUnmarshaler) || pt.Implements(textUnmarshaler)
+}
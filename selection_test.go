@@ -0,0 +1,66 @@
+package graphql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestSelectionSet(t *testing.T) {
+	type location struct {
+		Lat float64 `json:"lat"`
+		Lng float64 `json:"lng"`
+	}
+
+	type base struct {
+		ID string `json:"id"`
+	}
+
+	type city struct {
+		base
+		Name      string     `json:"name"`
+		Location  *location  `json:"location"`
+		Neighbors []location `graphql:"neighbors(first: 2)"`
+		Updated   time.Time  `json:"updated"`
+		Ignored   string     `json:"-"`
+		Country   string
+	}
+
+	type response struct {
+		GetCity city `graphql:"getCity(id: $id)"`
+	}
+
+	t.Log("Given the need to generate a query from a response type.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the type has nested and tagged fields.", testID)
+		{
+			got, err := graphql.BuildQuery("query GetCity($id: ID!)", response{})
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to build the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to build the query.", success, testID)
+
+			exp := `query GetCity($id: ID!) { getCity(id: $id) { id name location { lat lng } neighbors(first: 2) { lat lng } updated country } }`
+			if got != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould get the expected query:\n%s", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the expected query.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the type is recursive.", testID)
+		{
+			type node struct {
+				Name     string  `json:"name"`
+				Children []*node `json:"children"`
+			}
+
+			if _, err := graphql.SelectionSet(&node{}); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould reject the recursive type.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject the recursive type.", success, testID)
+		}
+	}
+}
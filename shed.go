@@ -0,0 +1,144 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrShed is matched by the errors of requests that were shed because the
+// client is under pressure.
+var ErrShed = errors.New("graphql load shed error")
+
+// Set of request priorities.
+const (
+	priorityLow    = -1
+	priorityNormal = 0
+	priorityHigh   = 1
+)
+
+// minShedSamples is the number of recent requests required before the
+// error rate is considered.
+const minShedSamples = 10
+
+// LowPriority returns a copy of the context that marks the call made with it
+// as low priority, like a background sync job. Low priority requests are
+// the first to be shed when load shedding is enabled.
+func LowPriority(ctx context.Context) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.priority = priorityLow
+	})
+}
+
+// HighPriority returns a copy of the context that marks the call made with
+// it as high priority. High priority requests are never shed.
+func HighPriority(ctx context.Context) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.priority = priorityHigh
+	})
+}
+
+// LoadShedConfig represents the thresholds at which requests are shed.
+// Low priority requests are shed when the number of requests in flight
+// reaches MaxInFlight or the rate of failed requests over the last Window
+// requests exceeds MaxErrorRate. Normal priority requests are shed when the
+// number of requests in flight reaches NormalMaxInFlight. A zero threshold
+// is disabled. Failures are requests that timed out, couldn't reach the
+// host, or got a status code that can be retried. Window defaults to 100.
+type LoadShedConfig struct {
+	MaxInFlight       int64
+	MaxErrorRate      float64
+	Window            int
+	NormalMaxInFlight int64
+}
+
+// WithLoadShedding sheds requests that are not high priority when the
+// client is under pressure, so background work backs off automatically
+// when interactive traffic is suffering. Shed requests fail immediately
+// with an error that matches ErrShed.
+func WithLoadShedding(cfg LoadShedConfig) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		if cfg.Window <= 0 {
+			cfg.Window = 100
+		}
+		gql.shedder = &shedder{
+			cfg:      cfg,
+			outcomes: make([]bool, cfg.Window),
+		}
+	}
+}
+
+// =============================================================================
+
+// shedder tracks the recent outcomes of requests and decides which
+// requests to shed.
+type shedder struct {
+	cfg LoadShedConfig
+
+	mu       sync.Mutex
+	outcomes []bool
+	next     int
+	samples  int
+	failures int
+}
+
+// shed returns an error when the request with the priority must be shed.
+func (s *shedder) shed(priority int, inFlight int64) error {
+	switch priority {
+	case priorityHigh:
+		return nil
+
+	case priorityNormal:
+		if s.cfg.NormalMaxInFlight > 0 && inFlight >= s.cfg.NormalMaxInFlight {
+			return fmt.Errorf("%w: %d requests in flight", ErrShed, inFlight)
+		}
+		return nil
+	}
+
+	if s.cfg.MaxInFlight > 0 && inFlight >= s.cfg.MaxInFlight {
+		return fmt.Errorf("%w: %d requests in flight", ErrShed, inFlight)
+	}
+
+	if s.cfg.MaxErrorRate > 0 {
+		if rate, ok := s.errorRate(); ok && rate > s.cfg.MaxErrorRate {
+			return fmt.Errorf("%w: error rate %.2f", ErrShed, rate)
+		}
+	}
+
+	return nil
+}
+
+// record adds the outcome of a request to the window.
+func (s *shedder) record(err error) {
+	failed := err != nil && IsRetryable(err) && !errors.Is(err, ErrShed)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.samples == len(s.outcomes) {
+		if s.outcomes[s.next] {
+			s.failures--
+		}
+	} else {
+		s.samples++
+	}
+
+	s.outcomes[s.next] = failed
+	if failed {
+		s.failures++
+	}
+	s.next = (s.next + 1) % len(s.outcomes)
+}
+
+// errorRate returns the rate of failures over the window once there are
+// enough samples.
+func (s *shedder) errorRate() (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.samples < minShedSamples && s.samples < len(s.outcomes) {
+		return 0, false
+	}
+	return float64(s.failures) / float64(s.samples), true
+}
@@ -0,0 +1,118 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestLoadShedding(t *testing.T) {
+	t.Log("Given the need to shed low priority requests under pressure.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen too many requests are in flight.", testID)
+		{
+			release := make(chan struct{})
+			f := func(w http.ResponseWriter, r *http.Request) {
+				<-release
+				io.WriteString(w, `{"data": {"ok": true}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithLoadShedding(graphql.LoadShedConfig{
+				MaxInFlight:       1,
+				NormalMaxInFlight: 2,
+			}))
+
+			done := make(chan error, 2)
+			go func() {
+				done <- gql.Execute(context.Background(), `{ ok }`, nil)
+			}()
+			for gql.Stats().InFlight == 0 {
+				runtime.Gosched()
+			}
+
+			err := gql.Execute(graphql.LowPriority(context.Background()), `{ ok }`, nil)
+			if !errors.Is(err, graphql.ErrShed) {
+				t.Fatalf("\t%s\tTest %d:\tShould shed the low priority request: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould shed the low priority request.", success, testID)
+
+			go func() {
+				done <- gql.Execute(context.Background(), `{ ok }`, nil)
+			}()
+			for gql.Stats().InFlight < 2 {
+				runtime.Gosched()
+			}
+
+			err = gql.Execute(context.Background(), `{ ok }`, nil)
+			if !errors.Is(err, graphql.ErrShed) {
+				t.Fatalf("\t%s\tTest %d:\tShould shed the normal priority request: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould shed the normal priority request.", success, testID)
+
+			go func() {
+				done <- gql.Execute(graphql.HighPriority(context.Background()), `{ ok }`, nil)
+			}()
+			for gql.Stats().InFlight < 3 {
+				runtime.Gosched()
+			}
+			t.Logf("\t%s\tTest %d:\tShould never shed the high priority request.", success, testID)
+
+			close(release)
+			for i := 0; i < 3; i++ {
+				if err := <-done; err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould complete the requests in flight: %v", failed, testID, err)
+				}
+			}
+
+			if got := gql.Stats(); got.Shed != 2 || got.Succeeded != 3 {
+				t.Fatalf("\t%s\tTest %d:\tShould count the shed requests: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould count the shed requests.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the error rate is too high.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithLoadShedding(graphql.LoadShedConfig{
+				MaxErrorRate: 0.5,
+				Window:       10,
+			}))
+
+			ctx := graphql.LowPriority(context.Background())
+			for i := 0; i < 10; i++ {
+				if err := gql.Execute(ctx, `{ ok }`, nil); errors.Is(err, graphql.ErrShed) {
+					t.Fatalf("\t%s\tTest %d:\tShould not shed before the window has enough samples.", failed, testID)
+				}
+			}
+
+			err := gql.Execute(ctx, `{ ok }`, nil)
+			if !errors.Is(err, graphql.ErrShed) {
+				t.Fatalf("\t%s\tTest %d:\tShould shed the low priority request: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould shed the low priority request.", success, testID)
+
+			err = gql.Execute(context.Background(), `{ ok }`, nil)
+			if errors.Is(err, graphql.ErrShed) {
+				t.Fatalf("\t%s\tTest %d:\tShould send the normal priority request: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the normal priority request.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RequestSigner signs a request before it's sent, typically by adding an
+// authorization header. The body is provided since signatures usually
+// cover it, and the signer must not read the request body itself.
+type RequestSigner func(ctx context.Context, req *Request, body []byte) error
+
+// WithRequestSigner sets the signer that signs every request, after all
+// other headers have been set. Each attempt to send a request is signed
+// separately.
+func WithRequestSigner(signer RequestSigner) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.signer = signer
+	}
+}
+
+// sign buffers the body of the request, adds the body digest, and signs
+// it. The digest is added first so signatures can cover its header.
+func (g *GraphQL) sign(ctx context.Context, req *Request) error {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return fmt.Errorf("graphql read request error: %w", err)
+		}
+		req.Body = bytes.NewReader(body)
+	}
+
+	if g.bodyHash != nil {
+		req.Header.Set(g.bodyHash.header, g.bodyHash.digest(body))
+	}
+
+	if g.signer != nil {
+		if err := g.signer(ctx, req, body); err != nil {
+			return fmt.Errorf("graphql signing error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// =============================================================================
+
+// SigV4 signs requests with AWS Signature Version 4, as required by AWS
+// AppSync APIs that use IAM authorization. Use its Sign method with
+// WithRequestSigner. Service is appsync for AppSync and SessionToken is
+// required for temporary credentials. Now can be set to control the time
+// of the signature.
+type SigV4 struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+	Service         string
+	Now             func() time.Time
+}
+
+// sigV4Algorithm identifies the signing algorithm.
+const sigV4Algorithm = "AWS4-HMAC-SHA256"
+
+// Sign adds the X-Amz-Date, X-Amz-Security-Token, and Authorization headers
+// to the request. Every header of the request is signed.
+func (s SigV4) Sign(ctx context.Context, req *Request, body []byte) error {
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now
+	if s.Now != nil {
+		now = s.Now
+	}
+	t := now().UTC()
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if s.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.SessionToken)
+	}
+
+	// The canonical headers include the host, which isn't part of the
+	// header map, and every header that is.
+	headers := map[string]string{"host": u.Host}
+	for key, values := range req.Header {
+		trimmed := make([]string, len(values))
+		for i, v := range values {
+			trimmed[i] = strings.Join(strings.Fields(v), " ")
+		}
+		headers[strings.ToLower(key)] = strings.Join(trimmed, ",")
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	payloadHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		canonicalQuery(u.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/" + s.Service + "/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := sigV4Algorithm + "\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, s.Service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, s.AccessKeyID, scope, signedHeaders, signature))
+
+	return nil
+}
+
+// canonicalQuery encodes the query parameters sorted by key and value with
+// spaces encoded as %20.
+func canonicalQuery(values url.Values) string {
+	var params []string
+	for key, vals := range values {
+		for _, v := range vals {
+			params = append(params, sigV4Escape(key)+"="+sigV4Escape(v))
+		}
+	}
+	sort.Strings(params)
+	return strings.Join(params, "&")
+}
+
+// sigV4Escape percent-encodes everything except unreserved characters.
+func sigV4Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+// hmacSHA256 computes the HMAC of the data using the key.
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	io.WriteString(h, data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,75 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestSigV4(t *testing.T) {
+	t.Log("Given the need to sign requests for AWS AppSync.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen signing the AWS get-vanilla test request.", testID)
+		{
+			signer := graphql.SigV4{
+				AccessKeyID:     "AKIDEXAMPLE",
+				SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+				Region:          "us-east-1",
+				Service:         "service",
+				Now:             func() time.Time { return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC) },
+			}
+
+			req := graphql.Request{
+				Method: http.MethodGet,
+				URL:    "https://example.amazonaws.com/",
+				Header: make(http.Header),
+			}
+			if err := signer.Sign(context.Background(), &req, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to sign the request: %v", failed, testID, err)
+			}
+
+			exp := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+			if got := req.Header.Get("Authorization"); got != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould get the expected signature:\n%s\n%s", failed, testID, got, exp)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the expected signature.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen executing a query with a signer.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				auth := r.Header.Get("Authorization")
+				if !strings.Contains(auth, "/us-west-2/appsync/aws4_request") || r.Header.Get("X-Amz-Security-Token") != "token" {
+					t.Fatalf("\t%s\tTest %d:\tShould send the signature: %s", failed, testID, auth)
+				}
+				t.Logf("\t%s\tTest %d:\tShould send the signature.", success, testID)
+
+				io.WriteString(w, `{"data": {"listEvents": []}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			signer := graphql.SigV4{
+				AccessKeyID:     "AKID",
+				SecretAccessKey: "secret",
+				SessionToken:    "token",
+				Region:          "us-west-2",
+				Service:         "appsync",
+			}
+			gql := graphql.New(server.URL, graphql.WithRequestSigner(signer.Sign))
+
+			if err := gql.Execute(context.Background(), `query { listEvents { id } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+		}
+	}
+}
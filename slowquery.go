@@ -0,0 +1,63 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SlowQuery represents a request that took longer than the slow query
+// threshold to complete. Fingerprint groups the slow queries of graphql
+// documents by their shape, as produced by the Fingerprint function.
+type SlowQuery struct {
+	Endpoint      string
+	Operation     string
+	Fingerprint   string
+	RequestID     string
+	Duration      time.Duration
+	RequestBytes  int
+	ResponseBytes int
+}
+
+// String returns the slow query in a form suitable for logging.
+func (sq SlowQuery) String() string {
+	var fingerprint string
+	if sq.Fingerprint != "" {
+		fingerprint = "fingerprint:[" + sq.Fingerprint + "] "
+	}
+	return fmt.Sprintf("%s%sendpoint:[%s] duration:[%s] request_bytes:[%d] response_bytes:[%d]",
+		labels(sq.RequestID, sq.Operation), fingerprint, sq.Endpoint, sq.Duration, sq.RequestBytes, sq.ResponseBytes)
+}
+
+// WithSlowQueryThreshold reports every request that takes longer than the
+// threshold, from sending the request to reading the response, to the
+// specified function, which may be nil. When logging is enabled the slow
+// query is logged as well. Each attempt of a retried request is measured
+// on its own.
+func WithSlowQueryThreshold(threshold time.Duration, f func(SlowQuery)) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.slowThreshold = threshold
+		gql.slowQuery = f
+		gql.slowQueryEnabled = true
+	}
+}
+
+// reportSlowQuery delivers the request to the slow query function and the
+// logger when it took longer than the threshold.
+func (g *GraphQL) reportSlowQuery(ctx context.Context, sq SlowQuery) {
+	if sq.Duration <= g.slowThreshold {
+		return
+	}
+
+	if i := strings.IndexByte(sq.Endpoint, '?'); i >= 0 {
+		sq.Endpoint = sq.Endpoint[:i]
+	}
+
+	if g.slowQuery != nil {
+		g.slowQuery(sq)
+	}
+	if log := g.logger(ctx); log != nil {
+		log("slow query: " + sq.String())
+	}
+}
@@ -0,0 +1,69 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestSlowQueryThreshold(t *testing.T) {
+	t.Log("Given the need to log slow queries.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen requests are faster and slower than the threshold.", testID)
+		{
+			const data = `{"data": {"getCity": {"name": "Miami"}}}`
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				if strings.Contains(string(b), "Slow") {
+					time.Sleep(50 * time.Millisecond)
+				}
+				io.WriteString(w, data)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			var got []graphql.SlowQuery
+			var logged string
+			gql := graphql.New(server.URL,
+				graphql.WithSlowQueryThreshold(25*time.Millisecond, func(sq graphql.SlowQuery) { got = append(got, sq) }),
+				graphql.WithLogging(func(s string) {
+					if strings.HasPrefix(s, "slow query") {
+						logged = s
+					}
+				}),
+			)
+
+			if err := gql.Execute(context.Background(), `query Fast { getCity { name } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the fast query: %v", failed, testID, err)
+			}
+			if err := gql.Execute(context.Background(), `query Slow { getCity { name } }`, nil); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the slow query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the queries.", success, testID)
+
+			if len(got) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould report the slow query only: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the slow query only.", success, testID)
+
+			sq := got[0]
+			if sq.Operation != "query Slow" || sq.Fingerprint != graphql.Fingerprint(`query Slow { getCity { name } }`) || sq.Endpoint != "graphql" || sq.Duration < 50*time.Millisecond || sq.RequestBytes < 30 || sq.ResponseBytes != len(data) {
+				t.Fatalf("\t%s\tTest %d:\tShould report the details of the slow query: %+v", failed, testID, sq)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report the details of the slow query.", success, testID)
+
+			if !strings.Contains(logged, "operation:[query Slow]") {
+				t.Fatalf("\t%s\tTest %d:\tShould log the slow query: %s", failed, testID, logged)
+			}
+			t.Logf("\t%s\tTest %d:\tShould log the slow query.", success, testID)
+		}
+	}
+}
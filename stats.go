@@ -0,0 +1,114 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"net/http/httptrace"
+	"sync/atomic"
+)
+
+// Stats represents a snapshot of the requests made by a client. Requests
+// and their bytes are counted once however many attempts it took to deliver
+// them, and connections are counted for every attempt. Bytes are counted
+// before compression, and connections only for transports built on
+// net/http. ETag hits and misses count the conditional requests made when
+// WithETags is used.
+type Stats struct {
+	InFlight      int64
+	Succeeded     uint64
+	GraphQLErrors uint64
+	HTTPErrors    uint64
+	Timeouts      uint64
+	Failed        uint64
+	Shed          uint64
+	BytesSent     uint64
+	BytesReceived uint64
+	NewConns      uint64
+	ReusedConns   uint64
+	ETagHits      uint64
+	ETagMisses    uint64
+}
+
+// Total returns the number of requests that completed or were shed.
+func (s Stats) Total() uint64 {
+	return s.Succeeded + s.GraphQLErrors + s.HTTPErrors + s.Timeouts + s.Failed + s.Shed
+}
+
+// Stats returns a snapshot of the requests made by the client, so health
+// endpoints can report the pressure on it. A clone starts with its own
+// stats.
+func (g *GraphQL) Stats() Stats {
+	s := g.stats
+	if s == nil {
+		return Stats{}
+	}
+
+	return Stats{
+		InFlight:      s.inFlight.Load(),
+		Succeeded:     s.succeeded.Load(),
+		GraphQLErrors: s.graphqlErrors.Load(),
+		HTTPErrors:    s.httpErrors.Load(),
+		Timeouts:      s.timeouts.Load(),
+		Failed:        s.failed.Load(),
+		Shed:          s.shed.Load(),
+		BytesSent:     s.bytesSent.Load(),
+		BytesReceived: s.bytesReceived.Load(),
+		NewConns:      s.newConns.Load(),
+		ReusedConns:   s.reusedConns.Load(),
+		ETagHits:      s.etagHits.Load(),
+		ETagMisses:    s.etagMisses.Load(),
+	}
+}
+
+// =============================================================================
+
+// clientStats holds the counters behind the Stats of a client.
+type clientStats struct {
+	inFlight      atomic.Int64
+	succeeded     atomic.Uint64
+	graphqlErrors atomic.Uint64
+	httpErrors    atomic.Uint64
+	timeouts      atomic.Uint64
+	failed        atomic.Uint64
+	shed          atomic.Uint64
+	bytesSent     atomic.Uint64
+	bytesReceived atomic.Uint64
+	newConns      atomic.Uint64
+	reusedConns   atomic.Uint64
+	etagHits      atomic.Uint64
+	etagMisses    atomic.Uint64
+}
+
+// done records the outcome of a request.
+func (s *clientStats) done(err error) {
+	s.inFlight.Add(-1)
+
+	var gqlErr *GraphQLError
+	var httpErr *HTTPError
+	switch {
+	case err == nil:
+		s.succeeded.Add(1)
+	case errors.Is(err, ErrTimeout):
+		s.timeouts.Add(1)
+	case errors.As(err, &gqlErr):
+		s.graphqlErrors.Add(1)
+	case errors.As(err, &httpErr):
+		s.httpErrors.Add(1)
+	default:
+		s.failed.Add(1)
+	}
+}
+
+// withTrace returns a copy of the context that counts the connections used.
+func (s *clientStats) withTrace(ctx context.Context) context.Context {
+	trace := httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				s.reusedConns.Add(1)
+				return
+			}
+			s.newConns.Add(1)
+		},
+	}
+	return httptrace.WithClientTrace(ctx, &trace)
+}
@@ -0,0 +1,81 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestStats(t *testing.T) {
+	t.Log("Given the need to report the pressure on a client.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen requests succeed and fail.", testID)
+		{
+			release := make(chan struct{})
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				switch {
+				case strings.Contains(string(b), "block"):
+					<-release
+				case strings.Contains(string(b), "bad"):
+					io.WriteString(w, `{"errors": [{"message": "bad"}]}`)
+					return
+				case strings.Contains(string(b), "down"):
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				io.WriteString(w, `{"data": {"ok": true}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			done := make(chan error)
+			go func() {
+				done <- gql.Execute(context.Background(), `{ block }`, nil)
+			}()
+
+			for gql.Stats().InFlight == 0 {
+				runtime.Gosched()
+			}
+			if got := gql.Stats().InFlight; got != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould count the request in flight: %d", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould count the request in flight.", success, testID)
+
+			close(release)
+			if err := <-done; err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+
+			gql.Execute(context.Background(), `{ ok }`, nil)
+			gql.Execute(context.Background(), `{ bad }`, nil)
+			gql.Execute(context.Background(), `{ down }`, nil)
+
+			got := gql.Stats()
+			if got.InFlight != 0 || got.Succeeded != 2 || got.GraphQLErrors != 1 || got.HTTPErrors != 1 || got.Total() != 4 {
+				t.Fatalf("\t%s\tTest %d:\tShould count the requests by outcome: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould count the requests by outcome.", success, testID)
+
+			if got.BytesSent == 0 || got.BytesReceived == 0 || got.NewConns == 0 || got.NewConns+got.ReusedConns != 4 {
+				t.Fatalf("\t%s\tTest %d:\tShould count the bytes and connections: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould count the bytes and connections.", success, testID)
+
+			if got := gql.Clone().Stats(); got.Total() != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould start a clone with its own stats: %+v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould start a clone with its own stats.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,381 @@
+package graphql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ExecuteNDJSON performs a graphql request against the url/graphql endpoint
+// for hosts that can stream list results as newline-delimited JSON, like
+// export jobs pulling millions of nodes. The request advertises the
+// application/x-ndjson content type and the item function is called for
+// every line of the response as it arrives, so the list is never held in
+// memory. A host reports a failure after the stream started by ending it
+// with a line holding only an errors list, like {"errors": [...]}. When the
+// host responds with regular JSON instead, the data must select a single
+// field holding the list and the function is called for each of its items.
+// Returning an error from the function stops the request and the error is
+// returned.
+func (g *GraphQL) ExecuteNDJSON(ctx context.Context, graphql string, item func(item json.RawMessage) error, variables ...func(m map[string]interface{})) error {
+	var queryVars map[string]interface{}
+	if len(variables) > 0 {
+		queryVars = make(map[string]interface{})
+		for _, variable := range variables {
+			variable(queryVars)
+		}
+	}
+
+	pq, err := g.prepare(ctx, g.graphqlPath, graphql, queryVars)
+	if err != nil {
+		return err
+	}
+
+	accept := "application/x-ndjson, application/json;q=0.9"
+	return g.stream(pq.ctx, g.graphqlPath, pq.body, accept, func(contentType string, r io.Reader) error {
+		if contentType == "application/x-ndjson" {
+			return readNDJSON(r, item, g.errorBodyLimit)
+		}
+		return readPath(r, nil, item)
+	})
+}
+
+// ExecuteStream performs a graphql request against the url/graphql endpoint
+// and calls the item function for each item of the list found at the path
+// within the data, like queryCity or getCity.people. The response is read
+// token by token and each item is decoded as it arrives, so the response is
+// never held in memory as a whole. The errors of the response are returned
+// after the items were delivered. Returning an error from the function
+// stops the request and the error is returned.
+func (g *GraphQL) ExecuteStream(ctx context.Context, graphql string, path string, item func(item json.RawMessage) error, variables ...func(m map[string]interface{})) error {
+	if path == "" {
+		return errors.New("graphql decoding error: a path is required")
+	}
+
+	var queryVars map[string]interface{}
+	if len(variables) > 0 {
+		queryVars = make(map[string]interface{})
+		for _, variable := range variables {
+			variable(queryVars)
+		}
+	}
+
+	pq, err := g.prepare(ctx, g.graphqlPath, graphql, queryVars)
+	if err != nil {
+		return err
+	}
+
+	fields := strings.Split(path, ".")
+	return g.stream(pq.ctx, g.graphqlPath, pq.body, "application/json", func(contentType string, r io.Reader) error {
+		return readPath(r, fields, item)
+	})
+}
+
+// stream sends the request and passes the response body to the read
+// function as it arrives, instead of reading it into memory. The request
+// goes through the same load shedding, throttling, stats, and timeout as
+// any other request.
+func (g *GraphQL) stream(ctx context.Context, endpoint string, body []byte, accept string, read func(contentType string, r io.Reader) error) error {
+	ctx = withCallOption(ctx, func(opts *callOptions) {
+		opts.accept = accept
+	})
+
+	return g.gate(ctx, func(ctx context.Context) error {
+		return g.streamResponse(ctx, endpoint, body, read)
+	})
+}
+
+// streamResponse sends the request and reads the response. The slow query
+// report and the log message are made once the response was read, and a
+// debug log message holds the request but not the streamed data.
+func (g *GraphQL) streamResponse(ctx context.Context, endpoint string, body []byte, read func(contentType string, r io.Reader) error) error {
+
+	// Generate the id that correlates this request with the host's logs.
+	var requestID string
+	if g.requestIDHeader != "" {
+		requestID = newRequestID()
+		ctx = withCallOption(ctx, func(opts *callOptions) {
+			opts.requestID = requestID
+		})
+	}
+
+	if g.throttle != nil {
+		if err := g.throttle.wait(ctx, g); err != nil {
+			return err
+		}
+	}
+
+	start := time.Now()
+	resp, err := g.send(ctx, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if g.throttle != nil {
+		g.throttle.updateHeader(resp.Header)
+	}
+
+	operation := callOpts(ctx).operation.String()
+
+	info := callOpts(ctx).responseInfo
+	if info != nil {
+		info.StatusCode = resp.StatusCode
+		info.Header = resp.Header
+		info.RequestID = requestID
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		httpErr := newHTTPError(resp)
+		httpErr.RequestID = requestID
+		httpErr.Operation = operation
+		return httpErr
+	}
+
+	// The bytes received are counted as the body is read.
+	var received limitedBuffer
+	var r io.Reader = io.TeeReader(resp.Body, &received)
+
+	// A transformed body can only be decoded as a whole.
+	if g.bodyTransformer != nil {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("graphql copy error: %w", err)
+		}
+		if data, err = g.decodeBody(ctx, data); err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	err = read(contentType, r)
+
+	if g.stats != nil {
+		g.stats.bytesSent.Add(uint64(len(body)))
+		g.stats.bytesReceived.Add(uint64(received.dropped))
+	}
+
+	if g.slowQueryEnabled {
+		g.reportSlowQuery(ctx, SlowQuery{
+			Endpoint:      endpoint,
+			Operation:     operation,
+			Fingerprint:   callOpts(ctx).fingerprint,
+			RequestID:     requestID,
+			Duration:      time.Since(start),
+			RequestBytes:  len(body),
+			ResponseBytes: received.dropped,
+		})
+	}
+
+	// The request is omitted from log messages and errors when capture is
+	// disabled.
+	var request string
+	if !g.noCapture {
+		request = excerpt(body, g.errorBodyLimit)
+	}
+	prefix := labels(requestID, operation)
+
+	if log := g.logger(ctx); log != nil {
+		if g.logLevelFor(ctx) == LogDebug {
+			log(fmt.Sprintf("%srequest:[%s] data:[%d bytes streamed]", prefix, request, received.dropped))
+		} else {
+			log(fmt.Sprintf("%sendpoint:[%s] duration:[%s] request:[%d bytes] data:[%d bytes]", prefix, endpoint, time.Since(start), len(body), received.dropped))
+		}
+	}
+
+	var gqlErr *GraphQLError
+	if errors.As(err, &gqlErr) {
+		gqlErr.Request = request
+		gqlErr.RequestID = requestID
+		gqlErr.Operation = operation
+	}
+
+	return err
+}
+
+// =============================================================================
+
+// readNDJSON calls the item function for every line of the stream. Invalid
+// lines are embedded in the error up to the limit.
+func readNDJSON(r io.Reader, item func(item json.RawMessage) error, limit int) error {
+	br := bufio.NewReader(r)
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			if itemErr := ndjsonItem(line, item, limit); itemErr != nil {
+				return itemErr
+			}
+		}
+
+		switch {
+		case errors.Is(err, io.EOF):
+			return nil
+		case err != nil:
+			return fmt.Errorf("graphql copy error: %w", err)
+		}
+	}
+}
+
+// ndjsonItem calls the item function for a line, unless the line reports
+// the errors of the request.
+func ndjsonItem(line []byte, item func(item json.RawMessage) error, limit int) error {
+	if !json.Valid(line) {
+		return fmt.Errorf("graphql decoding error: invalid line: %s", excerpt(line, limit))
+	}
+
+	// Only lines that mention errors are decoded to check for a failure.
+	var failure map[string]json.RawMessage
+	if bytes.Contains(line, []byte(`"errors"`)) && json.Unmarshal(line, &failure) == nil && len(failure) == 1 {
+		if raw, ok := failure["errors"]; ok {
+			var errs []ResponseError
+			if err := json.Unmarshal(raw, &errs); err == nil && len(errs) > 0 {
+				return &GraphQLError{Errors: errs}
+			}
+		}
+	}
+
+	return item(json.RawMessage(bytes.TrimSpace(line)))
+}
+
+// readPath walks the tokens of a regular response to the list at the path
+// within the data and calls the item function for each item as it's
+// decoded. A nil path selects the list held by the single field of the
+// data. The errors of the response are returned once the data was read.
+func readPath(r io.Reader, path []string, item func(item json.RawMessage) error) error {
+	d := json.NewDecoder(r)
+	if err := expectDelim(d, '{'); err != nil {
+		return err
+	}
+
+	var errs []ResponseError
+	var found bool
+	for d.More() {
+		key, err := d.Token()
+		if err != nil {
+			return fmt.Errorf("graphql decoding error: %w", err)
+		}
+
+		switch key {
+		case "data":
+			if found, err = walkPath(d, path, path == nil, item); err != nil {
+				return err
+			}
+
+		case "errors":
+			if err := d.Decode(&errs); err != nil {
+				return fmt.Errorf("graphql decoding error: %w", err)
+			}
+
+		default:
+			if err := skipValue(d); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return &GraphQLError{Errors: errs}
+	}
+
+	if !found {
+		return fmt.Errorf("graphql decoding error: no list at path %q", strings.Join(path, "."))
+	}
+
+	return nil
+}
+
+// walkPath descends into the value at the path and calls the item function
+// for each item of the list it holds. It reports whether the list was found,
+// where a null list is an empty one. When single is set the object must
+// have a single field holding the list.
+func walkPath(d *json.Decoder, path []string, single bool, item func(item json.RawMessage) error) (bool, error) {
+	tok, err := d.Token()
+	if err != nil {
+		return false, fmt.Errorf("graphql decoding error: %w", err)
+	}
+	if tok == nil {
+		return len(path) == 0 && !single, nil
+	}
+
+	if len(path) == 0 && !single {
+		if tok != json.Delim('[') {
+			return false, fmt.Errorf("graphql decoding error: expected a list, got %v", tok)
+		}
+		for d.More() {
+			var raw json.RawMessage
+			if err := d.Decode(&raw); err != nil {
+				return false, fmt.Errorf("graphql decoding error: %w", err)
+			}
+			if err := item(raw); err != nil {
+				return false, err
+			}
+		}
+		return true, expectDelim(d, ']')
+	}
+
+	if tok != json.Delim('{') {
+		return false, fmt.Errorf("graphql decoding error: expected an object, got %v", tok)
+	}
+
+	var found bool
+	var fields int
+	for d.More() {
+		key, err := d.Token()
+		if err != nil {
+			return false, fmt.Errorf("graphql decoding error: %w", err)
+		}
+		fields++
+
+		switch {
+		case single && fields > 1:
+			return false, errors.New("graphql decoding error: the query must select a single field")
+
+		case single || key == path[0]:
+			var rest []string
+			if !single {
+				rest = path[1:]
+			}
+			if found, err = walkPath(d, rest, false, item); err != nil {
+				return false, err
+			}
+
+		default:
+			if err := skipValue(d); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	return found, expectDelim(d, '}')
+}
+
+// expectDelim reads the next token and checks it's the delimiter.
+func expectDelim(d *json.Decoder, delim json.Delim) error {
+	tok, err := d.Token()
+	if err != nil {
+		return fmt.Errorf("graphql decoding error: %w", err)
+	}
+	if tok != delim {
+		return fmt.Errorf("graphql decoding error: expected %v, got %v", delim, tok)
+	}
+	return nil
+}
+
+// skipValue reads past the next value.
+func skipValue(d *json.Decoder) error {
+	var raw json.RawMessage
+	if err := d.Decode(&raw); err != nil {
+		return fmt.Errorf("graphql decoding error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,189 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestExecuteNDJSON(t *testing.T) {
+	t.Log("Given the need to stream the items of a large list.")
+	{
+		var accept string
+		f := func(w http.ResponseWriter, r *http.Request) {
+			accept = r.Header.Get("Accept")
+			b, _ := io.ReadAll(r.Body)
+
+			if strings.Contains(string(b), "json") {
+				io.WriteString(w, `{"data": {"queryCity": [{"name": "c0"}, {"name": "c1"}]}}`)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			for i := 0; i < 3; i++ {
+				fmt.Fprintf(w, "{\"name\": \"c%d\"}\n", i)
+				w.(http.Flusher).Flush()
+			}
+			if strings.Contains(string(b), "fail") {
+				io.WriteString(w, `{"errors": [{"message": "export failed"}]}`+"\n")
+			}
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(f))
+		defer server.Close()
+
+		gql := graphql.New(server.URL)
+
+		collect := func(query string) ([]string, error) {
+			var names []string
+			err := gql.ExecuteNDJSON(context.Background(), query, func(item json.RawMessage) error {
+				var city struct {
+					Name string `json:"name"`
+				}
+				if err := json.Unmarshal(item, &city); err != nil {
+					return err
+				}
+				names = append(names, city.Name)
+				return nil
+			})
+			return names, err
+		}
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the host streams newline-delimited JSON.", testID)
+		{
+			names, err := collect(`query { queryCity { name } }`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to stream the items: %v", failed, testID, err)
+			}
+			if fmt.Sprint(names) != "[c0 c1 c2]" || !strings.HasPrefix(accept, "application/x-ndjson") {
+				t.Fatalf("\t%s\tTest %d:\tShould get every item: %v %q", failed, testID, names, accept)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get every item.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the host ends the stream with errors.", testID)
+		{
+			names, err := collect(`query { fail: queryCity { name } }`)
+			var gqlErr *graphql.GraphQLError
+			if !errors.As(err, &gqlErr) || gqlErr.Errors[0].Message != "export failed" || len(names) != 3 {
+				t.Fatalf("\t%s\tTest %d:\tShould get the errors after the items: %v %v", failed, testID, names, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the errors after the items.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the host responds with regular JSON.", testID)
+		{
+			names, err := collect(`query { json: queryCity { name } }`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to read the items: %v", failed, testID, err)
+			}
+			if fmt.Sprint(names) != "[c0 c1]" {
+				t.Fatalf("\t%s\tTest %d:\tShould get every item: %v", failed, testID, names)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get every item.", success, testID)
+		}
+	}
+}
+
+func TestExecuteStream(t *testing.T) {
+	t.Log("Given the need to walk into a large list of a regular response.")
+	{
+		f := func(w http.ResponseWriter, r *http.Request) {
+			b, _ := io.ReadAll(r.Body)
+
+			switch {
+			case strings.Contains(string(b), "fail"):
+				io.WriteString(w, `{"data": {"getCity": {"people": [{"name": "p0"}]}}, "errors": [{"message": "partial"}]}`)
+			default:
+				io.WriteString(w, `{"extensions": {"cost": 1}, "data": {"other": [1, 2], "getCity": {"name": "Miami", "people": [{"name": "p0"}, {"name": "p1"}, {"name": "p2"}]}}}`)
+			}
+		}
+
+		server := httptest.NewServer(http.HandlerFunc(f))
+		defer server.Close()
+
+		gql := graphql.New(server.URL)
+
+		collect := func(query string, path string) ([]string, error) {
+			var names []string
+			err := gql.ExecuteStream(context.Background(), query, path, func(item json.RawMessage) error {
+				var person struct {
+					Name string `json:"name"`
+				}
+				if err := json.Unmarshal(item, &person); err != nil {
+					return err
+				}
+				names = append(names, person.Name)
+				return nil
+			})
+			return names, err
+		}
+
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the list is nested in the data.", testID)
+		{
+			names, err := collect(`query { getCity { people { name } } }`, "getCity.people")
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to stream the items: %v", failed, testID, err)
+			}
+			if fmt.Sprint(names) != "[p0 p1 p2]" {
+				t.Fatalf("\t%s\tTest %d:\tShould get every item: %v", failed, testID, names)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get every item.", success, testID)
+
+			if _, err := collect(`query { getCity { people { name } } }`, "getCity.missing"); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould fail for a path without a list.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould fail for a path without a list.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the response has errors.", testID)
+		{
+			names, err := collect(`query { fail: getCity { people { name } } }`, "getCity.people")
+			var gqlErr *graphql.GraphQLError
+			if !errors.As(err, &gqlErr) || len(names) != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould get the errors after the items: %v %v", failed, testID, names, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the errors after the items.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the client reports its requests.", testID)
+		{
+			var logs []string
+			gql := graphql.New(server.URL, graphql.WithRequestID(""), graphql.WithLogging(func(s string) {
+				logs = append(logs, s)
+			}))
+
+			err := gql.ExecuteStream(context.Background(), `query { getCity { people { name } } }`, "getCity.people", func(item json.RawMessage) error {
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to stream the items: %v", failed, testID, err)
+			}
+
+			stats := gql.Stats()
+			if stats.Succeeded != 1 || stats.BytesSent == 0 || stats.BytesReceived == 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould count the request and its bytes: %+v", failed, testID, stats)
+			}
+			t.Logf("\t%s\tTest %d:\tShould count the request and its bytes.", success, testID)
+
+			if len(logs) != 1 || !strings.Contains(logs[0], "request_id:[") {
+				t.Fatalf("\t%s\tTest %d:\tShould log the request with its id: %v", failed, testID, logs)
+			}
+			t.Logf("\t%s\tTest %d:\tShould log the request with its id.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,1007 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Set of defaults for subscriptions.
+const (
+	defaultSubscriptionsPerConn = 100
+	subscriptionInitTimeout     = 10 * time.Second
+	subscriptionWriteTimeout    = 10 * time.Second
+)
+
+// Set of WebSocket subprotocols. graphql-ws is the subprotocol of the
+// legacy subscriptions-transport-ws protocol.
+const (
+	wsProtocol       = "graphql-transport-ws"
+	wsLegacyProtocol = "graphql-ws"
+)
+
+// SubscriptionProtocol identifies the protocol used for subscriptions.
+type SubscriptionProtocol int
+
+// Set of subscription protocols. SubscriptionProtocolAuto offers both
+// protocols and uses the one the host selects, preferring
+// graphql-transport-ws when the host doesn't select one.
+const (
+	SubscriptionProtocolAuto SubscriptionProtocol = iota
+	SubscriptionProtocolTransportWS
+	SubscriptionProtocolLegacy
+)
+
+// subscriptionConfig holds the settings for the WebSocket connections of
+// subscriptions.
+type subscriptionConfig struct {
+	perConn      int
+	initPayload  func(ctx context.Context) map[string]interface{}
+	pingInterval time.Duration
+	pongTimeout  time.Duration
+	readLimit    int64
+	compression  bool
+	buffer       *subscriptionBuffer
+	protocol     SubscriptionProtocol
+}
+
+// WithSubscriptionsPerConn sets the number of subscriptions that share a
+// single WebSocket connection. Subscriptions spill over to additional
+// connections once every connection is full. The default is 100.
+func WithSubscriptionsPerConn(max int) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		if max > 0 {
+			gql.subsConfig.perConn = max
+		}
+	}
+}
+
+// WithSubscriptionInitPayload sets the function that provides the payload
+// of the connection_init message, like the credentials of gateways that
+// authenticate the connection instead of the handshake. The function is
+// called with the context of the subscription that opens the connection.
+func WithSubscriptionInitPayload(f func(ctx context.Context) map[string]interface{}) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.subsConfig.initPayload = f
+	}
+}
+
+// WithSubscriptionKeepAlive sends a ping message on every connection at the
+// specified interval, so gateways with an idle timeout keep it open. A
+// connection that doesn't receive the pong within the timeout is treated
+// as failed, which ends its subscriptions. The timeout defaults to the
+// interval.
+func WithSubscriptionKeepAlive(interval time.Duration, pongTimeout time.Duration) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		if pongTimeout <= 0 {
+			pongTimeout = interval
+		}
+		gql.subsConfig.pingInterval = interval
+		gql.subsConfig.pongTimeout = pongTimeout
+	}
+}
+
+// WithSubscriptionReadLimit sets the maximum size in bytes of a message
+// received on a connection. A larger message fails the connection. There
+// is no limit by default.
+func WithSubscriptionReadLimit(limit int64) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.subsConfig.readLimit = limit
+	}
+}
+
+// WithSubscriptionProtocol sets the protocol used for subscriptions. The
+// default negotiates between graphql-transport-ws and the legacy
+// subscriptions-transport-ws protocol that many gateways still use.
+func WithSubscriptionProtocol(protocol SubscriptionProtocol) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.subsConfig.protocol = protocol
+	}
+}
+
+// WithSubscriptionCompression negotiates per-message compression for the
+// connections with hosts that support it.
+func WithSubscriptionCompression() func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.subsConfig.compression = true
+	}
+}
+
+// BufferPolicy determines what happens to the results of a subscription
+// when its buffer is full because the consumer is slow.
+type BufferPolicy int
+
+// Set of buffer policies. BufferBlock waits for the consumer, which stalls
+// the other subscriptions sharing the connection. BufferDropOldest discards
+// the oldest buffered result to make room and BufferDropNewest discards the
+// new result. Errors that end the subscription are never discarded.
+const (
+	BufferBlock BufferPolicy = iota
+	BufferDropOldest
+	BufferDropNewest
+)
+
+// subscriptionBuffer represents the size and policy of the buffer of a
+// subscription.
+type subscriptionBuffer struct {
+	size   int
+	policy BufferPolicy
+}
+
+// defaultSubscriptionBuffer is used when no buffer is configured.
+var defaultSubscriptionBuffer = subscriptionBuffer{size: 16, policy: BufferBlock}
+
+// WithSubscriptionBuffer sets the size and policy of the buffer of every
+// subscription, so slow consumers don't stall the connection or grow
+// without bound. The default is a buffer of 16 results that blocks.
+func WithSubscriptionBuffer(size int, policy BufferPolicy) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.subsConfig.buffer = &subscriptionBuffer{size: size, policy: policy}
+	}
+}
+
+// SubscriptionBuffer returns a copy of the context that sets the size and
+// policy of the buffer for the subscription started with it.
+func SubscriptionBuffer(ctx context.Context, size int, policy BufferPolicy) context.Context {
+	return withCallOption(ctx, func(opts *callOptions) {
+		opts.subsBuffer = &subscriptionBuffer{size: size, policy: policy}
+	})
+}
+
+// SubscriptionMessage represents a single result of a subscription. Err is
+// set when the result has errors or the subscription failed.
+type SubscriptionMessage struct {
+	Data json.RawMessage
+	Err  error
+}
+
+// Subscription represents an active subscription. Results are received
+// from the channel returned by Messages, which is closed when the
+// subscription ends.
+type Subscription struct {
+	id        string
+	operation Operation
+	conn      *wsConn
+	messages  chan SubscriptionMessage
+	policy    BufferPolicy
+	dropped   atomic.Uint64
+
+	done     chan struct{}
+	doneOnce sync.Once
+
+	mu       sync.Mutex
+	finished bool
+}
+
+// Subscribe starts the subscription against the configured host on the
+// url/graphql endpoint using the graphql-transport-ws protocol. Many
+// subscriptions are multiplexed over a single WebSocket connection. The
+// subscription ends when the context is canceled, Close is called, the
+// host completes it, or the connection fails. Subscriptions connect
+// directly and don't use the transport set with WithTransport.
+func (g *GraphQL) Subscribe(ctx context.Context, graphql string, variables ...func(m map[string]interface{})) (*Subscription, error) {
+	if g.closers.isClosed() {
+		return nil, ErrClosed
+	}
+
+	if g.configErr != nil {
+		return nil, g.configErr
+	}
+
+	var queryVars map[string]interface{}
+	if len(variables) > 0 {
+		queryVars = make(map[string]interface{})
+		for _, variable := range variables {
+			variable(queryVars)
+		}
+	}
+
+	queryVars, err := g.formatVariables(queryVars)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := defaultSubscriptionBuffer
+	switch {
+	case callOpts(ctx).subsBuffer != nil:
+		buffer = *callOpts(ctx).subsBuffer
+	case g.subsConfig.buffer != nil:
+		buffer = *g.subsConfig.buffer
+	}
+	if buffer.size < 0 || (buffer.size == 0 && buffer.policy != BufferBlock) {
+		return nil, fmt.Errorf("graphql subscription error: invalid buffer size %d", buffer.size)
+	}
+
+	sub := Subscription{
+		operation: ParseOperation(graphql),
+		messages:  make(chan SubscriptionMessage, buffer.size),
+		policy:    buffer.policy,
+		done:      make(chan struct{}),
+	}
+
+	conn, err := g.subs.add(ctx, g, &sub)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables,omitempty"`
+	}{
+		Query:     graphql,
+		Variables: queryVars,
+	}
+	if err := conn.write(wsMessage{ID: sub.id, Type: "subscribe", Payload: payload}); err != nil {
+		g.subs.remove(&sub)
+		return nil, err
+	}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			sub.Close()
+		case <-sub.done:
+		}
+	}()
+
+	return &sub, nil
+}
+
+// ID returns the id of the subscription on its connection.
+func (s *Subscription) ID() string {
+	return s.id
+}
+
+// Messages returns the channel the results of the subscription are
+// received from.
+func (s *Subscription) Messages() <-chan SubscriptionMessage {
+	return s.messages
+}
+
+// Close ends the subscription. It's safe to call Close more than once.
+func (s *Subscription) Close() error {
+	if s.conn.pool.remove(s) {
+		s.conn.write(wsMessage{ID: s.id, Type: "complete"})
+	}
+	s.finish()
+	return nil
+}
+
+// Dropped returns the number of results discarded by the buffer policy.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// deliver sends the message to the consumer according to the buffer
+// policy unless the subscription ends first. A final message is never
+// discarded.
+func (s *Subscription) deliver(msg SubscriptionMessage, final bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.finished {
+		return
+	}
+
+	policy := s.policy
+	if final && policy == BufferDropNewest {
+		policy = BufferDropOldest
+	}
+
+	switch policy {
+	case BufferDropNewest:
+		select {
+		case s.messages <- msg:
+		default:
+			s.dropped.Add(1)
+		}
+		return
+
+	case BufferDropOldest:
+		for {
+			select {
+			case s.messages <- msg:
+				return
+			default:
+			}
+
+			select {
+			case <-s.messages:
+				s.dropped.Add(1)
+			default:
+			}
+		}
+	}
+
+	select {
+	case s.messages <- msg:
+	case <-s.done:
+	}
+}
+
+// finish closes the channel of the subscription.
+func (s *Subscription) finish() {
+	s.doneOnce.Do(func() { close(s.done) })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.finished {
+		s.finished = true
+		close(s.messages)
+	}
+}
+
+// =============================================================================
+
+// SubscriptionEventKind identifies the kind of a subscription event.
+type SubscriptionEventKind int
+
+// Set of subscription event kinds.
+const (
+	SubscriptionConnected SubscriptionEventKind = iota
+	SubscriptionData
+	SubscriptionDisconnected
+	SubscriptionCompleted
+)
+
+// String returns the name of the kind.
+func (k SubscriptionEventKind) String() string {
+	switch k {
+	case SubscriptionConnected:
+		return "connected"
+	case SubscriptionData:
+		return "data"
+	case SubscriptionDisconnected:
+		return "disconnected"
+	case SubscriptionCompleted:
+		return "completed"
+	}
+	return "unknown"
+}
+
+// SubscriptionEvent represents an event of a typed subscription. Data
+// events carry the decoded result and Err when the result has errors or
+// can't be decoded. A disconnected event carries the connection error and,
+// like a completed event, is the last event of the subscription.
+type SubscriptionEvent[T any] struct {
+	Kind SubscriptionEventKind
+	Data T
+	Err  error
+}
+
+// Subscribe starts the subscription and delivers its events with the
+// results decoded into T, applying the configured decode hooks. The first
+// event reports the subscription is connected and the channel is closed
+// after the last event. Cancel the context to end the subscription.
+func Subscribe[T any](ctx context.Context, gql *GraphQL, graphql string, variables ...func(m map[string]interface{})) (<-chan SubscriptionEvent[T], error) {
+	sub, err := gql.Subscribe(ctx, graphql, variables...)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make(chan SubscriptionEvent[T])
+	hooks := gql.decodeHooksFor(ctx)
+
+	go func() {
+		defer close(events)
+
+		send := func(event SubscriptionEvent[T]) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				sub.Close()
+				return false
+			}
+		}
+
+		if !send(SubscriptionEvent[T]{Kind: SubscriptionConnected}) {
+			return
+		}
+
+		for msg := range sub.Messages() {
+			if IsTransportError(msg.Err) {
+				send(SubscriptionEvent[T]{Kind: SubscriptionDisconnected, Err: msg.Err})
+				return
+			}
+
+			event := SubscriptionEvent[T]{Kind: SubscriptionData, Err: msg.Err}
+			if len(msg.Data) > 0 && string(msg.Data) != "null" {
+				if err := decodeData(msg.Data, &event.Data, hooks, gql.errorBodyLimit); err != nil && event.Err == nil {
+					event.Err = err
+				}
+			}
+			if !send(event) {
+				return
+			}
+		}
+
+		switch {
+		case ctx.Err() != nil:
+		case gql.closers.isClosed():
+			send(SubscriptionEvent[T]{Kind: SubscriptionDisconnected, Err: ErrClosed})
+		default:
+			send(SubscriptionEvent[T]{Kind: SubscriptionCompleted})
+		}
+	}()
+
+	return events, nil
+}
+
+// =============================================================================
+
+// wsMessage represents a message of the graphql-transport-ws protocol.
+type wsMessage struct {
+	ID      string      `json:"id,omitempty"`
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+// subscriptionPool multiplexes the subscriptions of a client over its
+// WebSocket connections.
+type subscriptionPool struct {
+	mu         sync.Mutex
+	conns      []*wsConn
+	dials      []*wsDial
+	nextID     uint64
+	registered bool
+	closed     bool
+}
+
+// wsDial represents a connection being dialed. Subscriptions that find
+// every connection full reserve a slot on it and wait for it instead of
+// dialing a connection of their own.
+type wsDial struct {
+	reserved int
+	done     chan struct{}
+}
+
+// add registers the subscription on a connection with room for it,
+// dialing a new connection when every connection is full. The pool isn't
+// locked while dialing so the connections in use aren't held up by the
+// handshake.
+func (p *subscriptionPool) add(ctx context.Context, g *GraphQL, sub *Subscription) (*wsConn, error) {
+	perConn := g.subsConfig.perConn
+	if perConn <= 0 {
+		perConn = defaultSubscriptionsPerConn
+	}
+
+	for {
+		p.mu.Lock()
+		if p.closed {
+			p.mu.Unlock()
+			return nil, ErrClosed
+		}
+
+		if conn := p.available(perConn); conn != nil {
+			p.register(conn, sub)
+			p.mu.Unlock()
+			return conn, nil
+		}
+
+		if dial := p.pending(perConn); dial != nil {
+			dial.reserved++
+			p.mu.Unlock()
+
+			select {
+			case <-dial.done:
+				continue
+			case <-ctx.Done():
+				p.mu.Lock()
+				dial.reserved--
+				p.mu.Unlock()
+				return nil, ctx.Err()
+			}
+		}
+
+		dial := wsDial{reserved: 1, done: make(chan struct{})}
+		p.dials = append(p.dials, &dial)
+
+		register := !p.registered
+		p.registered = true
+		p.mu.Unlock()
+
+		if register {
+			g.closers.add(p.close)
+		}
+
+		conn, err := dialSubscriptions(ctx, g, p)
+
+		p.mu.Lock()
+		p.dropDial(&dial)
+		close(dial.done)
+
+		switch {
+		case err != nil:
+			p.mu.Unlock()
+			return nil, err
+
+		case p.closed:
+			p.mu.Unlock()
+			conn.close()
+			return nil, ErrClosed
+
+		case conn.failed:
+			p.mu.Unlock()
+			if conn.failedErr != nil {
+				return nil, conn.failedErr
+			}
+			return nil, ErrClosed
+		}
+
+		p.conns = append(p.conns, conn)
+		p.register(conn, sub)
+		p.mu.Unlock()
+
+		return conn, nil
+	}
+}
+
+// available returns a connection with room for another subscription. The
+// pool must be locked.
+func (p *subscriptionPool) available(perConn int) *wsConn {
+	for _, c := range p.conns {
+		if len(c.subs) < perConn {
+			return c
+		}
+	}
+	return nil
+}
+
+// pending returns a connection being dialed with a slot that isn't
+// reserved. The pool must be locked.
+func (p *subscriptionPool) pending(perConn int) *wsDial {
+	for _, d := range p.dials {
+		if d.reserved < perConn {
+			return d
+		}
+	}
+	return nil
+}
+
+// register assigns the subscription an id on the connection. The pool must
+// be locked.
+func (p *subscriptionPool) register(conn *wsConn, sub *Subscription) {
+	p.nextID++
+	sub.id = strconv.FormatUint(p.nextID, 10)
+	sub.conn = conn
+	conn.subs[sub.id] = sub
+}
+
+// dropDial removes the dial from the pool. The pool must be locked.
+func (p *subscriptionPool) dropDial(dial *wsDial) {
+	for i, d := range p.dials {
+		if d == dial {
+			p.dials = append(p.dials[:i], p.dials[i+1:]...)
+			return
+		}
+	}
+}
+
+// remove unregisters the subscription and closes its connection once it
+// has no subscriptions left. It reports whether the subscription was
+// registered.
+func (p *subscriptionPool) remove(sub *Subscription) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	conn := sub.conn
+	if conn.subs[sub.id] != sub {
+		return false
+	}
+	delete(conn.subs, sub.id)
+
+	if len(conn.subs) == 0 {
+		p.drop(conn)
+		go conn.close()
+	}
+	return true
+}
+
+// drop removes the connection from the pool. The pool must be locked.
+func (p *subscriptionPool) drop(conn *wsConn) {
+	for i, c := range p.conns {
+		if c == conn {
+			p.conns = append(p.conns[:i], p.conns[i+1:]...)
+			return
+		}
+	}
+}
+
+// fail ends the subscriptions of a connection that failed.
+func (p *subscriptionPool) fail(conn *wsConn, err error) {
+	p.mu.Lock()
+	p.drop(conn)
+	subs := conn.subs
+	conn.subs = make(map[string]*Subscription)
+	conn.failed = true
+	conn.failedErr = err
+	p.mu.Unlock()
+
+	for _, sub := range subs {
+		if err != nil {
+			sub.deliver(SubscriptionMessage{Err: err}, true)
+		}
+		sub.finish()
+	}
+}
+
+// close closes every connection, ending their subscriptions.
+func (p *subscriptionPool) close() {
+	p.mu.Lock()
+	conns := p.conns
+	p.conns = nil
+	p.closed = true
+	p.mu.Unlock()
+
+	for _, conn := range conns {
+		conn.close()
+		p.fail(conn, nil)
+	}
+}
+
+// =============================================================================
+
+// wsConn represents a WebSocket connection shared by subscriptions. The
+// subscriptions and whether the connection failed are guarded by the mutex
+// of the pool. Messages are written and read using the names of the
+// graphql-transport-ws protocol and are translated when the connection uses
+// the legacy protocol.
+type wsConn struct {
+	pool      *subscriptionPool
+	ws        *websocket.Conn
+	legacy    bool
+	subs      map[string]*Subscription
+	failed    bool
+	failedErr error
+	bodyLimit int
+
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+	closing   chan struct{}
+	done      chan struct{}
+	pong      chan struct{}
+
+	failMu  sync.Mutex
+	failure error
+}
+
+// dialSubscriptions opens a connection to the url/graphql endpoint and
+// completes the connection handshake.
+func dialSubscriptions(ctx context.Context, g *GraphQL, pool *subscriptionPool) (*wsConn, error) {
+	url := g.endpointURL(ctx, g.url, g.graphqlPath)
+	switch {
+	case strings.HasPrefix(url, "https://"):
+		url = "wss://" + strings.TrimPrefix(url, "https://")
+	case strings.HasPrefix(url, "http://"):
+		url = "ws://" + strings.TrimPrefix(url, "http://")
+	}
+
+	cfg := g.subsConfig
+
+	var protocols []string
+	switch cfg.protocol {
+	case SubscriptionProtocolTransportWS:
+		protocols = []string{wsProtocol}
+	case SubscriptionProtocolLegacy:
+		protocols = []string{wsLegacyProtocol}
+	default:
+		protocols = []string{wsProtocol, wsLegacyProtocol}
+	}
+
+	dialer := websocket.Dialer{
+		Subprotocols:      protocols,
+		HandshakeTimeout:  subscriptionInitTimeout,
+		Proxy:             http.ProxyFromEnvironment,
+		EnableCompression: cfg.compression,
+	}
+	if g.client != nil {
+		if t, ok := g.client.Transport.(*http.Transport); ok {
+			dialer.Proxy = t.Proxy
+			dialer.TLSClientConfig = t.TLSClientConfig
+		}
+	}
+
+	header := make(http.Header)
+	g.setHeaders(ctx, header)
+	if err := g.setProvidedHeaders(ctx, header); err != nil {
+		return nil, err
+	}
+	if g.login != nil && g.login.userID != "" {
+		token, err := g.login.token(ctx, g, false)
+		if err != nil {
+			return nil, err
+		}
+		header.Set("X-Dgraph-AccessToken", token)
+	}
+
+	ws, resp, err := dialer.DialContext(ctx, url, header)
+	if err != nil {
+		if resp != nil {
+			if resp.StatusCode == http.StatusUnauthorized {
+				g.expireProvidedHeaders()
+			}
+			return nil, g.classify(newHTTPError(&Response{StatusCode: resp.StatusCode, Status: resp.Status, Header: resp.Header}))
+		}
+		return nil, &transportError{err: err}
+	}
+
+	if cfg.readLimit > 0 {
+		ws.SetReadLimit(cfg.readLimit)
+	}
+	if cfg.compression {
+		ws.EnableWriteCompression(true)
+	}
+
+	legacy := ws.Subprotocol() == wsLegacyProtocol
+	if ws.Subprotocol() == "" {
+		legacy = cfg.protocol == SubscriptionProtocolLegacy
+	}
+
+	conn := wsConn{
+		pool:      pool,
+		ws:        ws,
+		legacy:    legacy,
+		subs:      make(map[string]*Subscription),
+		bodyLimit: g.errorBodyLimit,
+		closing:   make(chan struct{}),
+		done:      make(chan struct{}),
+		pong:      make(chan struct{}, 1),
+	}
+
+	var payload map[string]interface{}
+	if cfg.initPayload != nil {
+		payload = cfg.initPayload(ctx)
+	}
+
+	if err := conn.init(payload); err != nil {
+		ws.Close()
+		return nil, err
+	}
+
+	go conn.readLoop()
+
+	if cfg.pingInterval > 0 {
+		go conn.keepAlive(cfg.pingInterval, cfg.pongTimeout)
+	}
+
+	return &conn, nil
+}
+
+// init sends the connection_init message and waits for the host to
+// acknowledge it.
+func (c *wsConn) init(payload map[string]interface{}) error {
+	msg := wsMessage{Type: "connection_init", Payload: struct{}{}}
+	if payload != nil {
+		msg.Payload = payload
+	}
+	if err := c.write(msg); err != nil {
+		return err
+	}
+
+	c.ws.SetReadDeadline(time.Now().Add(subscriptionInitTimeout))
+	defer c.ws.SetReadDeadline(time.Time{})
+
+	for {
+		var msg struct {
+			Type    string          `json:"type"`
+			Payload json.RawMessage `json:"payload"`
+		}
+		if err := c.ws.ReadJSON(&msg); err != nil {
+			return &transportError{err: err}
+		}
+
+		switch msg.Type {
+		case "connection_ack":
+			return nil
+		case "ping":
+			if err := c.write(wsMessage{Type: "pong"}); err != nil {
+				return err
+			}
+		case "connection_error", "error":
+			return fmt.Errorf("graphql subscription error: connection rejected: %s", excerpt(msg.Payload, c.bodyLimit))
+		}
+	}
+}
+
+// readLoop delivers the messages of the connection to the subscriptions
+// until the connection fails or is closed.
+func (c *wsConn) readLoop() {
+	defer close(c.done)
+
+	for {
+		var msg struct {
+			ID      string          `json:"id"`
+			Type    string          `json:"type"`
+			Payload json.RawMessage `json:"payload"`
+		}
+		if err := c.ws.ReadJSON(&msg); err != nil {
+			select {
+			case <-c.closing:
+				c.pool.fail(c, nil)
+			default:
+				c.failMu.Lock()
+				if c.failure != nil {
+					err = c.failure
+				}
+				c.failMu.Unlock()
+				c.pool.fail(c, &transportError{err: err})
+				c.ws.Close()
+			}
+			return
+		}
+
+		if c.legacy {
+			switch msg.Type {
+			case "data":
+				msg.Type = "next"
+			case "ka":
+				msg.Type = "pong"
+			}
+		}
+
+		switch msg.Type {
+		case "ping":
+			c.write(wsMessage{Type: "pong"})
+			continue
+		case "pong":
+			select {
+			case c.pong <- struct{}{}:
+			default:
+			}
+			continue
+		}
+
+		c.pool.mu.Lock()
+		sub := c.subs[msg.ID]
+		c.pool.mu.Unlock()
+		if sub == nil {
+			continue
+		}
+
+		switch msg.Type {
+		case "next":
+			var result struct {
+				Data   json.RawMessage `json:"data"`
+				Errors []ResponseError `json:"errors"`
+			}
+			if err := json.Unmarshal(msg.Payload, &result); err != nil {
+				sub.deliver(SubscriptionMessage{Err: fmt.Errorf("graphql decoding error: %w response: %s", err, excerpt(msg.Payload, c.bodyLimit))}, false)
+				continue
+			}
+			m := SubscriptionMessage{Data: result.Data}
+			if len(result.Errors) > 0 {
+				m.Err = &GraphQLError{Errors: result.Errors, Operation: sub.operation.String()}
+			}
+			sub.deliver(m, false)
+
+		case "error":
+			errs := subscriptionErrors(msg.Payload, c.bodyLimit)
+			sub.deliver(SubscriptionMessage{Err: &GraphQLError{Errors: errs, Operation: sub.operation.String()}}, true)
+			c.pool.remove(sub)
+			sub.finish()
+
+		case "complete":
+			c.pool.remove(sub)
+			sub.finish()
+		}
+	}
+}
+
+// subscriptionErrors decodes the payload of an error message, which is a
+// list of errors or, with the legacy protocol, a single error. A payload
+// that is neither is the message of the error, truncated to the limit.
+func subscriptionErrors(payload json.RawMessage, limit int) []ResponseError {
+	var errs []ResponseError
+	if err := json.Unmarshal(payload, &errs); err == nil {
+		return errs
+	}
+
+	var single ResponseError
+	if err := json.Unmarshal(payload, &single); err == nil && single.Message != "" {
+		return []ResponseError{single}
+	}
+
+	return []ResponseError{{Message: excerpt(payload, limit)}}
+}
+
+// keepAlive sends a ping at every interval and fails the connection when
+// the pong doesn't arrive within the timeout. The legacy protocol has no
+// pings, so the host's keep alive messages are expected instead.
+func (c *wsConn) keepAlive(interval time.Duration, pongTimeout time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.done:
+			return
+		}
+
+		wait := pongTimeout
+		if c.legacy {
+			wait += interval
+		} else {
+			select {
+			case <-c.pong:
+			default:
+			}
+
+			if err := c.write(wsMessage{Type: "ping"}); err != nil {
+				return
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-c.pong:
+			timer.Stop()
+		case <-c.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+			c.failMu.Lock()
+			c.failure = fmt.Errorf("no pong received within %s", wait)
+			c.failMu.Unlock()
+			c.ws.Close()
+			return
+		}
+	}
+}
+
+// legacyTypes maps the message types the client sends to the types of the
+// legacy protocol.
+var legacyTypes = map[string]string{
+	"subscribe": "start",
+	"complete":  "stop",
+}
+
+// write sends the message on the connection.
+func (c *wsConn) write(msg wsMessage) error {
+	if c.legacy {
+		if t, ok := legacyTypes[msg.Type]; ok {
+			msg.Type = t
+		}
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	c.ws.SetWriteDeadline(time.Now().Add(subscriptionWriteTimeout))
+	if err := c.ws.WriteJSON(msg); err != nil {
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return ErrClosed
+		}
+		return &transportError{err: err}
+	}
+	return nil
+}
+
+// close closes the connection. The read loop ends the subscriptions that
+// are left.
+func (c *wsConn) close() {
+	c.closeOnce.Do(func() {
+		close(c.closing)
+
+		c.writeMu.Lock()
+		c.ws.SetWriteDeadline(time.Now().Add(subscriptionWriteTimeout))
+		if c.legacy {
+			c.ws.WriteJSON(wsMessage{Type: "connection_terminate"})
+		}
+		c.ws.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		c.writeMu.Unlock()
+
+		c.ws.Close()
+	})
+}
@@ -0,0 +1,575 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/gorilla/websocket"
+)
+
+// subscriptionServer implements the graphql-transport-ws protocol. Each
+// subscription is sent the number of results in its count variable and is
+// completed, or is kept open when the count is zero. Pings are answered
+// unless noPong is set. When legacy is set the server only speaks the
+// subscriptions-transport-ws protocol and sends a keep alive message every
+// 10ms. When hold is set every connection after the first waits for it to
+// close before acknowledging the handshake.
+type subscriptionServer struct {
+	conns  int32
+	subs   int32
+	pings  int32
+	noPong bool
+	legacy bool
+	hold   chan struct{}
+
+	mu   sync.Mutex
+	init map[string]interface{}
+}
+
+func (s *subscriptionServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	protocol, start, stop, next := "graphql-transport-ws", "subscribe", "complete", "next"
+	if s.legacy {
+		protocol, start, stop, next = "graphql-ws", "start", "stop", "data"
+	}
+
+	upgrader := websocket.Upgrader{Subprotocols: []string{protocol}}
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+	conn := atomic.AddInt32(&s.conns, 1)
+
+	var mu sync.Mutex
+	write := func(v interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		ws.WriteJSON(v)
+	}
+
+	if s.legacy {
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			ticker := time.NewTicker(10 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					write(map[string]string{"type": "ka"})
+				case <-done:
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		var msg struct {
+			ID      string `json:"id"`
+			Type    string `json:"type"`
+			Payload struct {
+				Query     string         `json:"query"`
+				Variables map[string]int `json:"variables"`
+				Token     interface{}    `json:"token"`
+			} `json:"payload"`
+		}
+		if err := ws.ReadJSON(&msg); err != nil {
+			return
+		}
+
+		switch msg.Type {
+		case "connection_init":
+			s.mu.Lock()
+			s.init = map[string]interface{}{"token": msg.Payload.Token}
+			s.mu.Unlock()
+			if s.hold != nil && conn > 1 {
+				<-s.hold
+			}
+			write(map[string]string{"type": "connection_ack"})
+
+		case "ping":
+			atomic.AddInt32(&s.pings, 1)
+			if !s.noPong {
+				write(map[string]string{"type": "pong"})
+			}
+
+		case start:
+			atomic.AddInt32(&s.subs, 1)
+			count := msg.Payload.Variables["count"]
+			go func(id string) {
+				for i := 1; i <= count; i++ {
+					write(map[string]interface{}{"id": id, "type": next, "payload": map[string]interface{}{
+						"data": map[string]int{"tick": i},
+					}})
+				}
+				if count < 0 {
+					write(map[string]interface{}{"id": id, "type": next, "payload": map[string]interface{}{
+						"errors": []map[string]string{{"message": "tick failed"}},
+					}})
+				}
+				if count != 0 {
+					write(map[string]string{"id": id, "type": "complete"})
+				}
+			}(msg.ID)
+
+		case stop:
+			atomic.AddInt32(&s.subs, -1)
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Log("Given the need to receive the results of subscriptions.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a subscription sends results and completes.", testID)
+		{
+			srv := subscriptionServer{}
+			server := httptest.NewServer(&srv)
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+			defer gql.Close()
+
+			sub, err := gql.Subscribe(context.Background(), `subscription($count: Int!) { tick(count: $count) }`, graphql.WithVariable("count", 3))
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to subscribe.", success, testID)
+
+			var got []int
+			for msg := range sub.Messages() {
+				if msg.Err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould receive the results: %v", failed, testID, msg.Err)
+				}
+				var data struct {
+					Tick int `json:"tick"`
+				}
+				json.Unmarshal(msg.Data, &data)
+				got = append(got, data.Tick)
+			}
+
+			if fmt.Sprint(got) != "[1 2 3]" {
+				t.Fatalf("\t%s\tTest %d:\tShould receive every result until completed: %v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould receive every result until completed.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a result has errors.", testID)
+		{
+			srv := subscriptionServer{}
+			server := httptest.NewServer(&srv)
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+			defer gql.Close()
+
+			sub, err := gql.Subscribe(context.Background(), `subscription Ticks($count: Int!) { tick(count: $count) }`, graphql.WithVariable("count", -1))
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+			}
+
+			msg := <-sub.Messages()
+			if !graphql.IsGraphQLError(msg.Err) {
+				t.Fatalf("\t%s\tTest %d:\tShould receive the errors of the result: %v", failed, testID, msg.Err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould receive the errors of the result.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen there are more subscriptions than a connection holds.", testID)
+		{
+			srv := subscriptionServer{}
+			server := httptest.NewServer(&srv)
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithSubscriptionsPerConn(2))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			var subs []*graphql.Subscription
+			for i := 0; i < 5; i++ {
+				sub, err := gql.Subscribe(ctx, `subscription { tick(count: 0) }`)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+				}
+				subs = append(subs, sub)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to subscribe.", success, testID)
+
+			if got := atomic.LoadInt32(&srv.conns); got != 3 {
+				t.Fatalf("\t%s\tTest %d:\tShould spill over to additional connections: %d", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould spill over to additional connections.", success, testID)
+
+			subs[0].Close()
+			if _, ok := <-subs[0].Messages(); ok {
+				t.Fatalf("\t%s\tTest %d:\tShould close the channel of a closed subscription.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould close the channel of a closed subscription.", success, testID)
+
+			cancel()
+			for _, sub := range subs[1:] {
+				select {
+				case _, ok := <-sub.Messages():
+					if ok {
+						t.Fatalf("\t%s\tTest %d:\tShould not receive results.", failed, testID)
+					}
+				case <-time.After(5 * time.Second):
+					t.Fatalf("\t%s\tTest %d:\tShould end the subscriptions when the context is canceled.", failed, testID)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould end the subscriptions when the context is canceled.", success, testID)
+
+			deadline := time.Now().Add(5 * time.Second)
+			for atomic.LoadInt32(&srv.subs) != 0 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if got := atomic.LoadInt32(&srv.subs); got != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould complete the subscriptions on the host: %d", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould complete the subscriptions on the host.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a connection is dialed while another is in use.", testID)
+		{
+			srv := subscriptionServer{hold: make(chan struct{})}
+			server := httptest.NewServer(&srv)
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithSubscriptionsPerConn(1))
+			defer gql.Close()
+
+			first, err := gql.Subscribe(context.Background(), `subscription { tick(count: 0) }`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+			}
+
+			dialed := make(chan error, 1)
+			go func() {
+				_, err := gql.Subscribe(context.Background(), `subscription { tick(count: 0) }`)
+				dialed <- err
+			}()
+
+			deadline := time.Now().Add(5 * time.Second)
+			for atomic.LoadInt32(&srv.conns) != 2 && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+
+			closed := make(chan struct{})
+			go func() {
+				first.Close()
+				close(closed)
+			}()
+
+			select {
+			case <-closed:
+			case <-time.After(time.Second):
+				t.Fatalf("\t%s\tTest %d:\tShould close a subscription while another connection is dialed.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould close a subscription while another connection is dialed.", success, testID)
+
+			close(srv.hold)
+			if err := <-dialed; err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould subscribe once the connection is acknowledged: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould subscribe once the connection is acknowledged.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the client is closed.", testID)
+		{
+			srv := subscriptionServer{}
+			server := httptest.NewServer(&srv)
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			sub, err := gql.Subscribe(context.Background(), `subscription { tick(count: 0) }`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+			}
+
+			gql.Close()
+			select {
+			case _, ok := <-sub.Messages():
+				if ok {
+					t.Fatalf("\t%s\tTest %d:\tShould not receive results.", failed, testID)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("\t%s\tTest %d:\tShould end the subscription when the client closes.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould end the subscription when the client closes.", success, testID)
+		}
+	}
+}
+
+func TestSubscribeTyped(t *testing.T) {
+	t.Log("Given the need to receive decoded subscription events.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a subscription sends results and completes.", testID)
+		{
+			srv := subscriptionServer{}
+			server := httptest.NewServer(&srv)
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+			defer gql.Close()
+
+			type tick struct {
+				Tick int `json:"tick"`
+			}
+			events, err := graphql.Subscribe[tick](context.Background(), gql, `subscription($count: Int!) { tick(count: $count) }`, graphql.WithVariable("count", 2))
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to subscribe.", success, testID)
+
+			var got []string
+			for event := range events {
+				if event.Err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould receive the events: %v", failed, testID, event.Err)
+				}
+				got = append(got, fmt.Sprintf("%s:%d", event.Kind, event.Data.Tick))
+			}
+
+			if exp := "[connected:0 data:1 data:2 completed:0]"; fmt.Sprint(got) != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould receive the lifecycle and decoded results: got %v, exp %s", failed, testID, got, exp)
+			}
+			t.Logf("\t%s\tTest %d:\tShould receive the lifecycle and decoded results.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the client is closed.", testID)
+		{
+			srv := subscriptionServer{}
+			server := httptest.NewServer(&srv)
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			events, err := graphql.Subscribe[json.RawMessage](context.Background(), gql, `subscription { tick(count: 0) }`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+			}
+
+			if event := <-events; event.Kind != graphql.SubscriptionConnected {
+				t.Fatalf("\t%s\tTest %d:\tShould receive the connected event: %+v", failed, testID, event)
+			}
+
+			gql.Close()
+			event := <-events
+			if event.Kind != graphql.SubscriptionDisconnected || event.Err != graphql.ErrClosed {
+				t.Fatalf("\t%s\tTest %d:\tShould receive the disconnected event: %+v", failed, testID, event)
+			}
+			if _, ok := <-events; ok {
+				t.Fatalf("\t%s\tTest %d:\tShould close the channel after the last event.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould receive the disconnected event.", success, testID)
+		}
+	}
+}
+
+func TestSubscriptionOptions(t *testing.T) {
+	t.Log("Given the need to configure the connections of subscriptions.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen an init payload and keepalive are configured.", testID)
+		{
+			srv := subscriptionServer{}
+			server := httptest.NewServer(&srv)
+			defer server.Close()
+
+			gql := graphql.New(server.URL,
+				graphql.WithSubscriptionInitPayload(func(ctx context.Context) map[string]interface{} {
+					return map[string]interface{}{"token": "secret"}
+				}),
+				graphql.WithSubscriptionKeepAlive(10*time.Millisecond, time.Second),
+				graphql.WithSubscriptionReadLimit(1<<20),
+				graphql.WithSubscriptionCompression(),
+			)
+			defer gql.Close()
+
+			sub, err := gql.Subscribe(context.Background(), `subscription { tick(count: 0) }`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+			}
+			defer sub.Close()
+			t.Logf("\t%s\tTest %d:\tShould be able to subscribe.", success, testID)
+
+			srv.mu.Lock()
+			token := srv.init["token"]
+			srv.mu.Unlock()
+			if token != "secret" {
+				t.Fatalf("\t%s\tTest %d:\tShould send the init payload: %v", failed, testID, token)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send the init payload.", success, testID)
+
+			time.Sleep(100 * time.Millisecond)
+			if got := atomic.LoadInt32(&srv.pings); got < 3 {
+				t.Fatalf("\t%s\tTest %d:\tShould send pings at the interval: %d", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould send pings at the interval.", success, testID)
+
+			select {
+			case msg, ok := <-sub.Messages():
+				t.Fatalf("\t%s\tTest %d:\tShould keep the connection open: %v %v", failed, testID, msg, ok)
+			default:
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep the connection open.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the pong doesn't arrive.", testID)
+		{
+			srv := subscriptionServer{noPong: true}
+			server := httptest.NewServer(&srv)
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithSubscriptionKeepAlive(10*time.Millisecond, 20*time.Millisecond))
+			defer gql.Close()
+
+			sub, err := gql.Subscribe(context.Background(), `subscription { tick(count: 0) }`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+			}
+
+			select {
+			case msg := <-sub.Messages():
+				if !graphql.IsTransportError(msg.Err) {
+					t.Fatalf("\t%s\tTest %d:\tShould fail the connection: %v", failed, testID, msg.Err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("\t%s\tTest %d:\tShould fail the connection.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould fail the connection.", success, testID)
+		}
+	}
+}
+
+func TestSubscriptionBuffer(t *testing.T) {
+	t.Log("Given the need to protect the connection from slow consumers.")
+	{
+		tests := []struct {
+			name   string
+			policy graphql.BufferPolicy
+			exp    string
+		}{
+			{"drop oldest", graphql.BufferDropOldest, "[46 47 48 49 50]"},
+			{"drop newest", graphql.BufferDropNewest, "[1 2 3 4 5]"},
+		}
+
+		for testID, test := range tests {
+			t.Logf("\tTest %d:\tWhen the consumer is slow with the %s policy.", testID, test.name)
+			{
+				srv := subscriptionServer{}
+				server := httptest.NewServer(&srv)
+				defer server.Close()
+
+				gql := graphql.New(server.URL)
+				defer gql.Close()
+
+				ctx := graphql.SubscriptionBuffer(context.Background(), 5, test.policy)
+				sub, err := gql.Subscribe(ctx, `subscription($count: Int!) { tick(count: $count) }`, graphql.WithVariable("count", 50))
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+				}
+
+				deadline := time.Now().Add(5 * time.Second)
+				for sub.Dropped() < 45 && time.Now().Before(deadline) {
+					time.Sleep(10 * time.Millisecond)
+				}
+				if got := sub.Dropped(); got != 45 {
+					t.Fatalf("\t%s\tTest %d:\tShould drop the results that don't fit: %d", failed, testID, got)
+				}
+				t.Logf("\t%s\tTest %d:\tShould drop the results that don't fit.", success, testID)
+
+				var got []int
+				for msg := range sub.Messages() {
+					var data struct {
+						Tick int `json:"tick"`
+					}
+					json.Unmarshal(msg.Data, &data)
+					got = append(got, data.Tick)
+				}
+				if fmt.Sprint(got) != test.exp {
+					t.Fatalf("\t%s\tTest %d:\tShould keep the expected results: got %v, exp %s", failed, testID, got, test.exp)
+				}
+				t.Logf("\t%s\tTest %d:\tShould keep the expected results.", success, testID)
+			}
+		}
+	}
+}
+
+func TestSubscriptionLegacyProtocol(t *testing.T) {
+	t.Log("Given the need to subscribe through gateways using the legacy protocol.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the host only supports subscriptions-transport-ws.", testID)
+		{
+			srv := subscriptionServer{legacy: true}
+			server := httptest.NewServer(&srv)
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithSubscriptionKeepAlive(10*time.Millisecond, 100*time.Millisecond))
+			defer gql.Close()
+
+			sub, err := gql.Subscribe(context.Background(), `subscription($count: Int!) { tick(count: $count) }`, graphql.WithVariable("count", 3))
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to subscribe.", success, testID)
+
+			var got []int
+			for msg := range sub.Messages() {
+				if msg.Err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould receive the results: %v", failed, testID, msg.Err)
+				}
+				var data struct {
+					Tick int `json:"tick"`
+				}
+				json.Unmarshal(msg.Data, &data)
+				got = append(got, data.Tick)
+			}
+			if fmt.Sprint(got) != "[1 2 3]" {
+				t.Fatalf("\t%s\tTest %d:\tShould receive every result until completed: %v", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould receive every result until completed.", success, testID)
+
+			completed := atomic.LoadInt32(&srv.subs)
+			sub, err = gql.Subscribe(context.Background(), `subscription { tick(count: 0) }`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to subscribe: %v", failed, testID, err)
+			}
+
+			time.Sleep(100 * time.Millisecond)
+			select {
+			case msg, ok := <-sub.Messages():
+				t.Fatalf("\t%s\tTest %d:\tShould keep the connection alive with the keep alive messages: %v %v", failed, testID, msg, ok)
+			default:
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep the connection alive with the keep alive messages.", success, testID)
+
+			sub.Close()
+			deadline := time.Now().Add(5 * time.Second)
+			for atomic.LoadInt32(&srv.subs) != completed && time.Now().Before(deadline) {
+				time.Sleep(10 * time.Millisecond)
+			}
+			if got := atomic.LoadInt32(&srv.subs); got != completed {
+				t.Fatalf("\t%s\tTest %d:\tShould stop the subscription on the host: %d", failed, testID, got)
+			}
+			t.Logf("\t%s\tTest %d:\tShould stop the subscription on the host.", success, testID)
+		}
+	}
+}
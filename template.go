@@ -0,0 +1,495 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// QueryTemplate renders graphql documents from a text/template where every
+// value is interpolated through a graphql aware escaping function. Use it
+// when variables can't be used, like for dynamic field names, instead of
+// building documents with fmt.Sprintf. Prefer variables for values.
+//
+// The escaping functions are:
+//
+//	str    a string literal
+//	id     an ID as a string literal, from a string or integer
+//	int    an integer literal
+//	float  a float literal
+//	bool   a boolean literal
+//	enum   an enum value, which must be a valid name and not true, false or null
+//	name   a field, alias, or argument name, which must be a valid name
+//	value  a literal for any value, with maps and structs as input objects
+//
+// Actions that output a value without ending in one of these functions are
+// rejected when the template is parsed.
+type QueryTemplate struct {
+	tmpl *template.Template
+}
+
+// templateFuncs are the escaping functions available to templates.
+var templateFuncs = template.FuncMap{
+	"str":   templateString,
+	"id":    templateID,
+	"int":   templateInt,
+	"float": templateFloat,
+	"bool":  templateBool,
+	"enum":  templateEnum,
+	"name":  templateName,
+	"value": templateValue,
+}
+
+// NewQueryTemplate parses the template text.
+func NewQueryTemplate(name string, text string) (*QueryTemplate, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("graphql template error: %w", err)
+	}
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil {
+			continue
+		}
+		if err := checkEscaped(t.Tree.Root); err != nil {
+			return nil, fmt.Errorf("graphql template error: %s: %w", t.Name(), err)
+		}
+	}
+
+	return &QueryTemplate{tmpl: tmpl}, nil
+}
+
+// MustQueryTemplate parses the template text and panics if it's invalid.
+// This is intended for templates declared as package variables.
+func MustQueryTemplate(name string, text string) *QueryTemplate {
+	qt, err := NewQueryTemplate(name, text)
+	if err != nil {
+		panic(err)
+	}
+	return qt
+}
+
+// Render executes the template with the data and returns the document.
+func (qt *QueryTemplate) Render(data interface{}) (string, error) {
+	var b strings.Builder
+	if err := qt.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("graphql template error: %w", err)
+	}
+	return b.String(), nil
+}
+
+// checkEscaped verifies that every action that outputs a value ends with
+// an escaping function.
+func checkEscaped(node parse.Node) error {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkEscaped(child); err != nil {
+				return err
+			}
+		}
+
+	case *parse.ActionNode:
+		if len(n.Pipe.Decl) > 0 || len(n.Pipe.Cmds) == 0 {
+			return nil
+		}
+		last := n.Pipe.Cmds[len(n.Pipe.Cmds)-1]
+		if ident, ok := last.Args[0].(*parse.IdentifierNode); ok {
+			if _, ok := templateFuncs[ident.Ident]; ok {
+				return nil
+			}
+		}
+		return fmt.Errorf("action %s at line %d must end with an escaping function", n, n.Line)
+
+	case *parse.IfNode:
+		return checkBranch(&n.BranchNode)
+	case *parse.RangeNode:
+		return checkBranch(&n.BranchNode)
+	case *parse.WithNode:
+		return checkBranch(&n.BranchNode)
+	}
+
+	return nil
+}
+
+// checkBranch checks both lists of a branch node.
+func checkBranch(n *parse.BranchNode) error {
+	if err := checkEscaped(n.List); err != nil {
+		return err
+	}
+	if n.ElseList != nil {
+		return checkEscaped(n.ElseList)
+	}
+	return nil
+}
+
+// =============================================================================
+
+// nameRE matches a valid graphql name.
+var nameRE = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// templateString escapes a string literal.
+func templateString(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		if st, ok := v.(fmt.Stringer); ok {
+			s = st.String()
+		} else {
+			return "", fmt.Errorf("str: %T is not a string", v)
+		}
+	}
+	return quote(s), nil
+}
+
+// templateID escapes an ID as a string literal.
+func templateID(v interface{}) (string, error) {
+	switch id := v.(type) {
+	case string:
+		return quote(id), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return quote(fmt.Sprint(id)), nil
+	}
+	return "", fmt.Errorf("id: %T is not a string or integer", v)
+}
+
+// templateInt formats an integer literal.
+func templateInt(v interface{}) (string, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("int: %T is not an integer", v)
+}
+
+// templateFloat formats a float literal.
+func templateFloat(v interface{}) (string, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, 64), nil
+	}
+	if s, err := templateInt(v); err == nil {
+		return s, nil
+	}
+	return "", fmt.Errorf("float: %T is not a number", v)
+}
+
+// templateBool formats a boolean literal.
+func templateBool(v interface{}) (string, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return "", fmt.Errorf("bool: %T is not a bool", v)
+	}
+	return strconv.FormatBool(b), nil
+}
+
+// templateEnum verifies the value is a valid enum value.
+func templateEnum(v interface{}) (string, error) {
+	s := fmt.Sprint(v)
+	if !nameRE.MatchString(s) || s == "true" || s == "false" || s == "null" {
+		return "", fmt.Errorf("enum: %q is not a valid enum value", s)
+	}
+	return s, nil
+}
+
+// templateName verifies the value is a valid name.
+func templateName(v interface{}) (string, error) {
+	s := fmt.Sprint(v)
+	if !nameRE.MatchString(s) {
+		return "", fmt.Errorf("name: %q is not a valid name", s)
+	}
+	return s, nil
+}
+
+// templateValue formats any value as a literal.
+func templateValue(v interface{}) (string, error) {
+	lit, err := Literal(v)
+	if err != nil {
+		return "", fmt.Errorf("value: %w", err)
+	}
+	return lit, nil
+}
+
+// =============================================================================
+
+// Enum is implemented by enum types so their values are written as enum
+// values rather than strings in documents. In variables they are sent as
+// strings like any other string type. Enum types generated by graphqlgen
+// implement it.
+type Enum interface {
+	GraphQLEnum() string
+}
+
+// enumLiteral represents an enum value in a literal tree.
+type enumLiteral string
+
+// Literal formats the value as a graphql literal, the form a value takes
+// when it's written in a document instead of sent as a variable. Maps and
+// structs are written as input objects using their json field names, Enum
+// values are written without quotes, and values that implement
+// json.Marshaler are written as their JSON form.
+func Literal(v interface{}) (string, error) {
+	tree, err := literalTree(reflect.ValueOf(v), nil)
+	if err != nil {
+		return "", fmt.Errorf("graphql literal error: %w", err)
+	}
+
+	var b strings.Builder
+	if err := writeLiteral(&b, tree); err != nil {
+		return "", fmt.Errorf("graphql literal error: %w", err)
+	}
+	return b.String(), nil
+}
+
+var (
+	enumType      = reflect.TypeOf((*Enum)(nil)).Elem()
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// literalTree converts the value into the tree of nil, bool, json.Number,
+// string, enumLiteral, []interface{}, and map[string]interface{} values
+// that writeLiteral writes. The convert function, when set, replaces the
+// values it applies to.
+func literalTree(rv reflect.Value, convert func(rv reflect.Value) (interface{}, bool)) (interface{}, error) {
+	if !rv.IsValid() {
+		return nil, nil
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+		if rv.IsNil() {
+			return nil, nil
+		}
+	}
+
+	if convert != nil {
+		if value, ok := convert(rv); ok {
+			return value, nil
+		}
+	}
+
+	t := rv.Type()
+	switch {
+	case t.Implements(enumType):
+		value := rv.Interface().(Enum).GraphQLEnum()
+		if _, err := templateEnum(value); err != nil {
+			return nil, err
+		}
+		return enumLiteral(value), nil
+
+	case t.Implements(jsonMarshaler) || t.Implements(textMarshaler):
+		return jsonTree(rv.Interface())
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return literalTree(rv.Elem(), convert)
+
+	case reflect.Bool:
+		return rv.Bool(), nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return json.Number(strconv.FormatInt(rv.Int(), 10)), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return json.Number(strconv.FormatUint(rv.Uint(), 10)), nil
+
+	case reflect.Float32, reflect.Float64:
+		return json.Number(strconv.FormatFloat(rv.Float(), 'g', -1, 64)), nil
+
+	case reflect.String:
+		return rv.String(), nil
+
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return jsonTree(rv.Interface())
+		}
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			item, err := literalTree(rv.Index(i), convert)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = item
+		}
+		return list, nil
+
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return jsonTree(rv.Interface())
+		}
+		obj := make(map[string]interface{}, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			value, err := literalTree(iter.Value(), convert)
+			if err != nil {
+				return nil, err
+			}
+			obj[iter.Key().String()] = value
+		}
+		return obj, nil
+
+	case reflect.Struct:
+		obj := make(map[string]interface{})
+		if err := structLiteral(rv, obj, convert); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+
+	return nil, fmt.Errorf("%s can't be written as a literal", t)
+}
+
+// structLiteral adds the fields of the struct to the input object, using
+// the json field names and flattening embedded structs.
+func structLiteral(rv reflect.Value, obj map[string]interface{}, convert func(rv reflect.Value) (interface{}, bool)) error {
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		tag := strings.Split(field.Tag.Get("json"), ",")
+		if tag[0] == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if field.Anonymous && tag[0] == "" {
+			for fv.Kind() == reflect.Ptr && !fv.IsNil() {
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := structLiteral(fv, obj, convert); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		omitEmpty := false
+		for _, opt := range tag[1:] {
+			omitEmpty = omitEmpty || opt == "omitempty"
+		}
+		if omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+
+		name := tag[0]
+		if name == "" {
+			name = field.Name
+		}
+
+		value, err := literalTree(fv, convert)
+		if err != nil {
+			return err
+		}
+		obj[name] = value
+	}
+	return nil
+}
+
+// isEmptyValue reports whether the value is empty as encoding/json defines
+// it for the omitempty option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// jsonTree converts the value to its JSON form and decodes that into a
+// literal tree. Numbers are kept as written so large integers don't lose
+// precision.
+func jsonTree(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+
+	var tree interface{}
+	if err := d.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
+
+// writeLiteral writes the literal tree as a graphql literal.
+func writeLiteral(b *strings.Builder, v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		b.WriteString("null")
+	case bool:
+		b.WriteString(strconv.FormatBool(v))
+	case json.Number:
+		b.WriteString(v.String())
+	case string:
+		b.WriteString(quote(v))
+	case enumLiteral:
+		b.WriteString(string(v))
+	case []interface{}:
+		b.WriteString("[")
+		for i, item := range v {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if err := writeLiteral(b, item); err != nil {
+				return err
+			}
+		}
+		b.WriteString("]")
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			if !nameRE.MatchString(key) {
+				return fmt.Errorf("%q is not a valid field name", key)
+			}
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		b.WriteString("{")
+		for i, key := range keys {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString(key + ": ")
+			if err := writeLiteral(b, v[key]); err != nil {
+				return err
+			}
+		}
+		b.WriteString("}")
+	}
+	return nil
+}
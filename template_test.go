@@ -0,0 +1,110 @@
+package graphql_test
+
+import (
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+// size is an enum type for the tests.
+type size string
+
+// GraphQLEnum implements the graphql.Enum interface.
+func (s size) GraphQLEnum() string {
+	return string(s)
+}
+
+func TestQueryTemplate(t *testing.T) {
+	t.Log("Given the need to render documents with values that can't be variables.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen rendering values through the escaping functions.", testID)
+		{
+			qt, err := graphql.NewQueryTemplate("cities", `query {
+	{{name .Field}}(name: {{str .Name}}, id: {{id .ID}}, size: {{enum .Size}}, first: {{int .First}}, filter: {{value .Filter}}) {
+		{{range .Fields}}{{name .}} {{end}}
+	}
+}`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to parse the template: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to parse the template.", success, testID)
+
+			data := map[string]interface{}{
+				"Field":  "queryCity",
+				"Name":   `Mi"ami\`,
+				"ID":     12345678901234,
+				"Size":   "LARGE",
+				"First":  10,
+				"Filter": map[string]interface{}{"population": map[string]interface{}{"gt": 9007199254740993}, "tags": []string{"a"}},
+				"Fields": []string{"name", "population"},
+			}
+
+			got, err := qt.Render(data)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to render the template: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to render the template.", success, testID)
+
+			exp := `query {
+	queryCity(name: "Mi\"ami\\", id: "12345678901234", size: LARGE, first: 10, filter: {population: {gt: 9007199254740993}, tags: ["a"]}) {
+		name population 
+	}
+}`
+			if diff := cmp.Diff(got, exp); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the expected document. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the expected document.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen writing enum values in an input object.", testID)
+		{
+			type filter struct {
+				Size    size     `json:"size"`
+				Sizes   []size   `json:"sizes"`
+				Name    string   `json:"name,omitempty"`
+				Exclude *string  `json:"exclude"`
+				Tags    []string `json:"-"`
+			}
+
+			got, err := graphql.Literal(filter{Size: "LARGE", Sizes: []size{"SMALL", "HUGE"}})
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to write the literal: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to write the literal.", success, testID)
+
+			exp := `{exclude: null, size: LARGE, sizes: [SMALL, HUGE]}`
+			if diff := cmp.Diff(got, exp); diff != "" {
+				t.Fatalf("\t%s\tTest %d:\tShould write enum values without quotes. Diff:\n%s", failed, testID, diff)
+			}
+			t.Logf("\t%s\tTest %d:\tShould write enum values without quotes.", success, testID)
+
+			if _, err := graphql.Literal(size("LARGE) { admin")); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould reject an invalid enum value.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject an invalid enum value.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a value tries to inject into the document.", testID)
+		{
+			qt := graphql.MustQueryTemplate("inject", `{ queryCity { {{name .}} } }`)
+
+			if _, err := qt.Render(`name } admin { password`); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould reject the value.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject the value.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen an action doesn't escape its value.", testID)
+		{
+			if _, err := graphql.NewQueryTemplate("raw", `{ queryCity(name: "{{if .}}{{.}}{{end}}") { name } }`); err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould reject the template.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject the template.", success, testID)
+		}
+	}
+}
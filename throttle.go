@@ -0,0 +1,122 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WithRateLimitThrottling paces requests to stay within the rate limit the
+// host reports. The GitHub X-RateLimit-Remaining and X-RateLimit-Reset
+// headers pause requests until the limit resets once it's exhausted, and
+// the Shopify cost.throttleStatus extension delays requests until enough
+// points have been restored for the cost of the previous query.
+func WithRateLimitThrottling() func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.throttle = &throttler{}
+	}
+}
+
+// throttler tracks the rate limit budget reported by the host.
+type throttler struct {
+	mu sync.Mutex
+
+	// GitHub style limits reset at a point in time.
+	resetAt time.Time
+
+	// Shopify style limits restore points continuously.
+	available   float64
+	restoreRate float64
+	lastCost    float64
+	updated     time.Time
+}
+
+// delay returns how long to wait before sending the next request.
+func (t *throttler) delay(now time.Time) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var d time.Duration
+	if now.Before(t.resetAt) {
+		d = t.resetAt.Sub(now)
+	}
+
+	if t.restoreRate > 0 && t.lastCost > 0 {
+		available := t.available + now.Sub(t.updated).Seconds()*t.restoreRate
+		if short := t.lastCost - available; short > 0 {
+			if pd := time.Duration(short / t.restoreRate * float64(time.Second)); pd > d {
+				d = pd
+			}
+		}
+	}
+
+	return d
+}
+
+// wait blocks until the next request can be sent within the budget.
+func (t *throttler) wait(ctx context.Context, g *GraphQL) error {
+	d := t.delay(time.Now())
+	if d <= 0 {
+		return nil
+	}
+
+	if log := g.logger(ctx); log != nil {
+		log(fmt.Sprintf("rate limit: pausing for %s", d))
+	}
+
+	if err := sleep(ctx, d); err != nil {
+		return fmt.Errorf("graphql request error: %w", err)
+	}
+	return nil
+}
+
+// updateHeader records the budget reported in GitHub style headers.
+func (t *throttler) updateHeader(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.resetAt = time.Time{}
+	if remaining > 0 {
+		return
+	}
+
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		t.resetAt = time.Unix(reset, 0)
+	}
+}
+
+// updateExtensions records the budget reported in the Shopify style cost
+// extension.
+func (t *throttler) updateExtensions(extensions json.RawMessage, now time.Time) {
+	var ext struct {
+		Cost *struct {
+			RequestedQueryCost float64  `json:"requestedQueryCost"`
+			ActualQueryCost    *float64 `json:"actualQueryCost"`
+			ThrottleStatus     struct {
+				MaximumAvailable   float64 `json:"maximumAvailable"`
+				CurrentlyAvailable float64 `json:"currentlyAvailable"`
+				RestoreRate        float64 `json:"restoreRate"`
+			} `json:"throttleStatus"`
+		} `json:"cost"`
+	}
+	if err := json.Unmarshal(extensions, &ext); err != nil || ext.Cost == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.available = ext.Cost.ThrottleStatus.CurrentlyAvailable
+	t.restoreRate = ext.Cost.ThrottleStatus.RestoreRate
+	t.lastCost = ext.Cost.RequestedQueryCost
+	t.updated = now
+}
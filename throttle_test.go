@@ -0,0 +1,79 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestRateLimitThrottling(t *testing.T) {
+	t.Log("Given the need to stay within the rate limit of a host.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen a GitHub style limit is exhausted.", testID)
+		{
+			var calls []time.Time
+			f := func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, time.Now())
+				if len(calls) == 1 {
+					w.Header().Set("X-RateLimit-Remaining", "0")
+					w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Second).Unix(), 10))
+				}
+				io.WriteString(w, `{"data": {"viewer": {"login": "octocat"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithRateLimitThrottling())
+
+			for i := 0; i < 2; i++ {
+				if err := gql.Execute(context.Background(), `query { viewer { login } }`, nil); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+			}
+
+			// The reset is in whole seconds so the pause can be shorter than a second.
+			if calls[1].Sub(calls[0]) < 10*time.Millisecond {
+				t.Fatalf("\t%s\tTest %d:\tShould pause until the limit resets: %s", failed, testID, calls[1].Sub(calls[0]))
+			}
+			t.Logf("\t%s\tTest %d:\tShould pause until the limit resets.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen a Shopify style budget is too low for the next query.", testID)
+		{
+			var calls []time.Time
+			f := func(w http.ResponseWriter, r *http.Request) {
+				calls = append(calls, time.Now())
+				io.WriteString(w, `{"data": {"shop": {"name": "store"}}, "extensions": {"cost": {
+					"requestedQueryCost": 100, "actualQueryCost": 100,
+					"throttleStatus": {"maximumAvailable": 1000, "currentlyAvailable": 90, "restoreRate": 50}
+				}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithRateLimitThrottling())
+
+			for i := 0; i < 2; i++ {
+				if err := gql.Execute(context.Background(), `query { shop { name } }`, nil); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+			}
+
+			// 10 points are missing at 50 points a second.
+			if d := calls[1].Sub(calls[0]); d < 150*time.Millisecond {
+				t.Fatalf("\t%s\tTest %d:\tShould wait for the points to restore: %s", failed, testID, d)
+			}
+			t.Logf("\t%s\tTest %d:\tShould wait for the points to restore.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// TimeFormat specifies how time.Time and time.Duration values are written
+// in variables and read from responses. Any value other than TimeUnixMillis
+// is a layout for the time package.
+type TimeFormat string
+
+// Set of supported time formats.
+const (
+	TimeRFC3339     TimeFormat = time.RFC3339
+	TimeRFC3339Nano TimeFormat = time.RFC3339Nano
+	TimeUnixMillis  TimeFormat = "unixmillis"
+)
+
+// WithTimeFormat sets the format used for time.Time and time.Duration
+// values in the variables and data of every request, so scalars like
+// Dgraph's DateTime can be used without wrapper types. Times are written
+// and read using the layout, or as milliseconds since the unix epoch with
+// TimeUnixMillis. Durations are written as strings like 1h30m0s, or as
+// milliseconds with TimeUnixMillis, and are read from either form.
+func WithTimeFormat(format TimeFormat) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.timeFormat = format
+		gql.decodeHooks = append(gql.decodeHooks[:len(gql.decodeHooks):len(gql.decodeHooks)], format.decodeHook)
+	}
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// formatVariables returns a copy of the variables with the time.Time and
+// time.Duration values written in the configured format.
+func (g *GraphQL) formatVariables(queryVars map[string]interface{}) (map[string]interface{}, error) {
+	if g.timeFormat == "" || len(queryVars) == 0 {
+		return queryVars, nil
+	}
+
+	tree, err := literalTree(reflect.ValueOf(queryVars), g.timeFormat.encode)
+	if err != nil {
+		return nil, fmt.Errorf("graphql encoding error: %w", err)
+	}
+	return tree.(map[string]interface{}), nil
+}
+
+// encode writes the time.Time and time.Duration values in the format.
+func (f TimeFormat) encode(rv reflect.Value) (interface{}, bool) {
+	switch rv.Type() {
+	case timeType:
+		t := rv.Interface().(time.Time)
+		if f == TimeUnixMillis {
+			return json.Number(strconv.FormatInt(t.UnixMilli(), 10)), true
+		}
+		return t.Format(string(f)), true
+
+	case durationType:
+		d := rv.Interface().(time.Duration)
+		if f == TimeUnixMillis {
+			return json.Number(strconv.FormatInt(d.Milliseconds(), 10)), true
+		}
+		return d.String(), true
+	}
+
+	return nil, false
+}
+
+// decodeHook reads the time.Time and time.Duration values in the format.
+func (f TimeFormat) decodeHook(value interface{}, target reflect.Type) (interface{}, error) {
+	switch target {
+	case timeType:
+		switch v := value.(type) {
+		case string:
+			if f == TimeUnixMillis {
+				return unixMillis(v)
+			}
+			return time.Parse(string(f), v)
+		case json.Number:
+			return unixMillis(string(v))
+		}
+
+	case durationType:
+		switch v := value.(type) {
+		case string:
+			return time.ParseDuration(v)
+		case json.Number:
+			ms, err := v.Int64()
+			if err != nil {
+				return nil, fmt.Errorf("converting %s to duration: %w", v, err)
+			}
+			return time.Duration(ms) * time.Millisecond, nil
+		}
+	}
+
+	return value, nil
+}
+
+// unixMillis converts milliseconds since the unix epoch to a time.
+func unixMillis(s string) (time.Time, error) {
+	ms, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("converting %q to time: %w", s, err)
+	}
+	return time.UnixMilli(ms), nil
+}
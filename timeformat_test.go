@@ -0,0 +1,100 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTimeFormat(t *testing.T) {
+	t.Log("Given the need to use time values with the DateTime scalar.")
+	{
+		founded := time.Date(2021, 3, 4, 5, 6, 7, 890000000, time.UTC)
+
+		type input struct {
+			Name    string         `json:"name"`
+			Founded time.Time      `json:"founded"`
+			Updated *time.Time     `json:"updated,omitempty"`
+			Window  time.Duration  `json:"window"`
+			Tags    map[string]int `json:"tags,omitempty"`
+		}
+
+		type response struct {
+			City struct {
+				Founded time.Time     `json:"founded"`
+				Updated *time.Time    `json:"updated"`
+				Window  time.Duration `json:"window"`
+			} `json:"city"`
+		}
+
+		tests := []struct {
+			name   string
+			format graphql.TimeFormat
+			exp    string
+			data   string
+		}{
+			{
+				"RFC3339",
+				graphql.TimeRFC3339,
+				`{"query":"mutation","variables":{"at":"2021-03-04T05:06:07Z","city":{"founded":"2021-03-04T05:06:07Z","name":"Miami","window":"1h30m0s"}}}` + "\n",
+				`{"data": {"city": {"founded": "2021-03-04T05:06:07Z", "updated": "2021-03-04T05:06:07Z", "window": "1h30m0s"}}}`,
+			},
+			{
+				"RFC3339Nano",
+				graphql.TimeRFC3339Nano,
+				`{"query":"mutation","variables":{"at":"2021-03-04T05:06:07.89Z","city":{"founded":"2021-03-04T05:06:07.89Z","name":"Miami","window":"1h30m0s"}}}` + "\n",
+				`{"data": {"city": {"founded": "2021-03-04T05:06:07.89Z", "updated": "2021-03-04T05:06:07.89Z", "window": 5400000}}}`,
+			},
+			{
+				"unix millis",
+				graphql.TimeUnixMillis,
+				`{"query":"mutation","variables":{"at":1614834367890,"city":{"founded":1614834367890,"name":"Miami","window":5400000}}}` + "\n",
+				`{"data": {"city": {"founded": 1614834367890, "updated": "1614834367890", "window": 5400000}}}`,
+			},
+		}
+
+		for testID, test := range tests {
+			t.Logf("\tTest %d:\tWhen using the %s format.", testID, test.name)
+			{
+				f := func(w http.ResponseWriter, r *http.Request) {
+					b, _ := ioutil.ReadAll(r.Body)
+					if diff := cmp.Diff(string(b), test.exp); diff != "" {
+						t.Errorf("\t%s\tTest %d:\tShould write the variables in the format. Diff:\n%s", failed, testID, diff)
+					}
+					io.WriteString(w, test.data)
+				}
+
+				server := httptest.NewServer(http.HandlerFunc(f))
+				defer server.Close()
+
+				gql := graphql.New(server.URL, graphql.WithTimeFormat(test.format))
+
+				var got response
+				err := gql.Execute(context.Background(), "mutation", &got,
+					graphql.WithVariable("at", founded),
+					graphql.WithVariable("city", input{Name: "Miami", Founded: founded, Window: 90 * time.Minute}),
+				)
+				if err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the request: %v", failed, testID, err)
+				}
+				t.Logf("\t%s\tTest %d:\tShould be able to execute the request.", success, testID)
+
+				exp := founded
+				if test.format == graphql.TimeRFC3339 {
+					exp = founded.Truncate(time.Second)
+				}
+				if !got.City.Founded.Equal(exp) || got.City.Updated == nil || !got.City.Updated.Equal(exp) || got.City.Window != 90*time.Minute {
+					t.Fatalf("\t%s\tTest %d:\tShould read the values in the format: %+v", failed, testID, got)
+				}
+				t.Logf("\t%s\tTest %d:\tShould read the values in the format.", success, testID)
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestTimeout(t *testing.T) {
+	t.Log("Given the need to bound requests to a stalled host.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the caller didn't set a deadline.", testID)
+		{
+			release := make(chan struct{})
+			f := func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case <-release:
+				case <-r.Context().Done():
+				}
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+			defer close(release)
+
+			gql := graphql.New(server.URL, graphql.WithTimeout(50*time.Millisecond))
+
+			start := time.Now()
+			err := gql.Execute(context.Background(), `query { __typename }`, nil)
+
+			if !errors.Is(err, graphql.ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("\t%s\tTest %d:\tShould get a timeout error: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get a timeout error.", success, testID)
+
+			if time.Since(start) > 2*time.Second {
+				t.Fatalf("\t%s\tTest %d:\tShould apply the default deadline.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould apply the default deadline.", success, testID)
+		}
+	}
+}
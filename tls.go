@@ -0,0 +1,160 @@
+package graphql
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// WithTLSConfig sets the TLS configuration used to connect to the host. The
+// client gets its own transport so other clients aren't affected. The
+// transport is a copy of the one of the http client, whether or not it's
+// provided with WithClient before or after this option, and must be an
+// *http.Transport.
+func WithTLSConfig(cfg *tls.Config) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.transportOpts = append(gql.transportOpts, func(t *http.Transport) {
+			t.TLSClientConfig = cfg
+		})
+	}
+}
+
+// WithClientCertificates configures mutual TLS using the PEM encoded client
+// certificate and key files, and the optional CA file used to verify the
+// host. The certificate is reloaded from disk when the files change, so
+// rotated certificates are picked up without a restart. Problems reading
+// the files are reported by NewE and by every request. Like WithTLSConfig,
+// the client gets its own copy of the transport of the http client.
+func WithClientCertificates(certFile string, keyFile string, caFile string) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		cfg := tls.Config{
+			MinVersion: tls.VersionTLS12,
+		}
+
+		if caFile != "" {
+			pem, err := ioutil.ReadFile(caFile)
+			if err != nil {
+				gql.configErr = fmt.Errorf("graphql config error: reading CA file: %w", err)
+				return
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				gql.configErr = fmt.Errorf("graphql config error: no certificates found in CA file %s", caFile)
+				return
+			}
+			cfg.RootCAs = pool
+		}
+
+		kp := keyPair{certFile: certFile, keyFile: keyFile}
+		if _, err := kp.certificate(); err != nil {
+			gql.configErr = err
+			return
+		}
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return kp.certificate()
+		}
+
+		gql.transportOpts = append(gql.transportOpts, func(t *http.Transport) {
+			t.TLSClientConfig = &cfg
+		})
+	}
+}
+
+// ownTransport applies the transport settings of the options to a copy of
+// the transport of the http client, so the settings don't affect other
+// clients sharing it. It runs once the options are applied, so the order
+// of WithClient and the transport options doesn't matter. A transport that
+// isn't an *http.Transport can't be copied and is reported as an error.
+func (g *GraphQL) ownTransport() {
+	opts := g.transportOpts
+	g.transportOpts = nil
+
+	if len(opts) == 0 || g.client == nil {
+		return
+	}
+
+	client := *g.client
+
+	var base *http.Transport
+	switch rt := client.Transport.(type) {
+	case nil:
+		base = http.DefaultTransport.(*http.Transport)
+	case *http.Transport:
+		base = rt
+	default:
+		g.configErr = fmt.Errorf("graphql config error: transport options require an *http.Transport, got %T", rt)
+		return
+	}
+
+	t := base.Clone()
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	client.Transport = t
+	g.client = &client
+	g.ownedTransport = t
+}
+
+// =============================================================================
+
+// keyPair loads a certificate and key from disk, reloading them when the
+// files are modified.
+type keyPair struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// certificate returns the current certificate, reloading it if either file
+// has changed since it was loaded. If a reload fails the previously loaded
+// certificate continues to be used.
+func (kp *keyPair) certificate() (*tls.Certificate, error) {
+	kp.mu.Lock()
+	defer kp.mu.Unlock()
+
+	modTime, err := latestModTime(kp.certFile, kp.keyFile)
+	if err != nil && kp.cert == nil {
+		return nil, fmt.Errorf("graphql config error: reading client certificate: %w", err)
+	}
+
+	if kp.cert != nil && (err != nil || !modTime.After(kp.modTime)) {
+		return kp.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(kp.certFile, kp.keyFile)
+	if err != nil {
+		if kp.cert != nil {
+			return kp.cert, nil
+		}
+		return nil, fmt.Errorf("graphql config error: loading client certificate: %w", err)
+	}
+
+	kp.cert = &cert
+	kp.modTime = modTime
+
+	return kp.cert, nil
+}
+
+// latestModTime returns the most recent modification time of the files.
+func latestModTime(files ...string) (time.Time, error) {
+	var latest time.Time
+	for _, file := range files {
+		fi, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if fi.ModTime().After(latest) {
+			latest = fi.ModTime()
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,189 @@
+package graphql_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestClientCertificates(t *testing.T) {
+	t.Log("Given the need to connect to a host using mutual TLS.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the client certificate is rotated on disk.", testID)
+		{
+			dir := t.TempDir()
+			certFile := filepath.Join(dir, "client.crt")
+			keyFile := filepath.Join(dir, "client.key")
+			caFile := filepath.Join(dir, "ca.crt")
+
+			first := newClientCert(t, "first")
+			writeClientCert(t, certFile, keyFile, first)
+			second := newClientCert(t, "second")
+
+			clientCAs := x509.NewCertPool()
+			clientCAs.AddCert(first.Leaf)
+			clientCAs.AddCert(second.Leaf)
+
+			var commonName string
+			f := func(w http.ResponseWriter, r *http.Request) {
+				commonName = r.TLS.PeerCertificates[0].Subject.CommonName
+				io.WriteString(w, `{"data": {}}`)
+			}
+
+			server := httptest.NewUnstartedServer(http.HandlerFunc(f))
+			server.TLS = &tls.Config{
+				ClientAuth: tls.RequireAndVerifyClientCert,
+				ClientCAs:  clientCAs,
+			}
+			server.StartTLS()
+			defer server.Close()
+
+			writePEM(t, caFile, "CERTIFICATE", server.Certificate().Raw)
+
+			client := http.Client{
+				Transport: &http.Transport{DisableKeepAlives: true},
+			}
+			gql := graphql.New(server.URL,
+				graphql.WithClient(&client),
+				graphql.WithClientCertificates(certFile, keyFile, caFile),
+			)
+			defer gql.Close()
+
+			var resp struct{}
+			if err := gql.Execute(context.Background(), `query { __typename }`, &resp); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if commonName != "first" {
+				t.Fatalf("\t%s\tTest %d:\tShould present the first certificate: %s", failed, testID, commonName)
+			}
+			t.Logf("\t%s\tTest %d:\tShould present the first certificate.", success, testID)
+
+			writeClientCert(t, certFile, keyFile, second)
+			later := time.Now().Add(time.Minute)
+			os.Chtimes(certFile, later, later)
+			os.Chtimes(keyFile, later, later)
+
+			if err := gql.Execute(context.Background(), `query { __typename }`, &resp); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			if commonName != "second" {
+				t.Fatalf("\t%s\tTest %d:\tShould present the rotated certificate: %s", failed, testID, commonName)
+			}
+			t.Logf("\t%s\tTest %d:\tShould present the rotated certificate.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the certificate files don't exist.", testID)
+		{
+			_, err := graphql.NewE("https://localhost", graphql.WithClientCertificates("missing.crt", "missing.key", ""))
+			if !errors.Is(err, os.ErrNotExist) {
+				t.Fatalf("\t%s\tTest %d:\tShould get the error constructing the client: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the error constructing the client.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the client is provided after the TLS configuration.", testID)
+		{
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"data": {}}`)
+			}))
+			defer server.Close()
+
+			pool := x509.NewCertPool()
+			pool.AddCert(server.Certificate())
+
+			client := http.Client{
+				Transport: &http.Transport{DisableKeepAlives: true},
+			}
+			gql := graphql.New(server.URL,
+				graphql.WithTLSConfig(&tls.Config{RootCAs: pool}),
+				graphql.WithClient(&client),
+			)
+			defer gql.Close()
+
+			var resp struct{}
+			if err := gql.Execute(context.Background(), `query { __typename }`, &resp); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould keep the TLS configuration: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould keep the TLS configuration.", success, testID)
+
+			if cfg := client.Transport.(*http.Transport).TLSClientConfig; cfg != nil && cfg.RootCAs != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould leave the transport of the client unchanged.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould leave the transport of the client unchanged.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the client has a custom round tripper.", testID)
+		{
+			client := http.Client{
+				Transport: &idleCounter{RoundTripper: http.DefaultTransport},
+			}
+			_, err := graphql.NewE("https://localhost",
+				graphql.WithClient(&client),
+				graphql.WithTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}),
+			)
+			if err == nil {
+				t.Fatalf("\t%s\tTest %d:\tShould get an error constructing the client.", failed, testID)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get an error constructing the client: %v", success, testID, err)
+		}
+	}
+}
+
+// newClientCert generates a self-signed client certificate.
+func newClientCert(t *testing.T, commonName string) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+	leaf, _ := x509.ParseCertificate(der)
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+// writeClientCert writes the client certificate and key to disk.
+func writeClientCert(t *testing.T, certFile string, keyFile string, cert tls.Certificate) {
+	writePEM(t, certFile, "CERTIFICATE", cert.Certificate[0])
+	key, _ := x509.MarshalECPrivateKey(cert.PrivateKey.(*ecdsa.PrivateKey))
+	writePEM(t, keyFile, "EC PRIVATE KEY", key)
+}
+
+// writePEM writes the PEM encoded block to the file.
+func writePEM(t *testing.T, file string, blockType string, der []byte) {
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatalf("writing %s: %v", file, err)
+	}
+}
@@ -0,0 +1,127 @@
+package graphql
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// ConnTrace holds the connection level timings of a single http request.
+// Timings for steps that didn't happen, like DNS for a reused connection,
+// are zero. TimeToFirstByte and Total are measured from the start of the
+// request. Operation and Fingerprint are set for graphql documents.
+type ConnTrace struct {
+	URL             string
+	Operation       string
+	Fingerprint     string
+	Reused          bool
+	DNS             time.Duration
+	Connect         time.Duration
+	TLSHandshake    time.Duration
+	TimeToFirstByte time.Duration
+	Total           time.Duration
+}
+
+// String returns the timings in a form suitable for logging.
+func (c ConnTrace) String() string {
+	return fmt.Sprintf("%surl:[%s] reused:[%t] dns:[%s] connect:[%s] tls:[%s] ttfb:[%s] total:[%s]",
+		labels("", c.Operation), c.URL, c.Reused, c.DNS, c.Connect, c.TLSHandshake, c.TimeToFirstByte, c.Total)
+}
+
+// WithConnTrace attaches httptrace hooks to every http request and reports
+// the DNS, connect, TLS handshake, and time to first byte timings to the
+// specified function once the response headers are received. The function
+// may be nil. When logging is enabled the timings are logged as well. The
+// hooks only fire for transports built on net/http.
+func WithConnTrace(f func(ConnTrace)) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.connTrace = f
+		gql.connTraceEnabled = true
+	}
+}
+
+// connTracer records the timings of a request as the hooks fire. The hooks
+// can be called from different goroutines.
+type connTracer struct {
+	mu        sync.Mutex
+	start     time.Time
+	dnsStart  time.Time
+	connStart time.Time
+	tlsStart  time.Time
+	trace     ConnTrace
+}
+
+// withTrace returns a copy of the context with the httptrace hooks attached.
+func (ct *connTracer) withTrace(ctx context.Context) context.Context {
+	ct.start = time.Now()
+
+	trace := httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			ct.mu.Lock()
+			defer ct.mu.Unlock()
+			ct.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			ct.mu.Lock()
+			defer ct.mu.Unlock()
+			ct.trace.DNS = time.Since(ct.dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			ct.mu.Lock()
+			defer ct.mu.Unlock()
+			ct.connStart = time.Now()
+		},
+		ConnectDone: func(string, string, error) {
+			ct.mu.Lock()
+			defer ct.mu.Unlock()
+			ct.trace.Connect = time.Since(ct.connStart)
+		},
+		TLSHandshakeStart: func() {
+			ct.mu.Lock()
+			defer ct.mu.Unlock()
+			ct.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			ct.mu.Lock()
+			defer ct.mu.Unlock()
+			ct.trace.TLSHandshake = time.Since(ct.tlsStart)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			ct.mu.Lock()
+			defer ct.mu.Unlock()
+			ct.trace.Reused = info.Reused
+		},
+		GotFirstResponseByte: func() {
+			ct.mu.Lock()
+			defer ct.mu.Unlock()
+			ct.trace.TimeToFirstByte = time.Since(ct.start)
+		},
+	}
+
+	return httptrace.WithClientTrace(ctx, &trace)
+}
+
+// finish returns the timings recorded for the request.
+func (ct *connTracer) finish(url string, opts callOptions) ConnTrace {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	ct.trace.URL = url
+	ct.trace.Operation = opts.operation.String()
+	ct.trace.Fingerprint = opts.fingerprint
+	ct.trace.Total = time.Since(ct.start)
+	return ct.trace
+}
+
+// reportTrace delivers the timings to the trace function and the logger.
+func (g *GraphQL) reportTrace(ctx context.Context, trace ConnTrace) {
+	if g.connTrace != nil {
+		g.connTrace(trace)
+	}
+	if log := g.logger(ctx); log != nil {
+		log("conn_trace: " + trace.String())
+	}
+}
@@ -0,0 +1,66 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestConnTrace(t *testing.T) {
+	t.Log("Given the need to diagnose connection level latency.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen executing queries over TLS.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				io.WriteString(w, `{"data": {"__typename": "Query"}}`)
+			}
+
+			server := httptest.NewTLSServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			var traces []graphql.ConnTrace
+			var logs []string
+			gql := graphql.New(server.URL,
+				graphql.WithClient(server.Client()),
+				graphql.WithConnTrace(func(trace graphql.ConnTrace) { traces = append(traces, trace) }),
+				graphql.WithLogging(func(s string) { logs = append(logs, s) }),
+			)
+
+			for i := 0; i < 2; i++ {
+				var resp struct{}
+				if err := gql.Execute(context.Background(), `query { __typename }`, &resp); err != nil {
+					t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+				}
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the queries.", success, testID)
+
+			if len(traces) != 2 {
+				t.Fatalf("\t%s\tTest %d:\tShould get a trace for each request: %d", failed, testID, len(traces))
+			}
+			t.Logf("\t%s\tTest %d:\tShould get a trace for each request.", success, testID)
+
+			first := traces[0]
+			if first.Reused || first.Connect == 0 || first.TLSHandshake == 0 || first.TimeToFirstByte == 0 || first.URL != server.URL+"/graphql" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the timings of the new connection: %s", failed, testID, first)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the timings of the new connection.", success, testID)
+
+			second := traces[1]
+			if !second.Reused || second.TLSHandshake != 0 {
+				t.Fatalf("\t%s\tTest %d:\tShould reuse the connection: %s", failed, testID, second)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reuse the connection.", success, testID)
+
+			if len(logs) == 0 || !strings.HasPrefix(logs[0], "conn_trace: ") {
+				t.Fatalf("\t%s\tTest %d:\tShould log the timings: %v", failed, testID, logs)
+			}
+			t.Logf("\t%s\tTest %d:\tShould log the timings.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Request represents a request the client needs a transport to deliver.
+// URL is the fully qualified url for the endpoint including any query
+// parameters.
+type Request struct {
+	Method   string
+	URL      string
+	Endpoint string
+	Header   http.Header
+	Body     io.Reader
+}
+
+// Response represents the response a transport received for a request.
+// The client closes the body once it's read.
+type Response struct {
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       io.ReadCloser
+}
+
+// Transport delivers requests to a host. The default transport sends them
+// over http using the configured client. Alternative transports can be used
+// to deliver requests over other protocols, to an in-process handler, or
+// to a test double.
+type Transport interface {
+	Do(ctx context.Context, req Request) (Response, error)
+}
+
+// WithTransport replaces the http transport used to deliver requests. When
+// a transport is provided, the client set with WithClient isn't used.
+func WithTransport(transport Transport) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.transport = transport
+	}
+}
+
+// NewHTTPTransport constructs the default transport that delivers requests
+// with the specified http client. This is useful for transports that wrap
+// the default behavior.
+func NewHTTPTransport(client *http.Client) Transport {
+	return httpTransport{client: client}
+}
+
+// httpTransport delivers requests using an http client.
+type httpTransport struct {
+	client *http.Client
+}
+
+// Do implements the Transport interface.
+func (t httpTransport) Do(ctx context.Context, req Request) (Response, error) {
+	hreq, err := http.NewRequestWithContext(ctx, req.Method, req.URL, req.Body)
+	if err != nil {
+		return Response{}, fmt.Errorf("graphql create request error: %w", err)
+	}
+	hreq.Header = req.Header
+
+	resp, err := t.client.Do(hreq)
+	if err != nil {
+		return Response{}, err
+	}
+
+	return Response{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Header:     resp.Header,
+		Body:       resp.Body,
+	}, nil
+}
+
+// CloseIdleConnections closes the idle connections of the http client.
+func (t httpTransport) CloseIdleConnections() {
+	t.client.CloseIdleConnections()
+}
+
+// do delivers the request using the configured transport.
+func (g *GraphQL) do(ctx context.Context, req Request) (*Response, error) {
+	transport := g.transport
+	if transport == nil {
+		transport = httpTransport{client: g.client}
+	}
+
+	if g.signer != nil || g.bodyHash != nil {
+		if err := g.sign(ctx, &req); err != nil {
+			return nil, err
+		}
+	}
+
+	if stub := g.dryRunFor(ctx); stub != nil {
+		return stubResponse(ctx, req, stub)
+	}
+
+	if g.stats != nil {
+		ctx = g.stats.withTrace(ctx)
+	}
+
+	var tracer *connTracer
+	if g.connTraceEnabled {
+		tracer = &connTracer{}
+		ctx = tracer.withTrace(ctx)
+	}
+
+	resp, err := transport.Do(ctx, req)
+	if tracer != nil {
+		g.reportTrace(ctx, tracer.finish(req.URL, callOpts(ctx)))
+	}
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+
+	return &resp, nil
+}
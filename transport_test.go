@@ -0,0 +1,69 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+// fakeTransport records the request and returns a canned response.
+type fakeTransport struct {
+	req  graphql.Request
+	body string
+}
+
+func (ft *fakeTransport) Do(ctx context.Context, req graphql.Request) (graphql.Response, error) {
+	ft.req = req
+	b, _ := ioutil.ReadAll(req.Body)
+	ft.body = string(b)
+
+	resp := graphql.Response{
+		StatusCode: http.StatusOK,
+		Status:     "200 OK",
+		Header:     make(http.Header),
+		Body:       io.NopCloser(strings.NewReader(`{"data": {"getCity": {"name": "Miami"}}}`)),
+	}
+	return resp, nil
+}
+
+func TestTransport(t *testing.T) {
+	t.Log("Given the need to deliver requests with a custom transport.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen executing a query.", testID)
+		{
+			var ft fakeTransport
+			gql := graphql.New("http://localhost:8080", graphql.WithTransport(&ft))
+
+			var response struct {
+				GetCity struct {
+					Name string `json:"name"`
+				} `json:"getCity"`
+			}
+			if err := gql.Execute(context.Background(), `query { getCity(id: "0x01") { name } }`, &response); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the query.", success, testID)
+
+			if ft.req.Method != http.MethodPost || ft.req.URL != "http://localhost:8080/graphql" || ft.req.Endpoint != "graphql" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the request: %+v", failed, testID, ft.req)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the request.", success, testID)
+
+			if ft.req.Header.Get("Content-Type") != "application/json" || !strings.Contains(ft.body, "getCity") {
+				t.Fatalf("\t%s\tTest %d:\tShould get the headers and body: %s", failed, testID, ft.body)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the headers and body.", success, testID)
+
+			if response.GetCity.Name != "Miami" {
+				t.Fatalf("\t%s\tTest %d:\tShould decode the response: %+v", failed, testID, response)
+			}
+			t.Logf("\t%s\tTest %d:\tShould decode the response.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// UpsertMutation represents a conditional mutation within an upsert block.
+// Set and Delete are JSON mutations and SetNQuads and DelNQuads are RDF
+// mutations. JSON and RDF mutations can't be mixed in a single upsert. Use
+// the uid(v) and val(v) functions to reference variables extracted by the
+// query blocks.
+type UpsertMutation struct {
+	Cond      string
+	Set       interface{}
+	Delete    interface{}
+	SetNQuads string
+	DelNQuads string
+}
+
+// Upsert builds a Dgraph upsert block made up of query blocks that extract
+// variables and the mutations that use them.
+type Upsert struct {
+	blocks    []string
+	mutations []UpsertMutation
+	commitNow bool
+}
+
+// NewUpsert constructs an empty upsert block.
+func NewUpsert() *Upsert {
+	return &Upsert{}
+}
+
+// Block adds a query block with the specified name and root function that
+// extracts the specified variables. For example:
+//
+//	Block("user", `eq(email, "bill@ardanlabs.com")`, "u as uid", "n as name")
+//
+// produces:
+//
+//	user(func: eq(email, "bill@ardanlabs.com")) { u as uid n as name }
+func (u *Upsert) Block(name string, fn string, vars ...string) *Upsert {
+	u.blocks = append(u.blocks, fmt.Sprintf("%s(func: %s) { %s }", name, fn, strings.Join(vars, " ")))
+	return u
+}
+
+// Query adds a raw DQL query block for blocks that need filters or nested
+// extraction.
+func (u *Upsert) Query(block string) *Upsert {
+	u.blocks = append(u.blocks, strings.TrimSpace(block))
+	return u
+}
+
+// Mutation adds a mutation to the upsert block.
+func (u *Upsert) Mutation(m UpsertMutation) *Upsert {
+	u.mutations = append(u.mutations, m)
+	return u
+}
+
+// CommitNow commits the upsert immediately.
+func (u *Upsert) CommitNow() *Upsert {
+	u.commitNow = true
+	return u
+}
+
+// If produces an @if condition for the specified expression.
+func If(expr string) string {
+	return "@if(" + expr + ")"
+}
+
+// IfNotExists produces a condition that is true when the variable didn't
+// match any nodes.
+func IfNotExists(v string) string {
+	return If(fmt.Sprintf("eq(len(%s), 0)", v))
+}
+
+// IfExists produces a condition that is true when the variable matched at
+// least one node.
+func IfExists(v string) string {
+	return If(fmt.Sprintf("gt(len(%s), 0)", v))
+}
+
+// UIDVar produces a reference to the uids held by the variable for use in
+// a JSON or RDF mutation.
+func UIDVar(v string) string {
+	return "uid(" + v + ")"
+}
+
+// ValVar produces a reference to the values held by the variable for use
+// in a JSON or RDF mutation.
+func ValVar(v string) string {
+	return "val(" + v + ")"
+}
+
+// query renders the query blocks.
+func (u *Upsert) query() string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, block := range u.blocks {
+		fmt.Fprintf(&b, "\t%s\n", block)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// encode renders the upsert block in JSON or RDF format and returns the
+// content type for the format.
+func (u *Upsert) encode() (*bytes.Buffer, string, error) {
+	var rdf, js bool
+	for _, m := range u.mutations {
+		rdf = rdf || m.SetNQuads != "" || m.DelNQuads != ""
+		js = js || m.Set != nil || m.Delete != nil
+	}
+
+	if rdf && js {
+		return nil, "", fmt.Errorf("graphql upsert error: JSON and RDF mutations can't be mixed")
+	}
+
+	var b bytes.Buffer
+
+	if rdf {
+		b.WriteString("upsert {\n\tquery ")
+		b.WriteString(strings.ReplaceAll(u.query(), "\n", "\n\t"))
+		b.WriteString("\n")
+		for _, m := range u.mutations {
+			b.WriteString("\tmutation ")
+			if m.Cond != "" {
+				b.WriteString(m.Cond + " ")
+			}
+			b.WriteString("{\n")
+			if m.SetNQuads != "" {
+				fmt.Fprintf(&b, "\t\tset {\n%s\n\t\t}\n", m.SetNQuads)
+			}
+			if m.DelNQuads != "" {
+				fmt.Fprintf(&b, "\t\tdelete {\n%s\n\t\t}\n", m.DelNQuads)
+			}
+			b.WriteString("\t}\n")
+		}
+		b.WriteString("}\n")
+		return &b, "application/rdf", nil
+	}
+
+	type mutation struct {
+		Cond   string      `json:"cond,omitempty"`
+		Set    interface{} `json:"set,omitempty"`
+		Delete interface{} `json:"delete,omitempty"`
+	}
+
+	request := struct {
+		Query     string     `json:"query"`
+		Mutations []mutation `json:"mutations"`
+	}{
+		Query: u.query(),
+	}
+	for _, m := range u.mutations {
+		request.Mutations = append(request.Mutations, mutation{Cond: m.Cond, Set: m.Set, Delete: m.Delete})
+	}
+
+	if err := json.NewEncoder(&b).Encode(request); err != nil {
+		return nil, "", fmt.Errorf("graphql encoding error: %w", err)
+	}
+
+	return &b, "application/json", nil
+}
+
+// ExecuteUpsert performs the upsert block against the configured host on
+// the url/mutate endpoint.
+func (g *GraphQL) ExecuteUpsert(ctx context.Context, u *Upsert) (*DQLMutationResult, error) {
+	if len(u.mutations) == 0 {
+		return nil, fmt.Errorf("graphql upsert error: no mutations provided")
+	}
+
+	b, contentType, err := u.encode()
+	if err != nil {
+		return nil, err
+	}
+
+	var result DQLMutationResult
+	if err := g.mutate(ctx, contentType, u.commitNow, 0, b, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
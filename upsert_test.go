@@ -0,0 +1,94 @@
+package graphql_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpsert(t *testing.T) {
+	t.Log("Given the need to execute upsert blocks.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen executing a JSON upsert.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+
+				exp := `{"query":"{\n\tuser(func: eq(email, \"bill@ardanlabs.com\")) { u as uid }\n}","mutations":[{"cond":"@if(eq(len(u), 0))","set":{"email":"bill@ardanlabs.com","uid":"uid(u)"}}]}` + "\n"
+				if diff := cmp.Diff(string(b), exp); diff != "" {
+					t.Fatalf("\t%s\tTest %d:\tShould get the expected upsert. Diff:\n%s", failed, testID, diff)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the expected upsert.", success, testID)
+
+				io.WriteString(w, `{"data": {"code": "Success", "uids": {"uid(u)": "0x03"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			u := graphql.NewUpsert().
+				Block("user", `eq(email, "bill@ardanlabs.com")`, "u as uid").
+				Mutation(graphql.UpsertMutation{
+					Cond: graphql.IfNotExists("u"),
+					Set: map[string]interface{}{
+						"uid":   graphql.UIDVar("u"),
+						"email": "bill@ardanlabs.com",
+					},
+				}).
+				CommitNow()
+
+			result, err := gql.ExecuteUpsert(context.Background(), u)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the upsert: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the upsert.", success, testID)
+
+			if result.Code != "Success" {
+				t.Fatalf("\t%s\tTest %d:\tShould get the result: %+v", failed, testID, result)
+			}
+			t.Logf("\t%s\tTest %d:\tShould get the result.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen executing an RDF upsert.", testID)
+		{
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+
+				exp := "upsert {\n\tquery {\n\t\tuser(func: eq(email, \"bill@ardanlabs.com\")) { u as uid }\n\t}\n\tmutation @if(gt(len(u), 0)) {\n\t\tset {\nuid(u) <name> \"Bill\" .\n\t\t}\n\t}\n}\n"
+				if diff := cmp.Diff(string(b), exp); diff != "" {
+					t.Fatalf("\t%s\tTest %d:\tShould get the expected upsert. Diff:\n%s", failed, testID, diff)
+				}
+				t.Logf("\t%s\tTest %d:\tShould get the expected upsert.", success, testID)
+
+				io.WriteString(w, `{"data": {"code": "Success"}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			u := graphql.NewUpsert().
+				Block("user", `eq(email, "bill@ardanlabs.com")`, "u as uid").
+				Mutation(graphql.UpsertMutation{
+					Cond:      graphql.IfExists("u"),
+					SetNQuads: graphql.UIDVar("u") + ` <name> "Bill" .`,
+				})
+
+			if _, err := gql.ExecuteUpsert(context.Background(), u); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute the upsert: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute the upsert.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+	"github.com/vektah/gqlparser/v2/validator"
+)
+
+// Schema represents a parsed schema that documents can be validated against
+// locally before they are sent to the host.
+type Schema struct {
+	schema *ast.Schema
+}
+
+// LoadSchema parses the schema from one or more SDL documents.
+func LoadSchema(sdl ...string) (*Schema, error) {
+	sources := make([]*ast.Source, len(sdl))
+	for i, s := range sdl {
+		sources[i] = &ast.Source{Name: fmt.Sprintf("schema%d", i), Input: s}
+	}
+
+	schema, err := gqlparser.LoadSchema(sources...)
+	if err != nil {
+		return nil, fmt.Errorf("graphql schema error: %w", err)
+	}
+
+	return &Schema{schema: schema}, nil
+}
+
+// FetchSchema retrieves the schema from the host using introspection.
+func (g *GraphQL) FetchSchema(ctx context.Context) (*Schema, error) {
+	is, err := g.introspect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadSchema(is.SDL())
+}
+
+// Validate parses the document and validates it against the schema. If the
+// document is invalid a *ValidationError is returned.
+func (s *Schema) Validate(document string) error {
+	_, err := s.validateDocument(document)
+	return err
+}
+
+// validateDocument parses the document and validates it against the
+// schema, returning the validated document.
+func (s *Schema) validateDocument(document string) (*ast.QueryDocument, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: document})
+	if err != nil {
+		var gqlErr *gqlerror.Error
+		if errors.As(err, &gqlErr) {
+			return nil, newValidationError(gqlerror.List{gqlErr})
+		}
+		return nil, newValidationError(gqlerror.List{gqlerror.Wrap(err)})
+	}
+
+	if errs := validator.Validate(s.schema, doc); len(errs) > 0 {
+		return nil, newValidationError(errs)
+	}
+
+	return doc, nil
+}
+
+// =============================================================================
+
+// ValidationIssue represents a single problem found in a document.
+type ValidationIssue struct {
+	Message string
+	Rule    string
+	Line    int
+	Column  int
+}
+
+// ValidationError is returned when a document fails validation against the
+// schema before it's sent to the host.
+type ValidationError struct {
+	Issues []ValidationIssue
+}
+
+// newValidationError converts the parser errors into a ValidationError.
+func newValidationError(errs gqlerror.List) *ValidationError {
+	var ve ValidationError
+	for _, err := range errs {
+		issue := ValidationIssue{
+			Message: err.Message,
+			Rule:    err.Rule,
+		}
+		if len(err.Locations) > 0 {
+			issue.Line = err.Locations[0].Line
+			issue.Column = err.Locations[0].Column
+		}
+		ve.Issues = append(ve.Issues, issue)
+	}
+	return &ve
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		msgs[i] = fmt.Sprintf("%d:%d: %s", issue.Line, issue.Column, issue.Message)
+	}
+	return "graphql validation error: " + strings.Join(msgs, "; ")
+}
+
+// =============================================================================
+
+// WithSchema validates every document executed against the url/graphql
+// endpoint with the specified schema before it's sent to the host.
+func WithSchema(schema *Schema) func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.validation = &schemaValidation{schema: schema}
+	}
+}
+
+// WithSchemaValidation validates every document executed against the
+// url/graphql endpoint before it's sent to the host. The schema is fetched
+// from the host using introspection on first use and cached.
+func WithSchemaValidation() func(gql *GraphQL) {
+	return func(gql *GraphQL) {
+		gql.validation = &schemaValidation{}
+	}
+}
+
+// schemaValidation holds the schema used for validation, fetching it from
+// the host when it's not provided.
+type schemaValidation struct {
+	mu     sync.Mutex
+	schema *Schema
+}
+
+// load returns the schema, fetching it from the host if required. If the
+// fetch fails, it will be attempted again on the next call.
+func (sv *schemaValidation) load(ctx context.Context, g *GraphQL) (*Schema, error) {
+	sv.mu.Lock()
+	defer sv.mu.Unlock()
+
+	if sv.schema != nil {
+		return sv.schema, nil
+	}
+
+	schema, err := g.FetchSchema(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("graphql schema error: %w", err)
+	}
+	sv.schema = schema
+
+	return schema, nil
+}
+
+// validate validates the document if validation is enabled for the
+// endpoint.
+func (g *GraphQL) validate(ctx context.Context, endpoint string, graphql string) error {
+	if g.validation == nil || endpoint != g.graphqlPath {
+		return nil
+	}
+
+	schema, err := g.validation.load(ctx, g)
+	if err != nil {
+		return err
+	}
+
+	if g.deprecationWarn == nil {
+		return schema.Validate(graphql)
+	}
+
+	doc, err := schema.validateDocument(graphql)
+	if err != nil {
+		return err
+	}
+
+	if deps := schema.deprecations(doc); len(deps) > 0 {
+		g.deprecationWarn(ParseOperation(graphql), deps)
+	}
+
+	return nil
+}
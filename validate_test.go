@@ -0,0 +1,117 @@
+package graphql_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ardanlabs/graphql"
+)
+
+// introspectionResponse is a minimal introspection result for a schema with
+// a getCity query.
+const introspectionResponse = `{"data": {"__schema": {
+	"queryType": {"name": "Query"},
+	"types": [
+		{"kind": "OBJECT", "name": "Query", "fields": [
+			{"name": "getCity", "args": [
+				{"name": "id", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}}
+			], "type": {"kind": "OBJECT", "name": "City"}}
+		]},
+		{"kind": "OBJECT", "name": "City", "fields": [
+			{"name": "id", "type": {"kind": "NON_NULL", "ofType": {"kind": "SCALAR", "name": "ID"}}},
+			{"name": "name", "type": {"kind": "SCALAR", "name": "String"}},
+			{"name": "population", "type": {"kind": "SCALAR", "name": "Int"}, "isDeprecated": true, "deprecationReason": "use census"}
+		]},
+		{"kind": "SCALAR", "name": "ID"},
+		{"kind": "SCALAR", "name": "String"},
+		{"kind": "SCALAR", "name": "Int"}
+	],
+	"directives": [
+		{"name": "cascade", "locations": ["FIELD"], "args": [
+			{"name": "fields", "type": {"kind": "LIST", "ofType": {"kind": "SCALAR", "name": "String"}}}
+		]}
+	]
+}}}`
+
+func TestValidation(t *testing.T) {
+	type response struct {
+		GetCity struct {
+			Name string `json:"name"`
+		} `json:"getCity"`
+	}
+
+	t.Log("Given the need to validate documents before they are sent.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the schema is loaded from SDL.", testID)
+		{
+			schema, err := graphql.LoadSchema(`
+				type Query { getCity(id: ID!): City }
+				type City { id: ID! name: String }
+			`)
+			if err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to load the schema: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to load the schema.", success, testID)
+
+			if err := schema.Validate(`query { getCity(id: "0x01") { name } }`); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould accept a valid document: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould accept a valid document.", success, testID)
+
+			err = schema.Validate(`query { getCity(id: "0x01") { name mayor } }`)
+
+			var ve *graphql.ValidationError
+			if !errors.As(err, &ve) || len(ve.Issues) != 1 || ve.Issues[0].Line != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould reject an unknown field: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject an unknown field.", success, testID)
+		}
+
+		testID++
+		t.Logf("\tTest %d:\tWhen the schema is fetched using introspection.", testID)
+		{
+			var queries int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				b, _ := ioutil.ReadAll(r.Body)
+				if strings.Contains(string(b), "IntrospectionQuery") {
+					io.WriteString(w, introspectionResponse)
+					return
+				}
+				atomic.AddInt32(&queries, 1)
+				io.WriteString(w, `{"data": {"getCity": {"name": "Miami"}}}`)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL, graphql.WithSchemaValidation())
+
+			var got response
+			if err := gql.Execute(context.Background(), `query { getCity(id: "0x01") @cascade { name } }`, &got); err != nil {
+				t.Fatalf("\t%s\tTest %d:\tShould be able to execute a valid query: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould be able to execute a valid query.", success, testID)
+
+			err := gql.Execute(context.Background(), `query { getCity { name } }`, &got)
+
+			var ve *graphql.ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("\t%s\tTest %d:\tShould reject the missing argument: %v", failed, testID, err)
+			}
+			t.Logf("\t%s\tTest %d:\tShould reject the missing argument.", success, testID)
+
+			if n := atomic.LoadInt32(&queries); n != 1 {
+				t.Fatalf("\t%s\tTest %d:\tShould not send the invalid query: %d", failed, testID, n)
+			}
+			t.Logf("\t%s\tTest %d:\tShould not send the invalid query.", success, testID)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"math/rand"
+	"time"
+)
+
+// WatchConfig holds the settings for a watch.
+type WatchConfig struct {
+	jitter    time.Duration
+	variables []func(m map[string]interface{})
+}
+
+// WithWatchJitter adds a random delay of up to the specified duration to
+// every interval, so many watchers don't poll the host in lockstep.
+func WithWatchJitter(jitter time.Duration) func(wc *WatchConfig) {
+	return func(wc *WatchConfig) {
+		wc.jitter = jitter
+	}
+}
+
+// WithWatchVariables sets the variables of the query.
+func WithWatchVariables(variables ...func(m map[string]interface{})) func(wc *WatchConfig) {
+	return func(wc *WatchConfig) {
+		wc.variables = append(wc.variables, variables...)
+	}
+}
+
+// WatchEvent represents an update of a watched query. Data is the decoded
+// result and Raw the data as received. Changes holds the differences from
+// the previous result and is empty for the first one. Err is set when the
+// query failed, in which case Data and Raw hold the last result.
+type WatchEvent[T any] struct {
+	Data    T
+	Raw     json.RawMessage
+	Changes []Change
+	Err     error
+}
+
+// Watch emulates a live query for hosts without subscriptions. The query
+// is executed against the url/graphql endpoint right away and then at every
+// interval, and an event is delivered each time the result changes or the
+// query fails. Results are never read from the cache. The channel is
+// closed when the context is canceled.
+func Watch[T any](ctx context.Context, gql *GraphQL, graphql string, interval time.Duration, options ...func(wc *WatchConfig)) <-chan WatchEvent[T] {
+	var wc WatchConfig
+	for _, option := range options {
+		option(&wc)
+	}
+
+	events := make(chan WatchEvent[T])
+	hooks := gql.decodeHooksFor(ctx)
+
+	go func() {
+		defer close(events)
+
+		var last WatchEvent[T]
+		var lastHash []byte
+
+		timer := time.NewTimer(0)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				return
+			}
+
+			event := last
+			event.Changes = nil
+
+			var raw json.RawMessage
+			err := gql.Execute(NoCache(ctx), graphql, &raw, wc.variables...)
+			if err == nil {
+				hash := sha256.Sum256(raw)
+				if bytes.Equal(hash[:], lastHash) {
+					timer.Reset(watchDelay(interval, wc.jitter))
+					continue
+				}
+
+				var changes []Change
+				if lastHash != nil {
+					changes, err = Diff(last.Raw, raw)
+				}
+
+				var data T
+				if err == nil {
+					err = decodeData(raw, &data, hooks, gql.errorBodyLimit)
+				}
+
+				if err == nil {
+					lastHash = hash[:]
+					event = WatchEvent[T]{Data: data, Raw: raw, Changes: changes}
+					last = event
+				}
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				event.Err = err
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+
+			timer.Reset(watchDelay(interval, wc.jitter))
+		}
+	}()
+
+	return events
+}
+
+// watchDelay returns the interval with a random jitter added.
+func watchDelay(interval time.Duration, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	return interval + time.Duration(rand.Int63n(int64(jitter)))
+}
@@ -0,0 +1,81 @@
+package graphql_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ardanlabs/graphql"
+)
+
+func TestWatch(t *testing.T) {
+	t.Log("Given the need to watch the result of a query for changes.")
+	{
+		testID := 0
+		t.Logf("\tTest %d:\tWhen the result changes between polls.", testID)
+		{
+			// The population changes on the third and fifth polls and the
+			// fourth poll fails.
+			var polls int32
+			f := func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&polls, 1)
+				switch {
+				case n == 4:
+					w.WriteHeader(http.StatusServiceUnavailable)
+				case n < 3:
+					io.WriteString(w, `{"data": {"getCity": {"population": 100}}}`)
+				default:
+					io.WriteString(w, fmt.Sprintf(`{"data": {"getCity": {"population": %d}}}`, 100+n/5*100+n/3*50))
+				}
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(f))
+			defer server.Close()
+
+			gql := graphql.New(server.URL)
+
+			type city struct {
+				GetCity struct {
+					Population int `json:"population"`
+				} `json:"getCity"`
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events := graphql.Watch[city](ctx, gql, `query($name: String!) { getCity(name: $name) { population } }`, 5*time.Millisecond,
+				graphql.WithWatchJitter(time.Millisecond),
+				graphql.WithWatchVariables(graphql.WithVariable("name", "Miami")),
+			)
+
+			var got []string
+			var changes []string
+			for event := range events {
+				changes = append(changes, fmt.Sprint(event.Changes))
+				if event.Err != nil {
+					got = append(got, fmt.Sprintf("error:%d", event.Data.GetCity.Population))
+				} else {
+					got = append(got, fmt.Sprint(event.Data.GetCity.Population))
+				}
+				if len(got) == 4 {
+					cancel()
+				}
+			}
+
+			if exp := "[100 150 error:150 250]"; fmt.Sprint(got) != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould deliver the changes and failures: got %v, exp %s", failed, testID, got, exp)
+			}
+			t.Logf("\t%s\tTest %d:\tShould deliver the changes and failures.", success, testID)
+
+			if exp := "[[] [modified getCity.population: 100 -> 150] [] [modified getCity.population: 150 -> 250]]"; fmt.Sprint(changes) != exp {
+				t.Fatalf("\t%s\tTest %d:\tShould report what changed: got %v, exp %s", failed, testID, changes, exp)
+			}
+			t.Logf("\t%s\tTest %d:\tShould report what changed.", success, testID)
+		}
+	}
+}